@@ -0,0 +1,54 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"krizzy/internal/config"
+	"krizzy/internal/database"
+	"krizzy/internal/repository"
+	"krizzy/internal/services"
+)
+
+// runCheck checks the local database and every file and Postgres board for
+// position gaps, dangling references and similar problems, and with --repair
+// fixes them: krizzy check [--repair]. It exits with status 1 when a problem
+// is left or a database could not be checked.
+func runCheck(cfg *config.Config, args []string) {
+	repair := false
+	for _, arg := range args {
+		if arg != "--repair" {
+			log.Fatalf("usage: krizzy check [--repair]")
+		}
+		repair = true
+	}
+
+	db, err := database.NewSQLite(cfg.DatabasePath, cfg.SQLite)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	if err := db.Migrate(); err != nil {
+		db.Close()
+		log.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	boardRepo := repository.NewSQLiteBoardRepository(db.ReadWrite())
+	pgConnRepo := repository.NewSQLitePgConnectionRepository(db.DB())
+	bm := services.NewBoardManager(db, boardRepo, pgConnRepo, cfg.BoardFilesDir)
+
+	report := bm.CheckIntegrity(repair)
+	report.WriteText(os.Stdout)
+
+	bm.Close()
+	db.Close()
+
+	failed := false
+	for _, checked := range report.Databases {
+		if checked.Err != nil || !repair && len(checked.Issues) > 0 {
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -1,9 +1,11 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 
@@ -22,26 +24,71 @@ func main() {
 	// Load configuration
 	cfg := config.Load()
 
-	// Initialize database
-	db, err := database.NewSQLite(cfg.DatabasePath)
-	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+	if len(os.Args) > 1 && os.Args[1] == "restore" {
+		runRestore(cfg, os.Args[2:])
+		return
 	}
-	defer db.Close()
-
-	// Run migrations
-	if err := db.Migrate(); err != nil {
-		log.Fatalf("Failed to run migrations: %v", err)
+	if len(os.Args) > 1 && os.Args[1] == "check" {
+		runCheck(cfg, os.Args[2:])
+		return
 	}
 
-	// Initialize repositories (always local SQLite for metadata)
-	boardRepo := repository.NewSQLiteBoardRepository(db.DB())
-	pgConnRepo := repository.NewSQLitePgConnectionRepository(db.DB())
+	// Demo mode keeps everything in memory and never touches the disk
+	demo := len(os.Args) > 1 && os.Args[1] == "--demo"
+
+	var bm *services.BoardManager
+	var store *repository.MemoryStore
+	var backups *services.BackupService
+	if demo {
+		store = repository.NewMemoryStore()
+		bm = services.NewMemoryBoardManager(store)
+	} else {
+		// Initialize database
+		db, err := database.NewSQLite(cfg.DatabasePath, cfg.SQLite)
+		if err != nil {
+			log.Fatalf("Failed to connect to database: %v", err)
+		}
+		defer db.Close()
+
+		// Run migrations
+		if err := db.Migrate(); err != nil {
+			log.Fatalf("Failed to run migrations: %v", err)
+		}
+
+		// Initialize repositories (always local SQLite for metadata)
+		boardRepo := repository.NewSQLiteBoardRepository(db.ReadWrite())
+		pgConnRepo := repository.NewSQLitePgConnectionRepository(db.DB())
+
+		// Initialize BoardManager
+		bm = services.NewBoardManager(db, boardRepo, pgConnRepo, cfg.BoardFilesDir)
 
-	// Initialize BoardManager
-	bm := services.NewBoardManager(db, boardRepo, pgConnRepo)
+		backups = services.NewBackupService(db, cfg.BackupDir, cfg.BackupInterval, cfg.BackupRetention)
+		backups.Start()
+		defer backups.Close()
+	}
 	defer bm.Close()
-	eventHub := services.NewBoardEventHub()
+	bm.StartHealthChecks(cfg.HealthCheckInterval)
+	var eventHub services.BoardEventHub = services.NewMemoryEventHub()
+	if cfg.EventsPgDSN != "" {
+		pgHub, err := services.NewPgEventHub(cfg.EventsPgDSN)
+		if err != nil {
+			log.Fatalf("Failed to start Postgres event hub: %v", err)
+		}
+		eventHub = pgHub
+	}
+	defer eventHub.Close()
+
+	if demo {
+		demoService := services.NewDemoService(bm, store, eventHub, cfg.DemoResetInterval)
+		if err := demoService.Start(); err != nil {
+			log.Fatalf("Failed to seed demo boards: %v", err)
+		}
+		defer demoService.Close()
+	}
+
+	recurrences := services.NewRecurrenceScheduler(bm, eventHub, cfg.RecurrenceInterval)
+	recurrences.Start()
+	defer recurrences.Close()
 
 	// Initialize handlers
 	boardHandler := handlers.NewBoardHandler(bm)
@@ -50,9 +97,15 @@ func main() {
 	modalHandler := handlers.NewModalHandler(bm)
 	personHandler := handlers.NewPersonHandler(bm, eventHub)
 	commentHandler := handlers.NewCommentHandler(bm, eventHub)
+	linkHandler := handlers.NewLinkHandler(bm, eventHub)
 	checklistHandler := handlers.NewChecklistHandler(bm, eventHub)
+	swimlaneHandler := handlers.NewSwimlaneHandler(bm, eventHub)
+	fieldHandler := handlers.NewFieldHandler(bm, eventHub)
+	templateHandler := handlers.NewCardTemplateHandler(bm, eventHub)
+	recurrenceHandler := handlers.NewRecurrenceHandler(bm)
 	connectionHandler := handlers.NewConnectionHandler(bm)
 	realtimeHandler := handlers.NewRealtimeHandler(bm, eventHub)
+	apiHandler := handlers.NewAPIHandler(bm)
 
 	// Initialize Echo
 	e := echo.New()
@@ -72,12 +125,21 @@ func main() {
 	e.GET("/", boardHandler.ListBoards)
 	e.GET("/boards/import-modal", boardHandler.GetImportModal)
 	e.POST("/boards", boardHandler.CreateBoard)
-	e.POST("/boards/import-trello", boardHandler.ImportTrelloBoard)
+	e.POST("/boards/import", boardHandler.ImportBoard)
+	e.POST("/boards/import/preview", boardHandler.PreviewImport)
+	e.GET("/imports/:id/events", boardHandler.StreamImport)
+	e.POST("/imports/:id/cancel", boardHandler.CancelImport)
 	e.GET("/boards/:id", boardHandler.GetBoard)
+	e.POST("/boards/:id/reconnect", boardHandler.ReconnectBoard)
+	e.GET("/boards/:id/clone-modal", boardHandler.GetCloneModal)
+	e.POST("/boards/:id/clone", boardHandler.CloneBoard)
+	e.GET("/boards/:id/export", boardHandler.ExportBoard)
 	e.GET("/boards/:id/events", realtimeHandler.StreamBoardEvents)
 	e.GET("/boards/:id/columns", realtimeHandler.GetColumnsContainer)
 	e.GET("/boards/:id/columns/:columnId", realtimeHandler.GetColumn)
 	e.GET("/boards/:id/cards/:cardId", realtimeHandler.GetCard)
+	e.GET("/api/boards/:id/cards", apiHandler.ListCards)
+	e.GET("/api/boards/:id/cards/:cardId", apiHandler.GetCard)
 	e.PUT("/boards/:id", boardHandler.RenameBoard)
 	e.DELETE("/boards/:id", boardHandler.DeleteBoard)
 
@@ -89,6 +151,9 @@ func main() {
 		}
 		svc, err := bm.GetServiceForBoard(boardID)
 		if err != nil {
+			if errors.Is(err, services.ErrBoardUnavailable) {
+				return c.String(http.StatusServiceUnavailable, "Board storage unavailable, please retry shortly")
+			}
 			return c.String(http.StatusNotFound, "Board not found")
 		}
 		people, err := svc.PersonRepo.GetByBoardID(boardID)
@@ -104,18 +169,55 @@ func main() {
 	e.DELETE("/columns/:id", columnHandler.DeleteColumn)
 	e.POST("/columns/reorder", columnHandler.ReorderColumns)
 
+	// Swimlane routes
+	e.POST("/swimlanes", swimlaneHandler.CreateSwimlane)
+	e.PUT("/swimlanes/:id", swimlaneHandler.UpdateSwimlane)
+	e.POST("/swimlanes/:id/toggle", swimlaneHandler.ToggleSwimlane)
+	e.DELETE("/swimlanes/:id", swimlaneHandler.DeleteSwimlane)
+	e.PUT("/boards/:id/lane-grouping", swimlaneHandler.SetLaneGrouping)
+
+	// Custom field routes
+	e.GET("/boards/:id/fields", fieldHandler.GetFieldsModal)
+	e.POST("/fields", fieldHandler.CreateField)
+	e.PUT("/fields/:id", fieldHandler.UpdateField)
+	e.DELETE("/fields/:id", fieldHandler.DeleteField)
+	e.POST("/cards/:id/fields/:fieldId", fieldHandler.SetCardField)
+
+	// Card template routes
+	e.GET("/boards/:id/templates", templateHandler.GetTemplatesModal)
+	e.POST("/cards/:id/template", templateHandler.SaveCardTemplate)
+	e.POST("/cards/from-template", templateHandler.CreateCardFromTemplate)
+	e.DELETE("/card-templates/:id", templateHandler.DeleteTemplate)
+
+	// Recurring card routes
+	e.GET("/boards/:id/recurrences", recurrenceHandler.GetRecurrencesModal)
+	e.POST("/recurrences", recurrenceHandler.CreateRecurrence)
+	e.POST("/recurrences/:id/toggle", recurrenceHandler.ToggleRecurrence)
+	e.DELETE("/recurrences/:id", recurrenceHandler.DeleteRecurrence)
+
 	// Card routes
 	e.POST("/cards", cardHandler.CreateCard)
 	e.GET("/cards/:id/modal", modalHandler.GetCardModal)
 	e.PUT("/cards/:id", cardHandler.UpdateCard)
 	e.DELETE("/cards/:id", cardHandler.DeleteCard)
 	e.POST("/cards/:id/move", cardHandler.MoveCard)
+	e.POST("/cards/:id/duplicate", cardHandler.DuplicateCard)
+	e.GET("/cards/:id/move-board", cardHandler.GetMoveBoardTargets)
+	e.GET("/cards/:id/move-board/columns", cardHandler.GetMoveBoardColumns)
+	e.POST("/cards/:id/move-board", cardHandler.MoveCardToBoard)
 	e.POST("/cards/:id/assignees", cardHandler.UpdateAssignees)
+	e.POST("/cards/:id/archive", cardHandler.ArchiveCard)
+	e.POST("/cards/:id/restore", cardHandler.RestoreCard)
+	e.GET("/boards/:id/archived", cardHandler.GetArchivedCards)
 
 	// Comment routes
 	e.POST("/cards/:id/comments", commentHandler.CreateComment)
 	e.DELETE("/comments/:id", commentHandler.DeleteComment)
 
+	// Link routes
+	e.POST("/cards/:id/links", linkHandler.CreateLink)
+	e.DELETE("/links/:id", linkHandler.DeleteLink)
+
 	// Checklist routes
 	e.POST("/cards/:id/checklist", checklistHandler.CreateItem)
 	e.PUT("/checklist/:id", checklistHandler.UpdateItem)
@@ -130,14 +232,29 @@ func main() {
 	// Connection routes
 	e.GET("/connections", connectionHandler.ListConnections)
 	e.POST("/connections", connectionHandler.CreateConnection)
+	e.GET("/connections/:id/edit", connectionHandler.GetEditForm)
+	e.PUT("/connections/:id", connectionHandler.UpdateConnection)
 	e.POST("/connections/:id/test", connectionHandler.TestConnection)
 	e.DELETE("/connections/:id", connectionHandler.DeleteConnection)
 
+	// Admin routes; a demo has no database to back up or check
+	if backups != nil {
+		adminHandler := handlers.NewAdminHandler(backups, bm)
+		e.POST("/admin/backup", adminHandler.CreateBackup)
+		e.GET("/admin/integrity", adminHandler.CheckIntegrity)
+		e.POST("/admin/integrity/repair", adminHandler.RepairIntegrity)
+	}
+
 	// Start server
 	addr := cfg.ServerAddress
 	if strings.HasPrefix(addr, ":") {
 		addr = fmt.Sprintf("http://localhost%s", addr)
 	}
+	if demo && cfg.DemoResetInterval > 0 {
+		log.Printf("Demo mode: boards are kept in memory and reset every %s", cfg.DemoResetInterval)
+	} else if demo {
+		log.Printf("Demo mode: boards are kept in memory")
+	}
 	log.Printf("Starting Krizzy on %s", addr)
 	log.Fatal(e.Start(cfg.ServerAddress))
 }
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"krizzy/internal/config"
+	"krizzy/internal/database"
+)
+
+// runRestore swaps a backup in as the local database. It is meant to be run
+// with the server stopped: krizzy restore <backup-file>
+func runRestore(cfg *config.Config, args []string) {
+	if len(args) != 1 {
+		log.Fatalf("usage: krizzy restore <backup-file>")
+	}
+
+	previous, err := database.RestoreSQLite(args[0], cfg.DatabasePath, cfg.SQLite)
+	if err != nil {
+		log.Fatalf("Restore failed: %v", err)
+	}
+
+	fmt.Printf("Restored %s into %s\n", args[0], cfg.DatabasePath)
+	if previous != "" {
+		fmt.Printf("Previous database kept at %s\n", previous)
+	}
+}
@@ -2,17 +2,37 @@ package config
 
 import (
 	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"krizzy/internal/database"
 )
 
 type Config struct {
-	ServerAddress string
-	DatabasePath  string
+	ServerAddress       string
+	DatabasePath        string
+	HealthCheckInterval time.Duration
+	BackupDir           string
+	BoardFilesDir       string
+	BackupInterval      time.Duration
+	BackupRetention     int
+	RecurrenceInterval  time.Duration
+	EventsPgDSN         string
+	DemoResetInterval   time.Duration
+	SQLite              database.SQLiteOptions
 }
 
 func Load() *Config {
 	cfg := &Config{
-		ServerAddress: ":8080",
-		DatabasePath:  "krizzy.db",
+		ServerAddress:       ":8080",
+		DatabasePath:        "krizzy.db",
+		HealthCheckInterval: 30 * time.Second,
+		BackupInterval:      24 * time.Hour,
+		BackupRetention:     7,
+		RecurrenceInterval:  time.Minute,
+		DemoResetInterval:   time.Hour,
+		SQLite:              database.DefaultSQLiteOptions(),
 	}
 
 	if addr := os.Getenv("SERVER_ADDRESS"); addr != "" {
@@ -21,6 +41,70 @@ func Load() *Config {
 	if dbPath := os.Getenv("DATABASE_PATH"); dbPath != "" {
 		cfg.DatabasePath = dbPath
 	}
+	if interval := os.Getenv("PG_HEALTH_CHECK_INTERVAL"); interval != "" {
+		if d, err := time.ParseDuration(interval); err == nil {
+			cfg.HealthCheckInterval = d
+		}
+	}
+	if interval := os.Getenv("BACKUP_INTERVAL"); interval != "" {
+		if d, err := time.ParseDuration(interval); err == nil {
+			cfg.BackupInterval = d
+		}
+	}
+	if retention := os.Getenv("BACKUP_RETENTION"); retention != "" {
+		if n, err := strconv.Atoi(retention); err == nil {
+			cfg.BackupRetention = n
+		}
+	}
+	if interval := os.Getenv("RECURRENCE_INTERVAL"); interval != "" {
+		if d, err := time.ParseDuration(interval); err == nil {
+			cfg.RecurrenceInterval = d
+		}
+	}
+	if interval := os.Getenv("DEMO_RESET_INTERVAL"); interval != "" {
+		if d, err := time.ParseDuration(interval); err == nil {
+			cfg.DemoResetInterval = d
+		}
+	}
+
+	// The local database and board files are opened with the same settings
+	if mode := os.Getenv("SQLITE_JOURNAL_MODE"); mode != "" {
+		cfg.SQLite.JournalMode = mode
+	}
+	if level := os.Getenv("SQLITE_SYNCHRONOUS"); level != "" {
+		cfg.SQLite.Synchronous = level
+	}
+	if size := os.Getenv("SQLITE_CACHE_SIZE"); size != "" {
+		if n, err := strconv.Atoi(size); err == nil {
+			cfg.SQLite.CacheSize = n
+		}
+	}
+	if timeout := os.Getenv("SQLITE_BUSY_TIMEOUT"); timeout != "" {
+		if d, err := time.ParseDuration(timeout); err == nil {
+			cfg.SQLite.BusyTimeout = d
+		}
+	}
+	if readers := os.Getenv("SQLITE_READERS"); readers != "" {
+		if n, err := strconv.Atoi(readers); err == nil && n > 0 {
+			cfg.SQLite.Readers = n
+		}
+	}
+
+	// Board events stay in memory unless instances share them through Postgres
+	cfg.EventsPgDSN = os.Getenv("PG_EVENTS_DSN")
+
+	// Backups default to a directory next to the database so they land on
+	// the same volume in container deployments
+	cfg.BackupDir = filepath.Join(filepath.Dir(cfg.DatabasePath), "backups")
+	if dir := os.Getenv("BACKUP_DIR"); dir != "" {
+		cfg.BackupDir = dir
+	}
+
+	// Boards stored in their own SQLite file live next to the database too
+	cfg.BoardFilesDir = filepath.Join(filepath.Dir(cfg.DatabasePath), "boards")
+	if dir := os.Getenv("BOARD_FILES_DIR"); dir != "" {
+		cfg.BoardFilesDir = dir
+	}
 
 	return cfg
 }
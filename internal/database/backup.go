@@ -0,0 +1,199 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// Backup writes a consistent copy of the database to destPath using SQLite's
+// online backup API, so it is safe to call while the server is handling writes.
+// It copies from a reader, which leaves the writer free. An existing file at
+// destPath is never overwritten.
+func (s *SQLiteDB) Backup(ctx context.Context, destPath string) error {
+	if err := checkBackupFree(destPath); err != nil {
+		return err
+	}
+
+	tmpPath := destPath + ".tmp"
+	os.Remove(tmpPath)
+
+	destDB, err := sql.Open("sqlite3", tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to open backup file: %w", err)
+	}
+
+	err = copySQLite(ctx, s.reader, destDB)
+	if closeErr := destDB.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	// Checked again, since the copy can take a while
+	if err := checkBackupFree(destPath); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, destPath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to finalize backup: %w", err)
+	}
+	return nil
+}
+
+func checkBackupFree(destPath string) error {
+	if _, err := os.Stat(destPath); err == nil {
+		return fmt.Errorf("backup %s: %w", destPath, os.ErrExist)
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to check backup file: %w", err)
+	}
+	return nil
+}
+
+func copySQLite(ctx context.Context, src, dest *sql.DB) error {
+	srcConn, err := src.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire source connection: %w", err)
+	}
+	defer srcConn.Close()
+
+	destConn, err := dest.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire backup connection: %w", err)
+	}
+	defer destConn.Close()
+
+	return destConn.Raw(func(destRaw any) error {
+		return srcConn.Raw(func(srcRaw any) error {
+			destSQLite, ok := destRaw.(*sqlite3.SQLiteConn)
+			if !ok {
+				return fmt.Errorf("backup target is not a sqlite connection")
+			}
+			srcSQLite, ok := srcRaw.(*sqlite3.SQLiteConn)
+			if !ok {
+				return fmt.Errorf("backup source is not a sqlite connection")
+			}
+
+			backup, err := destSQLite.Backup("main", srcSQLite, "main")
+			if err != nil {
+				return fmt.Errorf("failed to start backup: %w", err)
+			}
+			if _, err := backup.Step(-1); err != nil {
+				backup.Finish()
+				return fmt.Errorf("failed to copy pages: %w", err)
+			}
+			return backup.Finish()
+		})
+	})
+}
+
+// ValidateSQLiteFile checks that path is an intact SQLite database that looks
+// like a Krizzy database.
+func ValidateSQLiteFile(path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+
+	db, err := sql.Open("sqlite3", "file:"+path+"?mode=ro")
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer db.Close()
+
+	var result string
+	if err := db.QueryRow("PRAGMA integrity_check").Scan(&result); err != nil {
+		return fmt.Errorf("%s is not a readable SQLite database: %w", path, err)
+	}
+	if result != "ok" {
+		return fmt.Errorf("integrity check failed for %s: %s", path, result)
+	}
+
+	var tables int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name IN ('boards', 'columns', 'cards')").Scan(&tables); err != nil {
+		return fmt.Errorf("failed to inspect %s: %w", path, err)
+	}
+	if tables != 3 {
+		return fmt.Errorf("%s does not contain a Krizzy database", path)
+	}
+
+	return nil
+}
+
+// RestoreSQLite replaces the database at dbPath with the backup at backupPath.
+// The backup is validated and migrated on a scratch copy first, and the old
+// database is kept next to it. The server must not be running.
+func RestoreSQLite(backupPath, dbPath string, opts SQLiteOptions) (string, error) {
+	if err := ValidateSQLiteFile(backupPath); err != nil {
+		return "", err
+	}
+
+	stagingPath := dbPath + ".restore"
+	if err := copyFile(backupPath, stagingPath); err != nil {
+		return "", fmt.Errorf("failed to stage backup: %w", err)
+	}
+
+	staged, err := NewSQLite(stagingPath, opts)
+	if err != nil {
+		os.Remove(stagingPath)
+		return "", err
+	}
+	err = staged.Migrate()
+	if closeErr := staged.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(stagingPath)
+		return "", fmt.Errorf("failed to migrate backup: %w", err)
+	}
+
+	previousPath := ""
+	if _, err := os.Stat(dbPath); err == nil {
+		previousPath = fmt.Sprintf("%s.pre-restore-%s", dbPath, time.Now().Format("20060102-150405"))
+		if err := os.Rename(dbPath, previousPath); err != nil {
+			os.Remove(stagingPath)
+			return "", fmt.Errorf("failed to move current database aside: %w", err)
+		}
+		// Journal files belong to the old database; left in place they would
+		// be replayed against the restored one
+		for _, suffix := range []string{"-wal", "-shm", "-journal"} {
+			if _, err := os.Stat(dbPath + suffix); err == nil {
+				os.Rename(dbPath+suffix, previousPath+suffix)
+			}
+		}
+	}
+
+	if err := os.Rename(stagingPath, dbPath); err != nil {
+		return previousPath, fmt.Errorf("failed to swap in restored database: %w", err)
+	}
+	return previousPath, nil
+}
+
+func copyFile(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,7 @@
+-- Done columns carry a resolution label ("Done", "Won't do", ...) that is
+-- copied onto cards when they are completed there
+ALTER TABLE columns ADD COLUMN resolution TEXT NOT NULL DEFAULT '';
+ALTER TABLE cards ADD COLUMN resolution TEXT NOT NULL DEFAULT '';
+
+UPDATE columns SET resolution = 'Done' WHERE is_done_column = 1;
+UPDATE cards SET resolution = 'Done' WHERE completed_at IS NOT NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE boards DROP COLUMN lane_grouping;
+DROP INDEX idx_cards_swimlane_id;
+ALTER TABLE cards DROP COLUMN swimlane_id;
+DROP TABLE swimlanes;
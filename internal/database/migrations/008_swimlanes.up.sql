@@ -0,0 +1,19 @@
+-- Swimlanes are horizontal rows that cross every column of a board
+CREATE TABLE swimlanes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    board_id INTEGER NOT NULL REFERENCES boards(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    collapsed BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_swimlanes_board_id ON swimlanes(board_id);
+
+-- Card positions are ordered within a (column, swimlane) cell. Cards without
+-- a swimlane sit in the board's unlaned row.
+ALTER TABLE cards ADD COLUMN swimlane_id INTEGER;
+CREATE INDEX idx_cards_swimlane_id ON cards(swimlane_id);
+
+-- '' shows stored swimlanes, 'assignee' groups rows by assignee instead
+ALTER TABLE boards ADD COLUMN lane_grouping TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,22 @@
+-- Board-defined card attributes such as story points or release
+CREATE TABLE custom_fields (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    board_id INTEGER NOT NULL REFERENCES boards(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    field_type TEXT NOT NULL,
+    options TEXT NOT NULL DEFAULT '[]', -- JSON array of choices for select fields
+    position INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_custom_fields_board_id ON custom_fields(board_id);
+
+-- One row per card and field; fields a card has no value for have no row
+CREATE TABLE card_field_values (
+    card_id INTEGER NOT NULL REFERENCES cards(id) ON DELETE CASCADE,
+    field_id INTEGER NOT NULL REFERENCES custom_fields(id) ON DELETE CASCADE,
+    value TEXT NOT NULL,
+    PRIMARY KEY (card_id, field_id)
+);
+
+CREATE INDEX idx_card_field_values_field_id ON card_field_values(field_id);
@@ -0,0 +1,15 @@
+-- Reusable card blueprints. Checklist items, assignees and custom field
+-- values are kept as JSON since they are only ever read back as a whole.
+CREATE TABLE card_templates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    board_id INTEGER NOT NULL REFERENCES boards(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    checklist TEXT NOT NULL DEFAULT '[]',
+    assignee_ids TEXT NOT NULL DEFAULT '[]',
+    field_values TEXT NOT NULL DEFAULT '{}',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_card_templates_board_id ON card_templates(board_id);
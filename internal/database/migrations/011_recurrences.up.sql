@@ -0,0 +1,16 @@
+-- Recurring cards. next_run_at is the next occurrence still to be created;
+-- the scheduler advances it before creating the card, so an occurrence is
+-- never created twice.
+CREATE TABLE recurrences (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    board_id INTEGER NOT NULL REFERENCES boards(id) ON DELETE CASCADE,
+    template_id INTEGER NOT NULL REFERENCES card_templates(id) ON DELETE CASCADE,
+    column_id INTEGER NOT NULL REFERENCES columns(id) ON DELETE CASCADE,
+    schedule TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    next_run_at DATETIME,
+    last_run_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_recurrences_board_id ON recurrences(board_id);
@@ -0,0 +1,19 @@
+-- Archived cards stay on the board but are hidden from columns. They are kept
+-- at position -1 so they never take part in a cell's ordering.
+ALTER TABLE cards ADD COLUMN archived_at DATETIME;
+
+-- Checklist items can be grouped into named checklists; '' is the card's
+-- unnamed checklist
+ALTER TABLE checklist_items ADD COLUMN checklist TEXT NOT NULL DEFAULT '';
+
+-- Links such as imported attachments
+CREATE TABLE card_links (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    card_id INTEGER NOT NULL REFERENCES cards(id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    url TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_card_links_card_id ON card_links(card_id);
@@ -0,0 +1 @@
+ALTER TABLE boards DROP COLUMN pg_schema_name;
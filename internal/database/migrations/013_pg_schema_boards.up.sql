@@ -0,0 +1,3 @@
+-- Postgres boards can live in a schema of a shared database instead of a
+-- database of their own; '' keeps the database-per-board layout
+ALTER TABLE boards ADD COLUMN pg_schema_name TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+ALTER TABLE cards DROP COLUMN resolution;
+ALTER TABLE columns DROP COLUMN resolution;
@@ -0,0 +1,5 @@
+ALTER TABLE columns ADD COLUMN resolution TEXT NOT NULL DEFAULT '';
+ALTER TABLE cards ADD COLUMN resolution TEXT NOT NULL DEFAULT '';
+
+UPDATE columns SET resolution = 'Done' WHERE is_done_column;
+UPDATE cards SET resolution = 'Done' WHERE completed_at IS NOT NULL;
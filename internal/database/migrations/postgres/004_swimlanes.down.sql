@@ -0,0 +1,3 @@
+DROP INDEX idx_cards_swimlane_id;
+ALTER TABLE cards DROP COLUMN swimlane_id;
+DROP TABLE swimlanes;
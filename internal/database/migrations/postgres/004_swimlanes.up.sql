@@ -0,0 +1,11 @@
+CREATE TABLE swimlanes (
+    id SERIAL PRIMARY KEY,
+    board_id INTEGER NOT NULL DEFAULT 1,
+    name TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    collapsed BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+ALTER TABLE cards ADD COLUMN swimlane_id INTEGER REFERENCES swimlanes(id) ON DELETE SET NULL;
+CREATE INDEX idx_cards_swimlane_id ON cards(swimlane_id);
@@ -0,0 +1,2 @@
+DROP TABLE card_field_values;
+DROP TABLE custom_fields;
@@ -0,0 +1,18 @@
+CREATE TABLE custom_fields (
+    id SERIAL PRIMARY KEY,
+    board_id INTEGER NOT NULL DEFAULT 1,
+    name TEXT NOT NULL,
+    field_type TEXT NOT NULL,
+    options TEXT NOT NULL DEFAULT '[]',
+    position INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE card_field_values (
+    card_id INTEGER NOT NULL REFERENCES cards(id) ON DELETE CASCADE,
+    field_id INTEGER NOT NULL REFERENCES custom_fields(id) ON DELETE CASCADE,
+    value TEXT NOT NULL,
+    PRIMARY KEY (card_id, field_id)
+);
+
+CREATE INDEX idx_card_field_values_field_id ON card_field_values(field_id);
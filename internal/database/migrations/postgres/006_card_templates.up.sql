@@ -0,0 +1,11 @@
+CREATE TABLE card_templates (
+    id SERIAL PRIMARY KEY,
+    board_id INTEGER NOT NULL DEFAULT 1,
+    name TEXT NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    checklist TEXT NOT NULL DEFAULT '[]',
+    assignee_ids TEXT NOT NULL DEFAULT '[]',
+    field_values TEXT NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT NOW()
+);
@@ -0,0 +1,11 @@
+CREATE TABLE recurrences (
+    id SERIAL PRIMARY KEY,
+    board_id INTEGER NOT NULL DEFAULT 1,
+    template_id INTEGER NOT NULL REFERENCES card_templates(id) ON DELETE CASCADE,
+    column_id INTEGER NOT NULL REFERENCES columns(id) ON DELETE CASCADE,
+    schedule TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    next_run_at TIMESTAMP,
+    last_run_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
@@ -0,0 +1,3 @@
+DROP TABLE card_links;
+ALTER TABLE checklist_items DROP COLUMN checklist;
+ALTER TABLE cards DROP COLUMN archived_at;
@@ -0,0 +1,14 @@
+ALTER TABLE cards ADD COLUMN archived_at TIMESTAMP;
+
+ALTER TABLE checklist_items ADD COLUMN checklist TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE card_links (
+    id SERIAL PRIMARY KEY,
+    card_id INTEGER NOT NULL REFERENCES cards(id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    url TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_card_links_card_id ON card_links(card_id);
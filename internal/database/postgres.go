@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"embed"
 	"fmt"
@@ -18,13 +19,16 @@ type PostgresDB struct {
 	db *sql.DB
 }
 
-func NewPostgres(connString string) (*PostgresDB, error) {
+// NewPostgres opens a pool on connString and pings it. ctx bounds the ping,
+// so an unreachable server fails by ctx's deadline.
+func NewPostgres(ctx context.Context, connString string) (*PostgresDB, error) {
 	db, err := sql.Open("postgres", connString)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open postgres database: %w", err)
 	}
 
-	if err := db.Ping(); err != nil {
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to ping postgres database: %w", err)
 	}
 
@@ -39,8 +43,18 @@ func (p *PostgresDB) Close() error {
 	return p.db.Close()
 }
 
+// Migrate runs the migrations on a connection borrowed from the pool and
+// returns it afterwards. The driver's WithInstance would keep a connection
+// out of the pool for as long as the pool lives.
 func (p *PostgresDB) Migrate() error {
-	driver, err := postgres.WithInstance(p.db, &postgres.Config{})
+	ctx := context.Background()
+	conn, err := p.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get migration connection: %w", err)
+	}
+	defer conn.Close()
+
+	driver, err := postgres.WithConnection(ctx, conn, &postgres.Config{})
 	if err != nil {
 		return fmt.Errorf("failed to create migration driver: %w", err)
 	}
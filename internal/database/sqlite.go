@@ -4,6 +4,10 @@ import (
 	"database/sql"
 	"embed"
 	"fmt"
+	"maps"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/sqlite3"
@@ -14,29 +18,147 @@ import (
 //go:embed migrations/*.sql
 var migrationsFS embed.FS
 
+// SQLiteOptions tune the connections a SQLite database is opened with
+type SQLiteOptions struct {
+	// JournalMode is the journal_mode pragma. In WAL mode readers carry on
+	// while a write is in progress.
+	JournalMode string
+	// Synchronous is the synchronous pragma. NORMAL is safe with WAL and
+	// much faster than FULL.
+	Synchronous string
+	// CacheSize is the cache_size pragma of each connection: pages when
+	// positive, KiB when negative
+	CacheSize int
+	// BusyTimeout is how long a connection waits for another one's lock
+	// before failing with "database is locked"
+	BusyTimeout time.Duration
+	// Readers is the number of connections in the read pool
+	Readers int
+}
+
+// DefaultSQLiteOptions suit a server with several users writing at once
+func DefaultSQLiteOptions() SQLiteOptions {
+	return SQLiteOptions{
+		JournalMode: "WAL",
+		Synchronous: "NORMAL",
+		CacheSize:   -2000,
+		BusyTimeout: 5 * time.Second,
+		Readers:     4,
+	}
+}
+
+// SQLiteDB holds two pools on one file. SQLite allows a single writer at a
+// time, so the write pool has one connection and writers queue for it in Go
+// rather than failing on SQLite's lock. Reads go to a pool of read-only
+// connections, which in WAL mode never wait for the writer.
 type SQLiteDB struct {
-	db *sql.DB
+	db     *sql.DB
+	reader *sql.DB
+	opts   SQLiteOptions
+}
+
+func NewSQLite(path string, opts SQLiteOptions) (*SQLiteDB, error) {
+	params := url.Values{}
+	params.Set("_foreign_keys", "on")
+	params.Set("_busy_timeout", strconv.FormatInt(opts.BusyTimeout.Milliseconds(), 10))
+	if opts.Synchronous != "" {
+		params.Set("_synchronous", opts.Synchronous)
+	}
+	if opts.CacheSize != 0 {
+		params.Set("_cache_size", strconv.Itoa(opts.CacheSize))
+	}
+
+	// The writer's transactions take the write lock when they begin, so a
+	// writer in another process makes them wait out the busy timeout instead
+	// of failing part way. The journal mode is set here too; in WAL mode it
+	// sticks to the file, so it is in place before the readers open.
+	writeParams := maps.Clone(params)
+	writeParams.Set("_txlock", "immediate")
+	if opts.JournalMode != "" {
+		writeParams.Set("_journal_mode", opts.JournalMode)
+	}
+	db, err := openSQLite(path, writeParams)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+
+	readParams := maps.Clone(params)
+	readParams.Set("_query_only", "true")
+	reader, err := openSQLite(path, readParams)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	readers := max(opts.Readers, 1)
+	reader.SetMaxOpenConns(readers)
+	reader.SetMaxIdleConns(readers)
+
+	return &SQLiteDB{db: db, reader: reader, opts: opts}, nil
 }
 
-func NewSQLite(path string) (*SQLiteDB, error) {
-	db, err := sql.Open("sqlite3", path+"?_foreign_keys=on")
+func openSQLite(path string, params url.Values) (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", path+"?"+params.Encode())
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
 	if err := db.Ping(); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	return &SQLiteDB{db: db}, nil
+	return db, nil
 }
 
+// DB returns the write pool, which migrations and transactions run on.
+// Repositories should use ReadWrite so their reads do not queue behind
+// writes.
 func (s *SQLiteDB) DB() *sql.DB {
 	return s.db
 }
 
+// ReadWrite returns a handle that sends queries to the read pool and
+// statements and transactions to the writer
+func (s *SQLiteDB) ReadWrite() *ReadWriteDB {
+	return &ReadWriteDB{write: s.db, read: s.reader}
+}
+
+// Options returns the options the database was opened with
+func (s *SQLiteDB) Options() SQLiteOptions {
+	return s.opts
+}
+
 func (s *SQLiteDB) Close() error {
-	return s.db.Close()
+	readErr := s.reader.Close()
+	if err := s.db.Close(); err != nil {
+		return err
+	}
+	return readErr
+}
+
+// ReadWriteDB splits a SQLite database's work between its pools. Queries
+// outside a transaction read committed data, as they would on any other
+// connection.
+type ReadWriteDB struct {
+	write *sql.DB
+	read  *sql.DB
+}
+
+func (d *ReadWriteDB) Exec(query string, args ...any) (sql.Result, error) {
+	return d.write.Exec(query, args...)
+}
+
+func (d *ReadWriteDB) Query(query string, args ...any) (*sql.Rows, error) {
+	return d.read.Query(query, args...)
+}
+
+func (d *ReadWriteDB) QueryRow(query string, args ...any) *sql.Row {
+	return d.read.QueryRow(query, args...)
+}
+
+func (d *ReadWriteDB) Begin() (*sql.Tx, error) {
+	return d.write.Begin()
 }
 
 func (s *SQLiteDB) Migrate() error {
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"krizzy/internal/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AdminHandler struct {
+	backups *services.BackupService
+	bm      *services.BoardManager
+}
+
+func NewAdminHandler(backups *services.BackupService, bm *services.BoardManager) *AdminHandler {
+	return &AdminHandler{backups: backups, bm: bm}
+}
+
+// CreateBackup takes an immediate online backup of the local database
+func (h *AdminHandler) CreateBackup(c echo.Context) error {
+	path, err := h.backups.RunBackup(c.Request().Context())
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Backup failed: "+err.Error())
+	}
+
+	return c.String(http.StatusOK, "Backup written to "+path)
+}
+
+// CheckIntegrity reports position gaps, dangling references and other
+// problems in the local database and every file and Postgres board
+func (h *AdminHandler) CheckIntegrity(c echo.Context) error {
+	return h.integrityReport(c, false)
+}
+
+// RepairIntegrity fixes what CheckIntegrity finds, one transaction per
+// database
+func (h *AdminHandler) RepairIntegrity(c echo.Context) error {
+	return h.integrityReport(c, true)
+}
+
+func (h *AdminHandler) integrityReport(c echo.Context, repair bool) error {
+	report := h.bm.CheckIntegrity(repair)
+
+	var b strings.Builder
+	report.WriteText(&b)
+
+	status := http.StatusOK
+	for _, db := range report.Databases {
+		if db.Err != nil {
+			status = http.StatusInternalServerError
+		}
+	}
+	return c.String(status, b.String())
+}
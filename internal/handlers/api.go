@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"krizzy/internal/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// APIHandler serves board data as JSON for scripts and integrations
+type APIHandler struct {
+	bm *services.BoardManager
+}
+
+func NewAPIHandler(bm *services.BoardManager) *APIHandler {
+	return &APIHandler{bm: bm}
+}
+
+// ListCards returns the board's cards, filtered like exports by the q, field
+// and value parameters
+func (h *APIHandler) ListCards(c echo.Context) error {
+	boardID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid board ID")
+	}
+
+	svc, err := h.bm.GetServiceForBoard(boardID)
+	if err != nil {
+		return boardServiceError(c, err)
+	}
+
+	cards, err := svc.ListCardsJSON(boardID, cardFilter(c))
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load cards")
+	}
+	return c.JSON(http.StatusOK, cards)
+}
+
+// GetCard returns one card of the board
+func (h *APIHandler) GetCard(c echo.Context) error {
+	boardID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid board ID")
+	}
+	cardID, err := strconv.ParseInt(c.Param("cardId"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid card ID")
+	}
+
+	svc, err := h.bm.GetServiceForBoard(boardID)
+	if err != nil {
+		return boardServiceError(c, err)
+	}
+
+	card, err := svc.GetCardJSON(boardID, cardID)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Card not found")
+	}
+	return c.JSON(http.StatusOK, card)
+}
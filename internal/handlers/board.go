@@ -1,10 +1,15 @@
 package handlers
 
 import (
-	"io"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"krizzy/internal/services"
 	"krizzy/internal/validation"
@@ -14,14 +19,14 @@ import (
 )
 
 type BoardHandler struct {
-	bm             *services.BoardManager
-	trelloImporter *services.TrelloImportService
+	bm       *services.BoardManager
+	importer *services.ImportService
 }
 
 func NewBoardHandler(bm *services.BoardManager) *BoardHandler {
 	return &BoardHandler{
-		bm:             bm,
-		trelloImporter: services.NewTrelloImportService(bm),
+		bm:       bm,
+		importer: services.NewImportService(bm),
 	}
 }
 
@@ -50,7 +55,7 @@ func (h *BoardHandler) GetImportModal(c echo.Context) error {
 		return c.String(http.StatusInternalServerError, "Failed to load connections")
 	}
 
-	return templates.ImportBoardModal(connections).Render(c.Request().Context(), c.Response().Writer)
+	return templates.ImportBoardModal(h.importer.Formats(), connections).Render(c.Request().Context(), c.Response().Writer)
 }
 
 // GetBoard shows a specific board
@@ -62,7 +67,10 @@ func (h *BoardHandler) GetBoard(c echo.Context) error {
 
 	svc, err := h.bm.GetServiceForBoard(boardID)
 	if err != nil {
-		return c.String(http.StatusNotFound, "Board not found")
+		if errors.Is(err, services.ErrBoardUnavailable) && c.Request().Header.Get("HX-Request") != "true" {
+			return h.renderUnavailable(c, boardID, err)
+		}
+		return boardServiceError(c, err)
 	}
 
 	board, err := svc.GetBoardWithData(boardID)
@@ -77,18 +85,136 @@ func (h *BoardHandler) GetBoard(c echo.Context) error {
 	return templates.BoardPage(board).Render(c.Request().Context(), c.Response().Writer)
 }
 
+// ExportBoard downloads a board as CSV (one row per card), Markdown or Trello
+// JSON. The q, field and value parameters apply the board's card filter to
+// CSV and Markdown.
+func (h *BoardHandler) ExportBoard(c echo.Context) error {
+	boardID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid board ID")
+	}
+
+	svc, err := h.bm.GetServiceForBoard(boardID)
+	if err != nil {
+		return boardServiceError(c, err)
+	}
+	board, err := h.bm.GetBoard(boardID)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Board not found")
+	}
+
+	filter := cardFilter(c)
+
+	// Render fully first so a failure is still a clean error response
+	var buf bytes.Buffer
+	var contentType, ext string
+	switch c.QueryParam("format") {
+	case "csv", "":
+		err = svc.ExportCSV(&buf, boardID, filter)
+		contentType, ext = "text/csv; charset=utf-8", "csv"
+	case "md", "markdown":
+		err = svc.ExportMarkdown(&buf, boardID, filter)
+		contentType, ext = "text/markdown; charset=utf-8", "md"
+	case "trello":
+		// A Trello export is a way out of krizzy, so it keeps every card
+		err = svc.ExportTrello(&buf, boardID)
+		contentType, ext = "application/json", "json"
+	default:
+		return c.String(http.StatusBadRequest, "Unknown export format")
+	}
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to export board")
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", exportFileName(board.Name, ext)))
+	return c.Blob(http.StatusOK, contentType, buf.Bytes())
+}
+
+// cardFilter reads the board filter bar's q, field and value parameters
+func cardFilter(c echo.Context) services.CardFilter {
+	filter := services.CardFilter{
+		Query: strings.TrimSpace(c.QueryParam("q")),
+		Value: strings.TrimSpace(c.QueryParam("value")),
+	}
+	filter.FieldID, _ = strconv.ParseInt(c.QueryParam("field"), 10, 64)
+	return filter
+}
+
+// exportFileName turns a board name into a safe download name
+func exportFileName(name, ext string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case b.Len() > 0 && !strings.HasSuffix(b.String(), "-"):
+			b.WriteByte('-')
+		}
+	}
+	base := strings.Trim(b.String(), "-")
+	if base == "" {
+		base = "board"
+	}
+	return base + "." + ext
+}
+
+// ReconnectBoard probes an unavailable board right away and sends the user
+// back to it if the storage answers again.
+func (h *BoardHandler) ReconnectBoard(c echo.Context) error {
+	boardID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid board ID")
+	}
+
+	if err := h.bm.ProbeBoard(boardID); err != nil {
+		if errors.Is(err, services.ErrBoardUnavailable) {
+			return h.renderUnavailable(c, boardID, err)
+		}
+		return c.String(http.StatusNotFound, "Board not found")
+	}
+
+	return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/boards/%d", boardID))
+}
+
+// renderUnavailable shows the retry page for a board whose storage is down.
+// The cause names the database host and user, so it only goes to the log.
+func (h *BoardHandler) renderUnavailable(c echo.Context, boardID int64, cause error) error {
+	board, err := h.bm.GetBoard(boardID)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Board not found")
+	}
+
+	log.Printf("board %d: %v", boardID, cause)
+	c.Response().WriteHeader(http.StatusServiceUnavailable)
+	return templates.BoardUnavailablePage(board).Render(c.Request().Context(), c.Response().Writer)
+}
+
+// boardServiceError maps a GetServiceForBoard failure to a response. Boards
+// whose storage is down get a 503 so clients don't mistake them for deleted,
+// and the cause is logged rather than sent.
+func boardServiceError(c echo.Context, err error) error {
+	if errors.Is(err, services.ErrBoardUnavailable) {
+		log.Printf("%s %s: %v", c.Request().Method, c.Request().URL.Path, err)
+		return c.String(http.StatusServiceUnavailable, "Board storage unavailable, please retry shortly")
+	}
+	return c.String(http.StatusNotFound, "Board not found")
+}
+
 type CreateBoardRequest struct {
 	Name           string `form:"name"`
 	DbType         string `form:"db_type"`
 	PgConnectionID int64  `form:"pg_connection_id"`
 	PgDatabaseName string `form:"pg_database_name"`
+	PgSchemaName   string `form:"pg_schema_name"`
 }
 
-type ImportTrelloRequest struct {
+type ImportBoardRequest struct {
+	Format         string `form:"format"`
 	Name           string `form:"name"`
 	DbType         string `form:"db_type"`
 	PgConnectionID int64  `form:"pg_connection_id"`
 	PgDatabaseName string `form:"pg_database_name"`
+	PgSchemaName   string `form:"pg_schema_name"`
 }
 
 // CreateBoard creates a new board
@@ -112,8 +238,11 @@ func (h *BoardHandler) CreateBoard(c echo.Context) error {
 		pgConnID = &req.PgConnectionID
 	}
 
-	_, err := h.bm.CreateBoard(req.Name, req.DbType, pgConnID, req.PgDatabaseName)
+	_, err := h.bm.CreateBoard(req.Name, req.DbType, pgConnID, req.PgDatabaseName, req.PgSchemaName)
 	if err != nil {
+		if errors.Is(err, services.ErrDemoMode) {
+			return c.String(http.StatusForbidden, err.Error())
+		}
 		return c.String(http.StatusInternalServerError, "Failed to create board: "+err.Error())
 	}
 
@@ -130,36 +259,207 @@ func (h *BoardHandler) CreateBoard(c echo.Context) error {
 	return templates.BoardsList(boards, connections).Render(c.Request().Context(), c.Response().Writer)
 }
 
-func (h *BoardHandler) ImportTrelloBoard(c echo.Context) error {
-	var req ImportTrelloRequest
+// readImportFile reads the uploaded export from the import form
+func readImportFile(c echo.Context) ([]byte, error) {
+	fileHeader, err := c.FormFile("import_file")
+	if err != nil {
+		return nil, errors.New("export file is required")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, errors.New("failed to read uploaded file")
+	}
+	defer file.Close()
+
+	return services.ReadExport(file)
+}
+
+// PreviewImport dry-runs an import and reports what it would create without
+// writing anything
+func (h *BoardHandler) PreviewImport(c echo.Context) error {
+	data, err := readImportFile(c)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	report, err := h.importer.DryRun(data, c.FormValue("format"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Failed to read export: "+err.Error())
+	}
+
+	return templates.ImportPreview(report).Render(c.Request().Context(), c.Response().Writer)
+}
+
+// ImportBoard starts importing an uploaded export in the background,
+// detecting its format unless one is chosen, and renders the job's progress
+func (h *BoardHandler) ImportBoard(c echo.Context) error {
+	var req ImportBoardRequest
 	if err := c.Bind(&req); err != nil {
 		return c.String(http.StatusBadRequest, "Invalid request")
 	}
 
-	req.Name = strings.TrimSpace(req.Name)
 	if req.DbType == "" {
 		req.DbType = "local"
 	}
 
-	fileHeader, err := c.FormFile("trello_file")
+	data, err := readImportFile(c)
 	if err != nil {
-		return c.String(http.StatusBadRequest, "Trello JSON file is required")
+		return c.String(http.StatusBadRequest, err.Error())
 	}
 
-	file, err := fileHeader.Open()
+	var pgConnID *int64
+	if req.DbType == "postgres" && req.PgConnectionID > 0 {
+		pgConnID = &req.PgConnectionID
+	}
+
+	job, err := h.importer.StartImport(services.ImportRequest{
+		Data:           data,
+		Format:         req.Format,
+		BoardName:      req.Name,
+		DbType:         req.DbType,
+		PgConnectionID: pgConnID,
+		PgDatabaseName: req.PgDatabaseName,
+		PgSchemaName:   req.PgSchemaName,
+	})
 	if err != nil {
-		return c.String(http.StatusBadRequest, "Failed to read uploaded file")
+		return c.String(http.StatusBadRequest, "Failed to import board: "+err.Error())
+	}
+
+	return templates.ImportProgress(job).Render(c.Request().Context(), c.Response().Writer)
+}
+
+// StreamImport sends an import job's progress as server-sent events until
+// the job finishes
+func (h *BoardHandler) StreamImport(c echo.Context) error {
+	id := c.Param("id")
+	job, ch, ok := h.importer.SubscribeJob(id)
+	if !ok {
+		return c.String(http.StatusNotFound, "Import not found")
+	}
+	defer h.importer.UnsubscribeJob(id, ch)
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+
+	send := func(job services.ImportJob) error {
+		payload, err := json.Marshal(job)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(res, "event: import-progress\ndata: %s\n\n", payload); err != nil {
+			return err
+		}
+		res.Flush()
+		return nil
+	}
+
+	if err := send(job); err != nil || ch == nil {
+		return nil
+	}
+
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	ctx := c.Request().Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if _, err := fmt.Fprint(res, ": keepalive\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		case update, ok := <-ch:
+			if !ok {
+				// The job finished; Job has its final state
+				if final, found := h.importer.Job(id); found {
+					send(final)
+				}
+				return nil
+			}
+			if err := send(update); err != nil {
+				return nil
+			}
+		}
+	}
+}
+
+// CancelImport stops a running import, rolling back what it wrote
+func (h *BoardHandler) CancelImport(c echo.Context) error {
+	if !h.importer.CancelImport(c.Param("id")) {
+		return c.String(http.StatusNotFound, "Import not found or already finished")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *BoardHandler) GetCloneModal(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid board ID")
+	}
+
+	board, err := h.bm.GetBoard(id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Board not found")
+	}
+
+	connections, err := h.bm.PgConnRepo().GetAll()
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load connections")
+	}
+
+	return templates.CloneBoardModal(board, connections).Render(c.Request().Context(), c.Response().Writer)
+}
+
+type CloneBoardRequest struct {
+	Name            string `form:"name"`
+	IncludeComments bool   `form:"include_comments"`
+	DbType          string `form:"db_type"`
+	PgConnectionID  int64  `form:"pg_connection_id"`
+	PgDatabaseName  string `form:"pg_database_name"`
+	PgSchemaName    string `form:"pg_schema_name"`
+}
+
+// CloneBoard copies a board into a new board on any backend
+func (h *BoardHandler) CloneBoard(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid board ID")
+	}
+
+	var req CloneBoardRequest
+	if err := c.Bind(&req); err != nil {
+		return c.String(http.StatusBadRequest, "Invalid request")
+	}
+
+	req.Name = validation.SanitizeName(req.Name)
+	if req.Name == "" {
+		return c.String(http.StatusBadRequest, "Name is required")
+	}
+
+	if req.DbType == "" {
+		req.DbType = "local"
 	}
-	defer file.Close()
 
 	var pgConnID *int64
 	if req.DbType == "postgres" && req.PgConnectionID > 0 {
 		pgConnID = &req.PgConnectionID
 	}
 
-	reader := io.LimitReader(file, 25<<20)
-	if _, err := h.trelloImporter.ImportBoard(reader, req.Name, req.DbType, pgConnID, req.PgDatabaseName); err != nil {
-		return c.String(http.StatusBadRequest, "Failed to import Trello board: "+err.Error())
+	if _, err := h.bm.CloneBoard(id, req.Name, req.DbType, pgConnID, req.PgDatabaseName, req.PgSchemaName, req.IncludeComments); err != nil {
+		if errors.Is(err, services.ErrBoardUnavailable) {
+			return boardServiceError(c, err)
+		}
+		if errors.Is(err, services.ErrDemoMode) {
+			return c.String(http.StatusForbidden, err.Error())
+		}
+		return c.String(http.StatusInternalServerError, "Failed to duplicate board: "+err.Error())
 	}
 
 	boards, err := h.bm.GetAllBoards()
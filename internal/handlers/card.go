@@ -3,6 +3,8 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"krizzy/internal/models"
 	"krizzy/internal/services"
@@ -14,17 +16,18 @@ import (
 
 type CardHandler struct {
 	bm  *services.BoardManager
-	hub *services.BoardEventHub
+	hub services.BoardEventHub
 }
 
-func NewCardHandler(bm *services.BoardManager, hub *services.BoardEventHub) *CardHandler {
+func NewCardHandler(bm *services.BoardManager, hub services.BoardEventHub) *CardHandler {
 	return &CardHandler{bm: bm, hub: hub}
 }
 
 type CreateCardRequest struct {
-	ColumnID int64  `form:"column_id"`
-	Title    string `form:"title"`
-	BoardID  int64  `form:"board_id"`
+	ColumnID   int64  `form:"column_id"`
+	SwimlaneID int64  `form:"swimlane_id"`
+	Title      string `form:"title"`
+	BoardID    int64  `form:"board_id"`
 }
 
 func (h *CardHandler) CreateCard(c echo.Context) error {
@@ -37,25 +40,35 @@ func (h *CardHandler) CreateCard(c echo.Context) error {
 
 	svc, err := h.bm.GetServiceForBoard(req.BoardID)
 	if err != nil {
-		return c.String(http.StatusNotFound, "Board not found")
+		return boardServiceError(c, err)
+	}
+
+	if _, err := svc.ColumnInBoard(req.BoardID, req.ColumnID); err != nil {
+		return c.String(http.StatusNotFound, "Column not found")
+	}
+
+	swimlaneID, err := swimlaneInBoard(svc, req.BoardID, req.SwimlaneID)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Swimlane not found")
 	}
 
 	card := &models.Card{
-		ColumnID: req.ColumnID,
-		Title:    req.Title,
+		ColumnID:   req.ColumnID,
+		SwimlaneID: swimlaneID,
+		Title:      req.Title,
 	}
 
 	if err := svc.CardRepo.Create(card); err != nil {
 		return c.String(http.StatusInternalServerError, "Failed to create card")
 	}
 
-	publishBoardEvent(h.hub, services.BoardEvent{
+	publishBoardEvent(h.hub, withCard(c, svc, services.BoardEvent{
 		Type:     "card.created",
 		BoardID:  req.BoardID,
 		CardID:   card.ID,
 		ColumnID: req.ColumnID,
 		ClientID: requestClientID(c),
-	})
+	}))
 
 	board, err := svc.GetBoardWithData(req.BoardID)
 	if err != nil {
@@ -84,10 +97,10 @@ func (h *CardHandler) UpdateCard(c echo.Context) error {
 
 	svc, err := h.bm.GetServiceForBoard(req.BoardID)
 	if err != nil {
-		return c.String(http.StatusNotFound, "Board not found")
+		return boardServiceError(c, err)
 	}
 
-	card, err := svc.CardRepo.GetByID(id)
+	card, err := svc.CardInBoard(req.BoardID, id)
 	if err != nil {
 		return c.String(http.StatusNotFound, "Card not found")
 	}
@@ -99,13 +112,13 @@ func (h *CardHandler) UpdateCard(c echo.Context) error {
 		return c.String(http.StatusInternalServerError, "Failed to update card")
 	}
 
-	publishBoardEvent(h.hub, services.BoardEvent{
+	publishBoardEvent(h.hub, withCard(c, svc, services.BoardEvent{
 		Type:     "card.updated",
 		BoardID:  req.BoardID,
 		CardID:   card.ID,
 		ColumnID: card.ColumnID,
 		ClientID: requestClientID(c),
-	})
+	}))
 
 	cardWithDetails, err := svc.GetCardWithDetails(id)
 	if err != nil {
@@ -117,7 +130,12 @@ func (h *CardHandler) UpdateCard(c echo.Context) error {
 		return c.String(http.StatusInternalServerError, "Failed to load people")
 	}
 
-	return templates.CardModal(cardWithDetails, people, req.BoardID).Render(c.Request().Context(), c.Response().Writer)
+	fields, err := svc.FieldRepo.GetByBoardID(req.BoardID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load fields")
+	}
+
+	return templates.CardModal(cardWithDetails, people, fields, req.BoardID).Render(c.Request().Context(), c.Response().Writer)
 }
 
 func (h *CardHandler) DeleteCard(c echo.Context) error {
@@ -130,10 +148,10 @@ func (h *CardHandler) DeleteCard(c echo.Context) error {
 
 	svc, err := h.bm.GetServiceForBoard(boardID)
 	if err != nil {
-		return c.String(http.StatusNotFound, "Board not found")
+		return boardServiceError(c, err)
 	}
 
-	card, err := svc.CardRepo.GetByID(id)
+	card, err := svc.CardInBoard(boardID, id)
 	if err != nil {
 		return c.String(http.StatusNotFound, "Card not found")
 	}
@@ -158,10 +176,119 @@ func (h *CardHandler) DeleteCard(c echo.Context) error {
 	return templates.BoardContent(board).Render(c.Request().Context(), c.Response().Writer)
 }
 
+// ArchiveCard hides a card from its column. It keeps its details and can be
+// restored from the board's archived cards.
+func (h *CardHandler) ArchiveCard(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid card ID")
+	}
+
+	boardID, _ := strconv.ParseInt(c.FormValue("board_id"), 10, 64)
+
+	svc, err := h.bm.GetServiceForBoard(boardID)
+	if err != nil {
+		return boardServiceError(c, err)
+	}
+
+	card, err := svc.CardInBoard(boardID, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Card not found")
+	}
+
+	if err := svc.CardRepo.Archive(id, time.Now()); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to archive card")
+	}
+
+	publishBoardEvent(h.hub, services.BoardEvent{
+		Type:     "card.deleted",
+		BoardID:  boardID,
+		CardID:   id,
+		ColumnID: card.ColumnID,
+		ClientID: requestClientID(c),
+	})
+
+	board, err := svc.GetBoardWithData(boardID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load board")
+	}
+
+	return templates.BoardContent(board).Render(c.Request().Context(), c.Response().Writer)
+}
+
+// GetArchivedCards lists a board's archived cards
+func (h *CardHandler) GetArchivedCards(c echo.Context) error {
+	boardID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid board ID")
+	}
+
+	svc, err := h.bm.GetServiceForBoard(boardID)
+	if err != nil {
+		return boardServiceError(c, err)
+	}
+
+	return h.renderArchived(c, svc, boardID, true)
+}
+
+// RestoreCard puts an archived card back at the end of its cell
+func (h *CardHandler) RestoreCard(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid card ID")
+	}
+
+	boardID, _ := strconv.ParseInt(c.FormValue("board_id"), 10, 64)
+
+	svc, err := h.bm.GetServiceForBoard(boardID)
+	if err != nil {
+		return boardServiceError(c, err)
+	}
+
+	card, err := svc.CardInBoard(boardID, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Card not found")
+	}
+
+	if err := svc.CardRepo.Restore(id); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to restore card")
+	}
+
+	publishBoardEvent(h.hub, withCard(c, svc, services.BoardEvent{
+		Type:     "card.created",
+		BoardID:  boardID,
+		CardID:   id,
+		ColumnID: card.ColumnID,
+		ClientID: requestClientID(c),
+	}))
+
+	return h.renderArchived(c, svc, boardID, false)
+}
+
+func (h *CardHandler) renderArchived(c echo.Context, svc *services.KanbanService, boardID int64, modal bool) error {
+	cards, err := svc.CardRepo.GetArchivedByBoardID(boardID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load archived cards")
+	}
+
+	columns, err := svc.ColumnRepo.GetByBoardID(boardID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load columns")
+	}
+
+	if modal {
+		return templates.ArchivedCardsModal(cards, columns, boardID).Render(c.Request().Context(), c.Response().Writer)
+	}
+	return templates.ArchivedCardsList(cards, columns, boardID).Render(c.Request().Context(), c.Response().Writer)
+}
+
+// MoveCardRequest moves a card into a (column, swimlane) cell. SwimlaneID is
+// left out to keep the card's current lane and 0 moves it to the unlaned row.
 type MoveCardRequest struct {
-	ColumnID int64 `json:"column_id" form:"column_id"`
-	Position int   `json:"position" form:"position"`
-	BoardID  int64 `json:"board_id" form:"board_id"`
+	ColumnID   int64  `json:"column_id" form:"column_id"`
+	SwimlaneID *int64 `json:"swimlane_id" form:"swimlane_id"`
+	Position   int    `json:"position" form:"position"`
+	BoardID    int64  `json:"board_id" form:"board_id"`
 }
 
 func (h *CardHandler) MoveCard(c echo.Context) error {
@@ -177,26 +304,38 @@ func (h *CardHandler) MoveCard(c echo.Context) error {
 
 	svc, err := h.bm.GetServiceForBoard(req.BoardID)
 	if err != nil {
-		return c.String(http.StatusNotFound, "Board not found")
+		return boardServiceError(c, err)
 	}
 
-	card, err := svc.CardRepo.GetByID(id)
+	card, err := svc.CardInBoard(req.BoardID, id)
 	if err != nil {
 		return c.String(http.StatusNotFound, "Card not found")
 	}
 
-	if err := svc.MoveCard(id, req.ColumnID, req.Position); err != nil {
+	if _, err := svc.ColumnInBoard(req.BoardID, req.ColumnID); err != nil {
+		return c.String(http.StatusNotFound, "Column not found")
+	}
+
+	swimlaneID := card.SwimlaneID
+	if req.SwimlaneID != nil {
+		swimlaneID, err = swimlaneInBoard(svc, req.BoardID, *req.SwimlaneID)
+		if err != nil {
+			return c.String(http.StatusNotFound, "Swimlane not found")
+		}
+	}
+
+	if err := svc.MoveCard(id, req.ColumnID, swimlaneID, req.Position); err != nil {
 		return c.String(http.StatusInternalServerError, "Failed to move card")
 	}
 
-	publishBoardEvent(h.hub, services.BoardEvent{
+	publishBoardEvent(h.hub, withCard(c, svc, services.BoardEvent{
 		Type:         "card.moved",
 		BoardID:      req.BoardID,
 		CardID:       id,
 		FromColumnID: card.ColumnID,
 		ToColumnID:   req.ColumnID,
 		ClientID:     requestClientID(c),
-	})
+	}))
 
 	return c.NoContent(http.StatusOK)
 }
@@ -219,25 +358,29 @@ func (h *CardHandler) UpdateAssignees(c echo.Context) error {
 
 	svc, err := h.bm.GetServiceForBoard(req.BoardID)
 	if err != nil {
-		return c.String(http.StatusNotFound, "Board not found")
+		return boardServiceError(c, err)
 	}
 
-	if err := svc.PersonRepo.SetCardAssignees(id, req.PersonIDs); err != nil {
-		return c.String(http.StatusInternalServerError, "Failed to update assignees")
-	}
-
-	card, err := svc.CardRepo.GetByID(id)
+	card, err := svc.CardInBoard(req.BoardID, id)
 	if err != nil {
 		return c.String(http.StatusNotFound, "Card not found")
 	}
 
-	publishBoardEvent(h.hub, services.BoardEvent{
+	if err := svc.PeopleInBoard(req.BoardID, req.PersonIDs); err != nil {
+		return c.String(http.StatusNotFound, "Person not found")
+	}
+
+	if err := svc.PersonRepo.SetCardAssignees(id, req.PersonIDs); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to update assignees")
+	}
+
+	publishBoardEvent(h.hub, withCard(c, svc, services.BoardEvent{
 		Type:     "card.updated",
 		BoardID:  req.BoardID,
 		CardID:   id,
 		ColumnID: card.ColumnID,
 		ClientID: requestClientID(c),
-	})
+	}))
 
 	cardWithDetails, err := svc.GetCardWithDetails(id)
 	if err != nil {
@@ -251,3 +394,175 @@ func (h *CardHandler) UpdateAssignees(c echo.Context) error {
 
 	return templates.AssigneePicker(cardWithDetails.ID, cardWithDetails.Assignees, people, req.BoardID).Render(c.Request().Context(), c.Response().Writer)
 }
+
+// DuplicateCard copies a card, with its checklist, comments, assignees and
+// field values, to the end of its cell
+func (h *CardHandler) DuplicateCard(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid card ID")
+	}
+
+	boardID, _ := strconv.ParseInt(c.FormValue("board_id"), 10, 64)
+
+	svc, err := h.bm.GetServiceForBoard(boardID)
+	if err != nil {
+		return boardServiceError(c, err)
+	}
+
+	if _, err := svc.CardInBoard(boardID, id); err != nil {
+		return c.String(http.StatusNotFound, "Card not found")
+	}
+
+	card, err := svc.DuplicateCard(boardID, id)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to duplicate card")
+	}
+
+	publishBoardEvent(h.hub, withCard(c, svc, services.BoardEvent{
+		Type:     "card.created",
+		BoardID:  boardID,
+		CardID:   card.ID,
+		ColumnID: card.ColumnID,
+		ClientID: requestClientID(c),
+	}))
+
+	board, err := svc.GetBoardWithData(boardID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load board")
+	}
+
+	return templates.BoardContent(board).Render(c.Request().Context(), c.Response().Writer)
+}
+
+// GetMoveBoardTargets lists the other boards a card can be moved to. Their
+// columns are only loaded once a board is picked, so listing does not open
+// every board's storage.
+func (h *CardHandler) GetMoveBoardTargets(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid card ID")
+	}
+
+	boardID, _ := strconv.ParseInt(c.QueryParam("board_id"), 10, 64)
+
+	boards, err := h.bm.GetAllBoards()
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load boards")
+	}
+
+	var targets []models.Board
+	for _, board := range boards {
+		if board.ID != boardID {
+			targets = append(targets, board)
+		}
+	}
+
+	return templates.MoveBoardForm(id, boardID, targets).Render(c.Request().Context(), c.Response().Writer)
+}
+
+// GetMoveBoardColumns lists the columns of the board picked in the move form
+func (h *CardHandler) GetMoveBoardColumns(c echo.Context) error {
+	targetBoardID, err := strconv.ParseInt(c.QueryParam("target_board"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid board ID")
+	}
+
+	// An unreachable board is shown like one without columns, so the form
+	// still swaps in a message
+	var columns []models.Column
+	if svc, err := h.bm.GetServiceForBoard(targetBoardID); err == nil {
+		columns, _ = svc.ColumnRepo.GetByBoardID(targetBoardID)
+	}
+
+	return templates.MoveBoardColumns(targetBoardID, columns).Render(c.Request().Context(), c.Response().Writer)
+}
+
+// MoveCardToBoardRequest names the destination as "boardID:columnID", since
+// column IDs are only unique within a board's own storage
+type MoveCardToBoardRequest struct {
+	Target  string `form:"target"`
+	BoardID int64  `form:"board_id"`
+}
+
+func parseMoveTarget(target string) (boardID, columnID int64, err error) {
+	boardPart, columnPart, ok := strings.Cut(target, ":")
+	if !ok {
+		return 0, 0, strconv.ErrSyntax
+	}
+	if boardID, err = strconv.ParseInt(boardPart, 10, 64); err != nil {
+		return 0, 0, err
+	}
+	if columnID, err = strconv.ParseInt(columnPart, 10, 64); err != nil {
+		return 0, 0, err
+	}
+	return boardID, columnID, nil
+}
+
+// MoveCardToBoard sends a card to a column on another board, which may live
+// on a different storage backend
+func (h *CardHandler) MoveCardToBoard(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid card ID")
+	}
+
+	var req MoveCardToBoardRequest
+	if err := c.Bind(&req); err != nil {
+		return c.String(http.StatusBadRequest, "Invalid request")
+	}
+
+	targetBoardID, targetColumnID, err := parseMoveTarget(req.Target)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid target")
+	}
+
+	svc, err := h.bm.GetServiceForBoard(req.BoardID)
+	if err != nil {
+		return boardServiceError(c, err)
+	}
+
+	card, err := svc.CardInBoard(req.BoardID, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Card not found")
+	}
+
+	if targetBoardID == req.BoardID {
+		return c.String(http.StatusBadRequest, services.ErrSameBoard.Error())
+	}
+
+	target, err := h.bm.GetServiceForBoard(targetBoardID)
+	if err != nil {
+		return boardServiceError(c, err)
+	}
+
+	if _, err := target.ColumnInBoard(targetBoardID, targetColumnID); err != nil {
+		return c.String(http.StatusNotFound, "Column not found")
+	}
+
+	moved, err := h.bm.MoveCardToBoard(req.BoardID, id, targetBoardID, targetColumnID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to move card")
+	}
+
+	publishBoardEvent(h.hub, services.BoardEvent{
+		Type:     "card.deleted",
+		BoardID:  req.BoardID,
+		CardID:   id,
+		ColumnID: card.ColumnID,
+		ClientID: requestClientID(c),
+	})
+	publishBoardEvent(h.hub, withCard(c, target, services.BoardEvent{
+		Type:     "card.created",
+		BoardID:  targetBoardID,
+		CardID:   moved.ID,
+		ColumnID: moved.ColumnID,
+	}))
+
+	board, err := svc.GetBoardWithData(req.BoardID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load board")
+	}
+
+	return templates.BoardContent(board).Render(c.Request().Context(), c.Response().Writer)
+}
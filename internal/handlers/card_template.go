@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"krizzy/internal/services"
+	"krizzy/internal/validation"
+	"krizzy/templates"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CardTemplateHandler struct {
+	bm  *services.BoardManager
+	hub services.BoardEventHub
+}
+
+func NewCardTemplateHandler(bm *services.BoardManager, hub services.BoardEventHub) *CardTemplateHandler {
+	return &CardTemplateHandler{bm: bm, hub: hub}
+}
+
+func (h *CardTemplateHandler) GetTemplatesModal(c echo.Context) error {
+	boardID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid board ID")
+	}
+
+	svc, err := h.bm.GetServiceForBoard(boardID)
+	if err != nil {
+		return boardServiceError(c, err)
+	}
+
+	cardTemplates, err := svc.TemplateRepo.GetByBoardID(boardID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load templates")
+	}
+
+	return templates.TemplatesModal(cardTemplates, boardID).Render(c.Request().Context(), c.Response().Writer)
+}
+
+type SaveCardTemplateRequest struct {
+	Name    string `form:"name"`
+	BoardID int64  `form:"board_id"`
+}
+
+// SaveCardTemplate stores a card's current contents as a board template
+func (h *CardTemplateHandler) SaveCardTemplate(c echo.Context) error {
+	cardID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid card ID")
+	}
+
+	var req SaveCardTemplateRequest
+	if err := c.Bind(&req); err != nil {
+		return c.String(http.StatusBadRequest, "Invalid request")
+	}
+
+	req.Name = validation.SanitizeName(req.Name)
+	if req.Name == "" {
+		return c.String(http.StatusBadRequest, "Name is required")
+	}
+
+	svc, err := h.bm.GetServiceForBoard(req.BoardID)
+	if err != nil {
+		return boardServiceError(c, err)
+	}
+
+	if _, err := svc.CardInBoard(req.BoardID, cardID); err != nil {
+		return c.String(http.StatusNotFound, "Card not found")
+	}
+
+	tpl, err := svc.TemplateFromCard(cardID, req.BoardID, req.Name)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to save template")
+	}
+
+	publishBoardEvent(h.hub, services.BoardEvent{
+		Type:     "templates.updated",
+		BoardID:  req.BoardID,
+		ClientID: requestClientID(c),
+	})
+
+	return templates.CardTemplateSaved(tpl).Render(c.Request().Context(), c.Response().Writer)
+}
+
+type CreateCardFromTemplateRequest struct {
+	TemplateID int64 `form:"template_id"`
+	ColumnID   int64 `form:"column_id"`
+	SwimlaneID int64 `form:"swimlane_id"`
+	BoardID    int64 `form:"board_id"`
+}
+
+func (h *CardTemplateHandler) CreateCardFromTemplate(c echo.Context) error {
+	var req CreateCardFromTemplateRequest
+	if err := c.Bind(&req); err != nil {
+		return c.String(http.StatusBadRequest, "Invalid request")
+	}
+
+	svc, err := h.bm.GetServiceForBoard(req.BoardID)
+	if err != nil {
+		return boardServiceError(c, err)
+	}
+
+	tpl, err := svc.TemplateInBoard(req.BoardID, req.TemplateID)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Template not found")
+	}
+
+	if _, err := svc.ColumnInBoard(req.BoardID, req.ColumnID); err != nil {
+		return c.String(http.StatusNotFound, "Column not found")
+	}
+
+	swimlaneID, err := swimlaneInBoard(svc, req.BoardID, req.SwimlaneID)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Swimlane not found")
+	}
+
+	card, err := svc.CreateCardFromTemplate(tpl, req.ColumnID, swimlaneID, time.Now())
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to create card")
+	}
+
+	publishBoardEvent(h.hub, withCard(c, svc, services.BoardEvent{
+		Type:     "card.created",
+		BoardID:  req.BoardID,
+		CardID:   card.ID,
+		ColumnID: req.ColumnID,
+		ClientID: requestClientID(c),
+	}))
+
+	board, err := svc.GetBoardWithData(req.BoardID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load board")
+	}
+
+	return templates.BoardContent(board).Render(c.Request().Context(), c.Response().Writer)
+}
+
+func (h *CardTemplateHandler) DeleteTemplate(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid template ID")
+	}
+
+	boardID, _ := strconv.ParseInt(c.QueryParam("board_id"), 10, 64)
+
+	svc, err := h.bm.GetServiceForBoard(boardID)
+	if err != nil {
+		return boardServiceError(c, err)
+	}
+
+	if _, err := svc.TemplateInBoard(boardID, id); err != nil {
+		return c.String(http.StatusNotFound, "Template not found")
+	}
+
+	if err := svc.TemplateRepo.Delete(id); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to delete template")
+	}
+
+	publishBoardEvent(h.hub, services.BoardEvent{
+		Type:     "templates.updated",
+		BoardID:  boardID,
+		ClientID: requestClientID(c),
+	})
+
+	cardTemplates, err := svc.TemplateRepo.GetByBoardID(boardID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load templates")
+	}
+
+	return templates.TemplatesList(cardTemplates, boardID).Render(c.Request().Context(), c.Response().Writer)
+}
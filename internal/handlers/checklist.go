@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"krizzy/internal/models"
 	"krizzy/internal/services"
@@ -13,16 +14,17 @@ import (
 
 type ChecklistHandler struct {
 	bm  *services.BoardManager
-	hub *services.BoardEventHub
+	hub services.BoardEventHub
 }
 
-func NewChecklistHandler(bm *services.BoardManager, hub *services.BoardEventHub) *ChecklistHandler {
+func NewChecklistHandler(bm *services.BoardManager, hub services.BoardEventHub) *ChecklistHandler {
 	return &ChecklistHandler{bm: bm, hub: hub}
 }
 
 type CreateChecklistItemRequest struct {
-	Content string `form:"content"`
-	BoardID int64  `form:"board_id"`
+	Content   string `form:"content"`
+	Checklist string `form:"checklist"`
+	BoardID   int64  `form:"board_id"`
 }
 
 func (h *ChecklistHandler) CreateItem(c echo.Context) error {
@@ -42,28 +44,31 @@ func (h *ChecklistHandler) CreateItem(c echo.Context) error {
 
 	svc, err := h.bm.GetServiceForBoard(req.BoardID)
 	if err != nil {
-		return c.String(http.StatusNotFound, "Board not found")
+		return boardServiceError(c, err)
+	}
+
+	card, err := svc.CardInBoard(req.BoardID, cardID)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Card not found")
 	}
 
 	item := &models.ChecklistItem{
-		CardID:  cardID,
-		Content: req.Content,
+		CardID:    cardID,
+		Checklist: strings.TrimSpace(req.Checklist),
+		Content:   req.Content,
 	}
 
 	if err := svc.ChecklistRepo.Create(item); err != nil {
 		return c.String(http.StatusInternalServerError, "Failed to create checklist item")
 	}
 
-	card, err := svc.CardRepo.GetByID(cardID)
-	if err == nil {
-		publishBoardEvent(h.hub, services.BoardEvent{
-			Type:     "checklist.updated",
-			BoardID:  req.BoardID,
-			CardID:   cardID,
-			ColumnID: card.ColumnID,
-			ClientID: requestClientID(c),
-		})
-	}
+	publishBoardEvent(h.hub, withCard(c, svc, services.BoardEvent{
+		Type:     "checklist.updated",
+		BoardID:  req.BoardID,
+		CardID:   cardID,
+		ColumnID: card.ColumnID,
+		ClientID: requestClientID(c),
+	}))
 
 	items, err := svc.ChecklistRepo.GetByCardID(cardID)
 	if err != nil {
@@ -92,10 +97,10 @@ func (h *ChecklistHandler) UpdateItem(c echo.Context) error {
 
 	svc, err := h.bm.GetServiceForBoard(req.BoardID)
 	if err != nil {
-		return c.String(http.StatusNotFound, "Board not found")
+		return boardServiceError(c, err)
 	}
 
-	item, err := svc.ChecklistRepo.GetByID(id)
+	item, err := svc.ChecklistItemInBoard(req.BoardID, id)
 	if err != nil {
 		return c.String(http.StatusNotFound, "Item not found")
 	}
@@ -111,13 +116,13 @@ func (h *ChecklistHandler) UpdateItem(c echo.Context) error {
 
 	card, err := svc.CardRepo.GetByID(item.CardID)
 	if err == nil {
-		publishBoardEvent(h.hub, services.BoardEvent{
+		publishBoardEvent(h.hub, withCard(c, svc, services.BoardEvent{
 			Type:     "checklist.updated",
 			BoardID:  req.BoardID,
 			CardID:   item.CardID,
 			ColumnID: card.ColumnID,
 			ClientID: requestClientID(c),
-		})
+		}))
 	}
 
 	items, err := svc.ChecklistRepo.GetByCardID(item.CardID)
@@ -138,10 +143,10 @@ func (h *ChecklistHandler) DeleteItem(c echo.Context) error {
 
 	svc, err := h.bm.GetServiceForBoard(boardID)
 	if err != nil {
-		return c.String(http.StatusNotFound, "Board not found")
+		return boardServiceError(c, err)
 	}
 
-	item, err := svc.ChecklistRepo.GetByID(id)
+	item, err := svc.ChecklistItemInBoard(boardID, id)
 	if err != nil {
 		return c.String(http.StatusNotFound, "Item not found")
 	}
@@ -154,13 +159,13 @@ func (h *ChecklistHandler) DeleteItem(c echo.Context) error {
 
 	card, err := svc.CardRepo.GetByID(cardID)
 	if err == nil {
-		publishBoardEvent(h.hub, services.BoardEvent{
+		publishBoardEvent(h.hub, withCard(c, svc, services.BoardEvent{
 			Type:     "checklist.updated",
 			BoardID:  boardID,
 			CardID:   cardID,
 			ColumnID: card.ColumnID,
 			ClientID: requestClientID(c),
-		})
+		}))
 	}
 
 	items, err := svc.ChecklistRepo.GetByCardID(cardID)
@@ -189,23 +194,29 @@ func (h *ChecklistHandler) ReorderItems(c echo.Context) error {
 
 	svc, err := h.bm.GetServiceForBoard(req.BoardID)
 	if err != nil {
-		return c.String(http.StatusNotFound, "Board not found")
+		return boardServiceError(c, err)
+	}
+
+	card, err := svc.CardInBoard(req.BoardID, cardID)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Card not found")
+	}
+
+	if err := svc.ChecklistItemsInCard(cardID, req.ItemIDs); err != nil {
+		return c.String(http.StatusNotFound, "Item not found")
 	}
 
 	if err := svc.ChecklistRepo.Reorder(cardID, req.ItemIDs); err != nil {
 		return c.String(http.StatusInternalServerError, "Failed to reorder checklist")
 	}
 
-	card, err := svc.CardRepo.GetByID(cardID)
-	if err == nil {
-		publishBoardEvent(h.hub, services.BoardEvent{
-			Type:     "checklist.updated",
-			BoardID:  req.BoardID,
-			CardID:   cardID,
-			ColumnID: card.ColumnID,
-			ClientID: requestClientID(c),
-		})
-	}
+	publishBoardEvent(h.hub, withCard(c, svc, services.BoardEvent{
+		Type:     "checklist.updated",
+		BoardID:  req.BoardID,
+		CardID:   cardID,
+		ColumnID: card.ColumnID,
+		ClientID: requestClientID(c),
+	}))
 
 	return c.NoContent(http.StatusOK)
 }
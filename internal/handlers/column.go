@@ -3,7 +3,6 @@ package handlers
 import (
 	"net/http"
 	"strconv"
-	"strings"
 
 	"krizzy/internal/models"
 	"krizzy/internal/services"
@@ -15,20 +14,31 @@ import (
 
 type ColumnHandler struct {
 	bm  *services.BoardManager
-	hub *services.BoardEventHub
+	hub services.BoardEventHub
 }
 
-func NewColumnHandler(bm *services.BoardManager, hub *services.BoardEventHub) *ColumnHandler {
+func NewColumnHandler(bm *services.BoardManager, hub services.BoardEventHub) *ColumnHandler {
 	return &ColumnHandler{bm: bm, hub: hub}
 }
 
 type CreateColumnRequest struct {
-	Name    string `form:"name"`
-	BoardID int64  `form:"board_id"`
+	Name       string `form:"name"`
+	BoardID    int64  `form:"board_id"`
+	IsDone     bool   `form:"is_done"`
+	Resolution string `form:"resolution"`
 }
 
-func isDoneColumnName(name string) bool {
-	return strings.EqualFold(strings.TrimSpace(name), "done")
+// doneResolution normalises the resolution submitted for a column. Only done
+// columns carry one, and they always get a label so cards can show it.
+func doneResolution(isDone bool, resolution string) string {
+	if !isDone {
+		return ""
+	}
+	resolution = validation.SanitizeName(resolution)
+	if resolution == "" {
+		return models.DefaultResolution
+	}
+	return resolution
 }
 
 func (h *ColumnHandler) CreateColumn(c echo.Context) error {
@@ -41,37 +51,40 @@ func (h *ColumnHandler) CreateColumn(c echo.Context) error {
 
 	svc, err := h.bm.GetServiceForBoard(req.BoardID)
 	if err != nil {
-		return c.String(http.StatusNotFound, "Board not found")
+		return boardServiceError(c, err)
 	}
 
 	column := &models.Column{
 		BoardID:      req.BoardID,
 		Name:         req.Name,
-		IsDoneColumn: isDoneColumnName(req.Name),
+		IsDoneColumn: req.IsDone,
+		Resolution:   doneResolution(req.IsDone, req.Resolution),
 	}
 
 	if err := svc.ColumnRepo.Create(column); err != nil {
 		return c.String(http.StatusInternalServerError, "Failed to create column")
 	}
 
-	publishBoardEvent(h.hub, services.BoardEvent{
-		Type:     "column.created",
-		BoardID:  req.BoardID,
-		ColumnID: column.ID,
-		ClientID: requestClientID(c),
-	})
-
 	board, err := svc.GetBoardWithData(req.BoardID)
 	if err != nil {
 		return c.String(http.StatusInternalServerError, "Failed to load board")
 	}
 
+	publishBoardEvent(h.hub, withColumn(c, board, services.BoardEvent{
+		Type:     "column.created",
+		BoardID:  req.BoardID,
+		ColumnID: column.ID,
+		ClientID: requestClientID(c),
+	}))
+
 	return templates.BoardContent(board).Render(c.Request().Context(), c.Response().Writer)
 }
 
 type UpdateColumnRequest struct {
-	Name    string `form:"name"`
-	BoardID int64  `form:"board_id"`
+	Name       string `form:"name"`
+	BoardID    int64  `form:"board_id"`
+	IsDone     bool   `form:"is_done"`
+	Resolution string `form:"resolution"`
 }
 
 func (h *ColumnHandler) UpdateColumn(c echo.Context) error {
@@ -92,36 +105,45 @@ func (h *ColumnHandler) UpdateColumn(c echo.Context) error {
 
 	svc, err := h.bm.GetServiceForBoard(req.BoardID)
 	if err != nil {
-		return c.String(http.StatusNotFound, "Board not found")
+		return boardServiceError(c, err)
 	}
 
-	column, err := svc.ColumnRepo.GetByID(id)
+	column, err := svc.ColumnInBoard(req.BoardID, id)
 	if err != nil {
 		return c.String(http.StatusNotFound, "Column not found")
 	}
 
 	column.Name = req.Name
-	column.IsDoneColumn = isDoneColumnName(req.Name)
+	column.IsDoneColumn = req.IsDone
+	column.Resolution = doneResolution(req.IsDone, req.Resolution)
 
-	if err := svc.ColumnRepo.Update(column); err != nil {
+	if err := svc.UpdateColumn(column); err != nil {
 		return c.String(http.StatusInternalServerError, "Failed to update column")
 	}
 
-	publishBoardEvent(h.hub, services.BoardEvent{
+	board, err := svc.GetBoardWithData(req.BoardID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load board")
+	}
+
+	publishBoardEvent(h.hub, withColumn(c, board, services.BoardEvent{
 		Type:     "column.updated",
 		BoardID:  req.BoardID,
 		ColumnID: column.ID,
 		ClientID: requestClientID(c),
-	})
+	}))
 
-	board, err := svc.GetBoardWithData(req.BoardID)
-	if err != nil {
-		return c.String(http.StatusInternalServerError, "Failed to load board")
+	// With swimlanes a column is split across rows, so there is no single
+	// element to swap
+	if board.HasLanes() {
+		c.Response().Header().Set("HX-Retarget", "#board-content")
+		c.Response().Header().Set("HX-Reswap", "innerHTML")
+		return templates.BoardContent(board).Render(c.Request().Context(), c.Response().Writer)
 	}
 
 	for i := range board.Columns {
 		if board.Columns[i].ID == column.ID {
-			return templates.ColumnComponent(&board.Columns[i], req.BoardID).Render(c.Request().Context(), c.Response().Writer)
+			return templates.ColumnComponent(&board.Columns[i], req.BoardID, board.Templates).Render(c.Request().Context(), c.Response().Writer)
 		}
 	}
 
@@ -138,10 +160,10 @@ func (h *ColumnHandler) DeleteColumn(c echo.Context) error {
 
 	svc, err := h.bm.GetServiceForBoard(boardID)
 	if err != nil {
-		return c.String(http.StatusNotFound, "Board not found")
+		return boardServiceError(c, err)
 	}
 
-	column, err := svc.ColumnRepo.GetByID(id)
+	column, err := svc.ColumnInBoard(boardID, id)
 	if err != nil {
 		return c.String(http.StatusNotFound, "Column not found")
 	}
@@ -178,7 +200,11 @@ func (h *ColumnHandler) ReorderColumns(c echo.Context) error {
 
 	svc, err := h.bm.GetServiceForBoard(req.BoardID)
 	if err != nil {
-		return c.String(http.StatusNotFound, "Board not found")
+		return boardServiceError(c, err)
+	}
+
+	if err := svc.ColumnsInBoard(req.BoardID, req.ColumnIDs); err != nil {
+		return c.String(http.StatusNotFound, "Column not found")
 	}
 
 	if err := svc.ColumnRepo.Reorder(req.BoardID, req.ColumnIDs); err != nil {
@@ -186,9 +212,10 @@ func (h *ColumnHandler) ReorderColumns(c echo.Context) error {
 	}
 
 	publishBoardEvent(h.hub, services.BoardEvent{
-		Type:     "column.reordered",
-		BoardID:  req.BoardID,
-		ClientID: requestClientID(c),
+		Type:      "column.reordered",
+		BoardID:   req.BoardID,
+		ColumnIDs: req.ColumnIDs,
+		ClientID:  requestClientID(c),
 	})
 
 	return c.NoContent(http.StatusOK)
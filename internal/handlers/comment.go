@@ -13,10 +13,10 @@ import (
 
 type CommentHandler struct {
 	bm  *services.BoardManager
-	hub *services.BoardEventHub
+	hub services.BoardEventHub
 }
 
-func NewCommentHandler(bm *services.BoardManager, hub *services.BoardEventHub) *CommentHandler {
+func NewCommentHandler(bm *services.BoardManager, hub services.BoardEventHub) *CommentHandler {
 	return &CommentHandler{bm: bm, hub: hub}
 }
 
@@ -42,7 +42,12 @@ func (h *CommentHandler) CreateComment(c echo.Context) error {
 
 	svc, err := h.bm.GetServiceForBoard(req.BoardID)
 	if err != nil {
-		return c.String(http.StatusNotFound, "Board not found")
+		return boardServiceError(c, err)
+	}
+
+	card, err := svc.CardInBoard(req.BoardID, cardID)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Card not found")
 	}
 
 	comment := &models.Comment{
@@ -54,16 +59,13 @@ func (h *CommentHandler) CreateComment(c echo.Context) error {
 		return c.String(http.StatusInternalServerError, "Failed to create comment")
 	}
 
-	card, err := svc.CardRepo.GetByID(cardID)
-	if err == nil {
-		publishBoardEvent(h.hub, services.BoardEvent{
-			Type:     "comment.updated",
-			BoardID:  req.BoardID,
-			CardID:   cardID,
-			ColumnID: card.ColumnID,
-			ClientID: requestClientID(c),
-		})
-	}
+	publishBoardEvent(h.hub, services.BoardEvent{
+		Type:     "comment.updated",
+		BoardID:  req.BoardID,
+		CardID:   cardID,
+		ColumnID: card.ColumnID,
+		ClientID: requestClientID(c),
+	})
 
 	comments, err := svc.CommentRepo.GetByCardID(cardID)
 	if err != nil {
@@ -83,10 +85,10 @@ func (h *CommentHandler) DeleteComment(c echo.Context) error {
 
 	svc, err := h.bm.GetServiceForBoard(boardID)
 	if err != nil {
-		return c.String(http.StatusNotFound, "Board not found")
+		return boardServiceError(c, err)
 	}
 
-	comment, err := svc.CommentRepo.GetByID(id)
+	comment, err := svc.CommentInBoard(boardID, id)
 	if err != nil {
 		return c.String(http.StatusNotFound, "Comment not found")
 	}
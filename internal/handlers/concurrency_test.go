@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"krizzy/internal/database"
+	"krizzy/internal/models"
+	"krizzy/internal/repository"
+	"krizzy/internal/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// TestConcurrentCardMovesOverHTTP is the services stress test with half the
+// clients going through POST /cards/:id/move, so form binding, the board
+// scope checks and event publishing run under the same load. Every move must
+// go through, every HTTP move must publish one event, moves that name another
+// board's card or column must be refused, and the board must stay consistent.
+func TestConcurrentCardMovesOverHTTP(t *testing.T) {
+	if testing.Short() {
+		t.Skip("stress test")
+	}
+
+	dir := t.TempDir()
+	db, err := database.NewSQLite(filepath.Join(dir, "krizzy.db"), database.DefaultSQLiteOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+	bm := services.NewBoardManager(db, repository.NewSQLiteBoardRepository(db.ReadWrite()), repository.NewSQLitePgConnectionRepository(db.DB()), filepath.Join(dir, "boards"))
+	t.Cleanup(bm.Close)
+
+	hub := services.NewMemoryEventHub()
+	e := echo.New()
+	e.POST("/cards/:id/move", NewCardHandler(bm, hub).MoveCard)
+
+	board, err := bm.CreateBoard("Stress", "local", nil, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := bm.CreateBoard("Other", "local", nil, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc, err := bm.GetServiceForBoard(board.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	columns, err := svc.ColumnRepo.GetByBoardID(board.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherColumns, err := svc.ColumnRepo.GetByBoardID(other.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	foreignColumn := otherColumns[0].ID
+
+	const cards = 40
+	for i := range cards {
+		card := &models.Card{ColumnID: columns[i%len(columns)].ID, Title: fmt.Sprintf("Card %d", i)}
+		if err := svc.CardRepo.Create(card); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	move := func(client int, cardID, boardID, columnID int64) *httptest.ResponseRecorder {
+		form := url.Values{}
+		form.Set("board_id", fmt.Sprint(boardID))
+		form.Set("column_id", fmt.Sprint(columnID))
+		form.Set("position", "0")
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/cards/%d/move", cardID), strings.NewReader(form.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		req.Header.Set("X-Client-ID", fmt.Sprintf("client-%d", client))
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// A browser watching the board; events it is too slow for are dropped
+	events, _ := hub.Subscribe(board.ID)
+	var received []services.BoardEvent
+	watched := make(chan struct{})
+	go func() {
+		defer close(watched)
+		for event := range events {
+			received = append(received, event)
+		}
+	}()
+
+	const clients = 32
+	const moves = 25
+	var wg sync.WaitGroup
+	var published atomic.Int64
+	errs := make(chan error, clients*moves)
+	for client := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rng := rand.New(rand.NewPCG(uint64(client), 0))
+			overHTTP := client%2 == 0
+			for i := range moves {
+				loaded, err := svc.GetBoardWithData(board.ID)
+				if err != nil {
+					errs <- fmt.Errorf("client %d: load board: %w", client, err)
+					return
+				}
+
+				from := loaded.Columns[rng.IntN(len(loaded.Columns))]
+				if len(from.Cards) == 0 {
+					continue
+				}
+				card := from.Cards[rng.IntN(len(from.Cards))]
+				to := loaded.Columns[rng.IntN(len(loaded.Columns))]
+
+				if !overHTTP {
+					if err := svc.MoveCard(card.ID, to.ID, nil, 0); err != nil {
+						errs <- fmt.Errorf("client %d: move card %d: %w", client, card.ID, err)
+						return
+					}
+					continue
+				}
+
+				// Now and then try to reach across boards first
+				if i%5 == 0 {
+					if rec := move(client, card.ID, board.ID, foreignColumn); rec.Code != http.StatusNotFound {
+						errs <- fmt.Errorf("client %d: move card %d to another board's column: status %d", client, card.ID, rec.Code)
+						return
+					}
+					if rec := move(client, card.ID, other.ID, to.ID); rec.Code != http.StatusNotFound {
+						errs <- fmt.Errorf("client %d: move card %d as another board's card: status %d", client, card.ID, rec.Code)
+						return
+					}
+				}
+
+				if rec := move(client, card.ID, board.ID, to.ID); rec.Code != http.StatusOK {
+					errs <- fmt.Errorf("client %d: move card %d: status %d: %s", client, card.ID, rec.Code, rec.Body)
+					return
+				}
+				published.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	hub.Unsubscribe(board.ID, events)
+	<-watched
+	if len(received) == 0 {
+		t.Error("no events reached the board's subscriber")
+	}
+	for _, event := range received {
+		if event.Type != "card.moved" || event.HTML == "" || !strings.HasPrefix(event.ClientID, "client-") {
+			t.Errorf("unexpected event %+v", event)
+		}
+	}
+
+	// The hub numbers every event it delivers, so its version counts them
+	ch, version := hub.Subscribe(board.ID)
+	hub.Unsubscribe(board.ID, ch)
+	if version != published.Load() {
+		t.Errorf("hub delivered %d events for %d moves over HTTP", version, published.Load())
+	}
+	ch, version = hub.Subscribe(other.ID)
+	hub.Unsubscribe(other.ID, ch)
+	if version != 0 {
+		t.Errorf("refused moves published %d events on the other board", version)
+	}
+
+	loaded, err := svc.GetBoardWithData(board.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := 0
+	for _, column := range loaded.Columns {
+		total += len(column.Cards)
+	}
+	if total != cards {
+		t.Errorf("board has %d cards after the moves, want %d", total, cards)
+	}
+
+	issues, err := repository.NewSQLiteIntegrityRepository(db.ReadWrite()).Check(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, issue := range issues {
+		t.Errorf("integrity: [%s] %s: %s", issue.Check, issue.Table, issue.Detail)
+	}
+}
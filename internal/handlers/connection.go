@@ -81,6 +81,79 @@ func (h *ConnectionHandler) CreateConnection(c echo.Context) error {
 	return templates.ConnectionsList(connections).Render(c.Request().Context(), c.Response().Writer)
 }
 
+func (h *ConnectionHandler) GetEditForm(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid connection ID")
+	}
+
+	conn, err := h.bm.PgConnRepo().GetByID(id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Connection not found")
+	}
+
+	return templates.ConnectionEditForm(conn).Render(c.Request().Context(), c.Response().Writer)
+}
+
+// UpdateConnectionRequest mirrors CreateConnectionRequest; a blank password
+// keeps the stored one so editing a host doesn't require retyping secrets.
+type UpdateConnectionRequest struct {
+	Name     string `form:"name"`
+	Host     string `form:"host"`
+	Port     int    `form:"port"`
+	User     string `form:"user"`
+	Password string `form:"password"`
+	SSLMode  string `form:"ssl_mode"`
+}
+
+func (h *ConnectionHandler) UpdateConnection(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid connection ID")
+	}
+
+	var req UpdateConnectionRequest
+	if err := c.Bind(&req); err != nil {
+		return c.String(http.StatusBadRequest, "Invalid request")
+	}
+
+	req.Name = validation.SanitizeName(req.Name)
+	if req.Name == "" {
+		return c.String(http.StatusBadRequest, "Name is required")
+	}
+	if req.Host == "" {
+		return c.String(http.StatusBadRequest, "Host is required")
+	}
+	if req.User == "" {
+		return c.String(http.StatusBadRequest, "User is required")
+	}
+
+	conn, err := h.bm.PgConnRepo().GetByID(id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Connection not found")
+	}
+
+	conn.Name = req.Name
+	conn.Host = req.Host
+	conn.Port = req.Port
+	conn.User = req.User
+	conn.SSLMode = req.SSLMode
+	if req.Password != "" {
+		conn.Password = req.Password
+	}
+
+	if err := h.bm.UpdateConnection(conn); err != nil {
+		return c.String(http.StatusBadRequest, "Connection failed: "+err.Error())
+	}
+
+	connections, err := h.bm.PgConnRepo().GetAll()
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load connections")
+	}
+
+	return templates.ConnectionsList(connections).Render(c.Request().Context(), c.Response().Writer)
+}
+
 func (h *ConnectionHandler) TestConnection(c echo.Context) error {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"krizzy/internal/models"
+	"krizzy/internal/services"
+	"krizzy/internal/validation"
+	"krizzy/templates"
+
+	"github.com/labstack/echo/v4"
+)
+
+type FieldHandler struct {
+	bm  *services.BoardManager
+	hub services.BoardEventHub
+}
+
+func NewFieldHandler(bm *services.BoardManager, hub services.BoardEventHub) *FieldHandler {
+	return &FieldHandler{bm: bm, hub: hub}
+}
+
+// parseFieldOptions reads select choices entered one per line
+func parseFieldOptions(raw string) []string {
+	seen := make(map[string]bool)
+	var options []string
+	for _, line := range strings.Split(raw, "\n") {
+		option := validation.SanitizeName(line)
+		if option == "" || seen[option] {
+			continue
+		}
+		seen[option] = true
+		options = append(options, option)
+	}
+	return options
+}
+
+func (h *FieldHandler) GetFieldsModal(c echo.Context) error {
+	boardID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid board ID")
+	}
+
+	svc, err := h.bm.GetServiceForBoard(boardID)
+	if err != nil {
+		return boardServiceError(c, err)
+	}
+
+	fields, err := svc.FieldRepo.GetByBoardID(boardID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load fields")
+	}
+
+	return templates.FieldsModal(fields, boardID).Render(c.Request().Context(), c.Response().Writer)
+}
+
+type CreateFieldRequest struct {
+	Name    string `form:"name"`
+	Type    string `form:"type"`
+	Options string `form:"options"`
+	BoardID int64  `form:"board_id"`
+}
+
+func (h *FieldHandler) CreateField(c echo.Context) error {
+	var req CreateFieldRequest
+	if err := c.Bind(&req); err != nil {
+		return c.String(http.StatusBadRequest, "Invalid request")
+	}
+
+	req.Name = validation.SanitizeName(req.Name)
+	if req.Name == "" {
+		return c.String(http.StatusBadRequest, "Name is required")
+	}
+	if !services.IsFieldType(req.Type) {
+		return c.String(http.StatusBadRequest, "Unknown field type")
+	}
+
+	field := &models.CustomField{
+		BoardID: req.BoardID,
+		Name:    req.Name,
+		Type:    req.Type,
+	}
+	if field.HasOptions() {
+		field.Options = parseFieldOptions(req.Options)
+		if len(field.Options) == 0 {
+			return c.String(http.StatusBadRequest, "Select fields need at least one option")
+		}
+	}
+
+	svc, err := h.bm.GetServiceForBoard(req.BoardID)
+	if err != nil {
+		return boardServiceError(c, err)
+	}
+
+	if err := svc.FieldRepo.Create(field); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to create field")
+	}
+
+	return h.renderFields(c, svc, req.BoardID)
+}
+
+type UpdateFieldRequest struct {
+	Name    string `form:"name"`
+	Options string `form:"options"`
+	BoardID int64  `form:"board_id"`
+}
+
+func (h *FieldHandler) UpdateField(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid field ID")
+	}
+
+	var req UpdateFieldRequest
+	if err := c.Bind(&req); err != nil {
+		return c.String(http.StatusBadRequest, "Invalid request")
+	}
+
+	req.Name = validation.SanitizeName(req.Name)
+	if req.Name == "" {
+		return c.String(http.StatusBadRequest, "Name is required")
+	}
+
+	svc, err := h.bm.GetServiceForBoard(req.BoardID)
+	if err != nil {
+		return boardServiceError(c, err)
+	}
+
+	field, err := svc.FieldInBoard(req.BoardID, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Field not found")
+	}
+
+	field.Name = req.Name
+	if field.HasOptions() {
+		field.Options = parseFieldOptions(req.Options)
+		if len(field.Options) == 0 {
+			return c.String(http.StatusBadRequest, "Select fields need at least one option")
+		}
+	}
+
+	if err := svc.FieldRepo.Update(field); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to update field")
+	}
+
+	return h.renderFields(c, svc, req.BoardID)
+}
+
+func (h *FieldHandler) DeleteField(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid field ID")
+	}
+
+	boardID, _ := strconv.ParseInt(c.QueryParam("board_id"), 10, 64)
+
+	svc, err := h.bm.GetServiceForBoard(boardID)
+	if err != nil {
+		return boardServiceError(c, err)
+	}
+
+	if _, err := svc.FieldInBoard(boardID, id); err != nil {
+		return c.String(http.StatusNotFound, "Field not found")
+	}
+
+	if err := svc.FieldRepo.Delete(id); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to delete field")
+	}
+
+	return h.renderFields(c, svc, boardID)
+}
+
+type SetCardFieldRequest struct {
+	Values  []string `form:"value"`
+	BoardID int64    `form:"board_id"`
+}
+
+// SetCardField stores one card's value for a custom field
+func (h *FieldHandler) SetCardField(c echo.Context) error {
+	cardID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid card ID")
+	}
+	fieldID, err := strconv.ParseInt(c.Param("fieldId"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid field ID")
+	}
+
+	var req SetCardFieldRequest
+	if err := c.Bind(&req); err != nil {
+		return c.String(http.StatusBadRequest, "Invalid request")
+	}
+
+	svc, err := h.bm.GetServiceForBoard(req.BoardID)
+	if err != nil {
+		return boardServiceError(c, err)
+	}
+
+	card, err := svc.CardInBoard(req.BoardID, cardID)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Card not found")
+	}
+
+	field, err := svc.FieldInBoard(req.BoardID, fieldID)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Field not found")
+	}
+
+	value, err := services.NormalizeFieldValue(field, req.Values)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidFieldValue) {
+			return c.String(http.StatusBadRequest, err.Error())
+		}
+		return c.String(http.StatusInternalServerError, "Failed to update field")
+	}
+
+	if err := svc.FieldRepo.SetValue(cardID, fieldID, value); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to update field")
+	}
+
+	publishBoardEvent(h.hub, withCard(c, svc, services.BoardEvent{
+		Type:     "card.updated",
+		BoardID:  req.BoardID,
+		CardID:   cardID,
+		ColumnID: card.ColumnID,
+		ClientID: requestClientID(c),
+	}))
+
+	cardWithDetails, err := svc.GetCardWithDetails(cardID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load card")
+	}
+
+	fields, err := svc.FieldRepo.GetByBoardID(req.BoardID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load fields")
+	}
+
+	return templates.CardFieldsEditor(cardWithDetails, fields, req.BoardID).Render(c.Request().Context(), c.Response().Writer)
+}
+
+// renderFields publishes a field definition change and re-renders the field
+// list. Cards show field values, so open boards refresh their columns too.
+func (h *FieldHandler) renderFields(c echo.Context, svc *services.KanbanService, boardID int64) error {
+	publishBoardEvent(h.hub, services.BoardEvent{
+		Type:     "fields.updated",
+		BoardID:  boardID,
+		ClientID: requestClientID(c),
+	})
+
+	fields, err := svc.FieldRepo.GetByBoardID(boardID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load fields")
+	}
+
+	return templates.FieldsList(fields, boardID).Render(c.Request().Context(), c.Response().Writer)
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"krizzy/internal/models"
+	"krizzy/internal/services"
+	"krizzy/internal/validation"
+	"krizzy/templates"
+
+	"github.com/labstack/echo/v4"
+)
+
+type LinkHandler struct {
+	bm  *services.BoardManager
+	hub services.BoardEventHub
+}
+
+func NewLinkHandler(bm *services.BoardManager, hub services.BoardEventHub) *LinkHandler {
+	return &LinkHandler{bm: bm, hub: hub}
+}
+
+type CreateLinkRequest struct {
+	Title   string `form:"title"`
+	URL     string `form:"url"`
+	BoardID int64  `form:"board_id"`
+}
+
+func (h *LinkHandler) CreateLink(c echo.Context) error {
+	cardID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid card ID")
+	}
+
+	var req CreateLinkRequest
+	if err := c.Bind(&req); err != nil {
+		return c.String(http.StatusBadRequest, "Invalid request")
+	}
+
+	linkURL, ok := validation.NormalizeLinkURL(req.URL)
+	if !ok {
+		return c.String(http.StatusBadRequest, "Link must be an http or https URL")
+	}
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		title = linkURL
+	}
+
+	svc, err := h.bm.GetServiceForBoard(req.BoardID)
+	if err != nil {
+		return boardServiceError(c, err)
+	}
+
+	card, err := svc.CardInBoard(req.BoardID, cardID)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Card not found")
+	}
+
+	link := &models.CardLink{
+		CardID: cardID,
+		Title:  title,
+		URL:    linkURL,
+	}
+	if err := svc.LinkRepo.Create(link); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to add link")
+	}
+
+	publishBoardEvent(h.hub, withCard(c, svc, services.BoardEvent{
+		Type:     "card.updated",
+		BoardID:  req.BoardID,
+		CardID:   cardID,
+		ColumnID: card.ColumnID,
+		ClientID: requestClientID(c),
+	}))
+
+	links, err := svc.LinkRepo.GetByCardID(cardID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load links")
+	}
+
+	return templates.LinksList(cardID, links, req.BoardID).Render(c.Request().Context(), c.Response().Writer)
+}
+
+func (h *LinkHandler) DeleteLink(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid link ID")
+	}
+
+	boardID, _ := strconv.ParseInt(c.QueryParam("board_id"), 10, 64)
+
+	svc, err := h.bm.GetServiceForBoard(boardID)
+	if err != nil {
+		return boardServiceError(c, err)
+	}
+
+	link, err := svc.LinkInBoard(boardID, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Link not found")
+	}
+
+	if err := svc.LinkRepo.Delete(id); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to delete link")
+	}
+
+	card, err := svc.CardRepo.GetByID(link.CardID)
+	if err == nil {
+		publishBoardEvent(h.hub, withCard(c, svc, services.BoardEvent{
+			Type:     "card.updated",
+			BoardID:  boardID,
+			CardID:   link.CardID,
+			ColumnID: card.ColumnID,
+			ClientID: requestClientID(c),
+		}))
+	}
+
+	links, err := svc.LinkRepo.GetByCardID(link.CardID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load links")
+	}
+
+	return templates.LinksList(link.CardID, links, boardID).Render(c.Request().Context(), c.Response().Writer)
+}
@@ -28,7 +28,11 @@ func (h *ModalHandler) GetCardModal(c echo.Context) error {
 
 	svc, err := h.bm.GetServiceForBoard(boardID)
 	if err != nil {
-		return c.String(http.StatusNotFound, "Board not found")
+		return boardServiceError(c, err)
+	}
+
+	if _, err := svc.CardInBoard(boardID, id); err != nil {
+		return c.String(http.StatusNotFound, "Card not found")
 	}
 
 	card, err := svc.GetCardWithDetails(id)
@@ -41,5 +45,10 @@ func (h *ModalHandler) GetCardModal(c echo.Context) error {
 		return c.String(http.StatusInternalServerError, "Failed to load people")
 	}
 
-	return templates.CardModal(card, people, boardID).Render(c.Request().Context(), c.Response().Writer)
+	fields, err := svc.FieldRepo.GetByBoardID(boardID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load fields")
+	}
+
+	return templates.CardModal(card, people, fields, boardID).Render(c.Request().Context(), c.Response().Writer)
 }
@@ -14,10 +14,10 @@ import (
 
 type PersonHandler struct {
 	bm  *services.BoardManager
-	hub *services.BoardEventHub
+	hub services.BoardEventHub
 }
 
-func NewPersonHandler(bm *services.BoardManager, hub *services.BoardEventHub) *PersonHandler {
+func NewPersonHandler(bm *services.BoardManager, hub services.BoardEventHub) *PersonHandler {
 	return &PersonHandler{bm: bm, hub: hub}
 }
 
@@ -45,7 +45,7 @@ func (h *PersonHandler) CreatePerson(c echo.Context) error {
 
 	svc, err := h.bm.GetServiceForBoard(req.BoardID)
 	if err != nil {
-		return c.String(http.StatusNotFound, "Board not found")
+		return boardServiceError(c, err)
 	}
 
 	person := &models.Person{
@@ -90,17 +90,16 @@ func (h *PersonHandler) UpdatePerson(c echo.Context) error {
 
 	svc, err := h.bm.GetServiceForBoard(req.BoardID)
 	if err != nil {
-		return c.String(http.StatusNotFound, "Board not found")
+		return boardServiceError(c, err)
 	}
 
-	person, err := svc.PersonRepo.GetByID(id)
+	person, err := svc.PersonInBoard(req.BoardID, id)
 	if err != nil {
 		return c.String(http.StatusNotFound, "Person not found")
 	}
 
 	person.Name = req.Name
 	person.Color = req.Color
-	person.BoardID = req.BoardID
 
 	if err := svc.PersonRepo.Update(person); err != nil {
 		return c.String(http.StatusInternalServerError, "Failed to update person")
@@ -134,10 +133,14 @@ func (h *PersonHandler) DeletePerson(c echo.Context) error {
 
 	svc, err := h.bm.GetServiceForBoard(boardID)
 	if err != nil {
-		return c.String(http.StatusNotFound, "Board not found")
+		return boardServiceError(c, err)
 	}
 
-	if err := svc.PersonRepo.Delete(id); err != nil {
+	if _, err := svc.PersonInBoard(boardID, id); err != nil {
+		return c.String(http.StatusNotFound, "Person not found")
+	}
+
+	if err := svc.DeletePerson(id); err != nil {
 		return c.String(http.StatusInternalServerError, "Failed to delete person")
 	}
 
@@ -5,21 +5,23 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"krizzy/internal/models"
 	"krizzy/internal/services"
 	"krizzy/templates"
 
+	"github.com/a-h/templ"
 	"github.com/labstack/echo/v4"
 )
 
 type RealtimeHandler struct {
 	bm  *services.BoardManager
-	hub *services.BoardEventHub
+	hub services.BoardEventHub
 }
 
-func NewRealtimeHandler(bm *services.BoardManager, hub *services.BoardEventHub) *RealtimeHandler {
+func NewRealtimeHandler(bm *services.BoardManager, hub services.BoardEventHub) *RealtimeHandler {
 	return &RealtimeHandler{bm: bm, hub: hub}
 }
 
@@ -30,7 +32,7 @@ func (h *RealtimeHandler) StreamBoardEvents(c echo.Context) error {
 	}
 
 	if _, err := h.bm.GetServiceForBoard(boardID); err != nil {
-		return c.String(http.StatusNotFound, "Board not found")
+		return boardServiceError(c, err)
 	}
 
 	res := c.Response()
@@ -41,10 +43,12 @@ func (h *RealtimeHandler) StreamBoardEvents(c echo.Context) error {
 	res.WriteHeader(http.StatusOK)
 	res.Flush()
 
-	ch := h.hub.Subscribe(boardID)
+	ch, version := h.hub.Subscribe(boardID)
 	defer h.hub.Unsubscribe(boardID, ch)
 
-	if _, err := fmt.Fprint(res, ": connected\n\n"); err != nil {
+	// A client that reconnects compares this with the last version it saw to
+	// tell whether it missed anything while it was away
+	if _, err := fmt.Fprintf(res, "event: board-version\ndata: {\"board_id\":%d,\"version\":%d}\n\n", boardID, version); err != nil {
 		return nil
 	}
 	res.Flush()
@@ -95,11 +99,7 @@ func (h *RealtimeHandler) GetColumn(c echo.Context) error {
 		return err
 	}
 
-	column, err := svc.ColumnRepo.GetByID(columnID)
-	if err != nil {
-		return c.String(http.StatusNotFound, "Column not found")
-	}
-	if column.BoardID != boardID {
+	if _, err := svc.ColumnInBoard(boardID, columnID); err != nil {
 		return c.String(http.StatusNotFound, "Column not found")
 	}
 
@@ -110,7 +110,7 @@ func (h *RealtimeHandler) GetColumn(c echo.Context) error {
 
 	for i := range board.Columns {
 		if board.Columns[i].ID == columnID {
-			return templates.ColumnComponent(&board.Columns[i], boardID).Render(c.Request().Context(), c.Response().Writer)
+			return templates.ColumnComponent(&board.Columns[i], boardID, board.Templates).Render(c.Request().Context(), c.Response().Writer)
 		}
 	}
 
@@ -123,13 +123,12 @@ func (h *RealtimeHandler) GetCard(c echo.Context) error {
 		return err
 	}
 
-	card, err := svc.GetCardWithDetails(cardID)
-	if err != nil {
+	if _, err := svc.CardInBoard(boardID, cardID); err != nil {
 		return c.String(http.StatusNotFound, "Card not found")
 	}
 
-	column, err := svc.ColumnRepo.GetByID(card.ColumnID)
-	if err != nil || column.BoardID != boardID {
+	card, err := svc.GetCardWithDetails(cardID)
+	if err != nil {
 		return c.String(http.StatusNotFound, "Card not found")
 	}
 
@@ -144,7 +143,7 @@ func (h *RealtimeHandler) loadBoard(c echo.Context) (*models.Board, error) {
 
 	svc, err := h.bm.GetServiceForBoard(boardID)
 	if err != nil {
-		return nil, c.String(http.StatusNotFound, "Board not found")
+		return nil, boardServiceError(c, err)
 	}
 
 	board, err := svc.GetBoardWithData(boardID)
@@ -168,7 +167,7 @@ func (h *RealtimeHandler) loadBoardAndColumn(c echo.Context) (int64, int64, *ser
 
 	svc, err := h.bm.GetServiceForBoard(boardID)
 	if err != nil {
-		return 0, 0, nil, c.String(http.StatusNotFound, "Board not found")
+		return 0, 0, nil, boardServiceError(c, err)
 	}
 
 	return boardID, columnID, svc, nil
@@ -187,19 +186,66 @@ func (h *RealtimeHandler) loadBoardAndCard(c echo.Context) (int64, int64, *servi
 
 	svc, err := h.bm.GetServiceForBoard(boardID)
 	if err != nil {
-		return 0, 0, nil, c.String(http.StatusNotFound, "Board not found")
+		return 0, 0, nil, boardServiceError(c, err)
 	}
 
 	return boardID, cardID, svc, nil
 }
 
-func publishBoardEvent(hub *services.BoardEventHub, event services.BoardEvent) {
+func publishBoardEvent(hub services.BoardEventHub, event services.BoardEvent) {
 	if hub == nil {
 		return
 	}
 	hub.Publish(event)
 }
 
+// withCard adds the card as rendered on the board to a card event, with the
+// cell and position it sits at. If the card cannot be loaded the event goes
+// out as it is and clients fetch the card's column.
+func withCard(c echo.Context, svc *services.KanbanService, event services.BoardEvent) services.BoardEvent {
+	card, err := svc.GetCardWithDetails(event.CardID)
+	if err != nil {
+		return event
+	}
+
+	event.ColumnID = card.ColumnID
+	event.SwimlaneID = 0
+	if card.SwimlaneID != nil {
+		event.SwimlaneID = *card.SwimlaneID
+	}
+	event.Position = card.Position
+	event.HTML = renderFragment(c, templates.CardComponent(card, event.BoardID))
+	return event
+}
+
+// withColumn adds a column as rendered on the board to a column event.
+// With swimlanes a column is split across rows, so there is no single
+// element to send and clients fetch the columns container.
+func withColumn(c echo.Context, board *models.Board, event services.BoardEvent) services.BoardEvent {
+	if board.HasLanes() {
+		return event
+	}
+
+	for i := range board.Columns {
+		if board.Columns[i].ID == event.ColumnID {
+			event.Position = i
+			event.HTML = renderFragment(c, templates.ColumnComponent(&board.Columns[i], board.ID, board.Templates))
+			break
+		}
+	}
+	return event
+}
+
+// renderFragment renders a component into an event. A fragment that fails
+// to render is left out.
+func renderFragment(c echo.Context, component templ.Component) string {
+	var buf strings.Builder
+	if err := component.Render(c.Request().Context(), &buf); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
 func requestClientID(c echo.Context) string {
 	return c.Request().Header.Get("X-Client-ID")
 }
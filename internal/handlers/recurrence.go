@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"krizzy/internal/models"
+	"krizzy/internal/services"
+	"krizzy/templates"
+
+	"github.com/labstack/echo/v4"
+)
+
+type RecurrenceHandler struct {
+	bm *services.BoardManager
+}
+
+func NewRecurrenceHandler(bm *services.BoardManager) *RecurrenceHandler {
+	return &RecurrenceHandler{bm: bm}
+}
+
+func (h *RecurrenceHandler) GetRecurrencesModal(c echo.Context) error {
+	boardID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid board ID")
+	}
+
+	svc, err := h.bm.GetServiceForBoard(boardID)
+	if err != nil {
+		return boardServiceError(c, err)
+	}
+
+	return h.render(c, svc, boardID, true)
+}
+
+type CreateRecurrenceRequest struct {
+	TemplateID int64  `form:"template_id"`
+	ColumnID   int64  `form:"column_id"`
+	Schedule   string `form:"schedule"`
+	BoardID    int64  `form:"board_id"`
+}
+
+func (h *RecurrenceHandler) CreateRecurrence(c echo.Context) error {
+	var req CreateRecurrenceRequest
+	if err := c.Bind(&req); err != nil {
+		return c.String(http.StatusBadRequest, "Invalid request")
+	}
+
+	req.Schedule = strings.TrimSpace(req.Schedule)
+	next, err := services.NextOccurrence(req.Schedule, time.Now())
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	if next == nil {
+		return c.String(http.StatusBadRequest, "Schedule never fires")
+	}
+
+	svc, err := h.bm.GetServiceForBoard(req.BoardID)
+	if err != nil {
+		return boardServiceError(c, err)
+	}
+
+	if _, err := svc.TemplateInBoard(req.BoardID, req.TemplateID); err != nil {
+		return c.String(http.StatusNotFound, "Template not found")
+	}
+	if _, err := svc.ColumnInBoard(req.BoardID, req.ColumnID); err != nil {
+		return c.String(http.StatusNotFound, "Column not found")
+	}
+
+	rec := &models.Recurrence{
+		BoardID:    req.BoardID,
+		TemplateID: req.TemplateID,
+		ColumnID:   req.ColumnID,
+		Schedule:   req.Schedule,
+		Enabled:    true,
+		NextRunAt:  next,
+	}
+	if err := svc.RecurrenceRepo.Create(rec); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to create recurrence")
+	}
+
+	return h.render(c, svc, req.BoardID, false)
+}
+
+// ToggleRecurrence pauses or resumes a recurrence. Resuming starts from the
+// next occurrence after now rather than catching up on the paused period.
+func (h *RecurrenceHandler) ToggleRecurrence(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid recurrence ID")
+	}
+
+	boardID, _ := strconv.ParseInt(c.FormValue("board_id"), 10, 64)
+
+	svc, err := h.bm.GetServiceForBoard(boardID)
+	if err != nil {
+		return boardServiceError(c, err)
+	}
+
+	rec, err := svc.RecurrenceInBoard(boardID, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Recurrence not found")
+	}
+
+	rec.Enabled = !rec.Enabled
+	if rec.Enabled {
+		next, err := services.NextOccurrence(rec.Schedule, time.Now())
+		if err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to update recurrence")
+		}
+		rec.NextRunAt = next
+	}
+
+	if err := svc.RecurrenceRepo.Update(rec); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to update recurrence")
+	}
+
+	return h.render(c, svc, boardID, false)
+}
+
+func (h *RecurrenceHandler) DeleteRecurrence(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid recurrence ID")
+	}
+
+	boardID, _ := strconv.ParseInt(c.QueryParam("board_id"), 10, 64)
+
+	svc, err := h.bm.GetServiceForBoard(boardID)
+	if err != nil {
+		return boardServiceError(c, err)
+	}
+
+	if _, err := svc.RecurrenceInBoard(boardID, id); err != nil {
+		return c.String(http.StatusNotFound, "Recurrence not found")
+	}
+
+	if err := svc.RecurrenceRepo.Delete(id); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to delete recurrence")
+	}
+
+	return h.render(c, svc, boardID, false)
+}
+
+// render draws the whole modal, or just the list inside it after a change
+func (h *RecurrenceHandler) render(c echo.Context, svc *services.KanbanService, boardID int64, modal bool) error {
+	recurrences, err := svc.RecurrenceRepo.GetByBoardID(boardID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load recurrences")
+	}
+	cardTemplates, err := svc.TemplateRepo.GetByBoardID(boardID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load templates")
+	}
+	columns, err := svc.ColumnRepo.GetByBoardID(boardID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load columns")
+	}
+
+	if modal {
+		return templates.RecurrencesModal(recurrences, cardTemplates, columns, boardID).Render(c.Request().Context(), c.Response().Writer)
+	}
+	return templates.RecurrencesList(recurrences, cardTemplates, columns, boardID).Render(c.Request().Context(), c.Response().Writer)
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"krizzy/internal/models"
+	"krizzy/internal/services"
+	"krizzy/internal/validation"
+	"krizzy/templates"
+
+	"github.com/labstack/echo/v4"
+)
+
+type SwimlaneHandler struct {
+	bm  *services.BoardManager
+	hub services.BoardEventHub
+}
+
+func NewSwimlaneHandler(bm *services.BoardManager, hub services.BoardEventHub) *SwimlaneHandler {
+	return &SwimlaneHandler{bm: bm, hub: hub}
+}
+
+// swimlaneInBoard resolves a submitted swimlane ID, where 0 means the
+// board's unlaned row
+func swimlaneInBoard(svc *services.KanbanService, boardID, swimlaneID int64) (*int64, error) {
+	if swimlaneID == 0 {
+		return nil, nil
+	}
+	lane, err := svc.SwimlaneInBoard(boardID, swimlaneID)
+	if err != nil {
+		return nil, err
+	}
+	return &lane.ID, nil
+}
+
+type CreateSwimlaneRequest struct {
+	Name    string `form:"name"`
+	BoardID int64  `form:"board_id"`
+}
+
+func (h *SwimlaneHandler) CreateSwimlane(c echo.Context) error {
+	var req CreateSwimlaneRequest
+	if err := c.Bind(&req); err != nil {
+		return c.String(http.StatusBadRequest, "Invalid request")
+	}
+
+	req.Name = validation.SanitizeName(req.Name)
+	if req.Name == "" {
+		return c.String(http.StatusBadRequest, "Name is required")
+	}
+
+	svc, err := h.bm.GetServiceForBoard(req.BoardID)
+	if err != nil {
+		return boardServiceError(c, err)
+	}
+
+	lane := &models.Swimlane{
+		BoardID: req.BoardID,
+		Name:    req.Name,
+	}
+
+	if err := svc.SwimlaneRepo.Create(lane); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to create swimlane")
+	}
+
+	return h.renderBoard(c, svc, req.BoardID)
+}
+
+type UpdateSwimlaneRequest struct {
+	Name    string `form:"name"`
+	BoardID int64  `form:"board_id"`
+}
+
+func (h *SwimlaneHandler) UpdateSwimlane(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid swimlane ID")
+	}
+
+	var req UpdateSwimlaneRequest
+	if err := c.Bind(&req); err != nil {
+		return c.String(http.StatusBadRequest, "Invalid request")
+	}
+
+	req.Name = validation.SanitizeName(req.Name)
+	if req.Name == "" {
+		return c.String(http.StatusBadRequest, "Name is required")
+	}
+
+	svc, err := h.bm.GetServiceForBoard(req.BoardID)
+	if err != nil {
+		return boardServiceError(c, err)
+	}
+
+	lane, err := svc.SwimlaneInBoard(req.BoardID, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Swimlane not found")
+	}
+
+	lane.Name = req.Name
+	if err := svc.SwimlaneRepo.Update(lane); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to update swimlane")
+	}
+
+	return h.renderBoard(c, svc, req.BoardID)
+}
+
+// ToggleSwimlane collapses or expands a swimlane. The state is stored with
+// the lane so it survives reloads and is shared by everyone on the board.
+func (h *SwimlaneHandler) ToggleSwimlane(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid swimlane ID")
+	}
+
+	boardID, _ := strconv.ParseInt(c.FormValue("board_id"), 10, 64)
+
+	svc, err := h.bm.GetServiceForBoard(boardID)
+	if err != nil {
+		return boardServiceError(c, err)
+	}
+
+	lane, err := svc.SwimlaneInBoard(boardID, id)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Swimlane not found")
+	}
+
+	lane.Collapsed = !lane.Collapsed
+	if err := svc.SwimlaneRepo.Update(lane); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to update swimlane")
+	}
+
+	return h.renderBoard(c, svc, boardID)
+}
+
+func (h *SwimlaneHandler) DeleteSwimlane(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid swimlane ID")
+	}
+
+	boardID, _ := strconv.ParseInt(c.QueryParam("board_id"), 10, 64)
+
+	svc, err := h.bm.GetServiceForBoard(boardID)
+	if err != nil {
+		return boardServiceError(c, err)
+	}
+
+	if _, err := svc.SwimlaneInBoard(boardID, id); err != nil {
+		return c.String(http.StatusNotFound, "Swimlane not found")
+	}
+
+	if err := svc.SwimlaneRepo.Delete(id); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to delete swimlane")
+	}
+
+	return h.renderBoard(c, svc, boardID)
+}
+
+type SetLaneGroupingRequest struct {
+	Grouping string `form:"grouping"`
+}
+
+// SetLaneGrouping switches the board between its stored swimlanes and the
+// dynamic by-assignee view
+func (h *SwimlaneHandler) SetLaneGrouping(c echo.Context) error {
+	boardID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid board ID")
+	}
+
+	var req SetLaneGroupingRequest
+	if err := c.Bind(&req); err != nil {
+		return c.String(http.StatusBadRequest, "Invalid request")
+	}
+	if req.Grouping != "" && req.Grouping != models.LaneGroupingAssignee {
+		return c.String(http.StatusBadRequest, "Unknown lane grouping")
+	}
+
+	svc, err := h.bm.GetServiceForBoard(boardID)
+	if err != nil {
+		return boardServiceError(c, err)
+	}
+
+	if err := h.bm.SetLaneGrouping(boardID, req.Grouping); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to update board")
+	}
+
+	return h.renderBoard(c, svc, boardID)
+}
+
+// renderBoard publishes the lane change and re-renders the board. Lane edits
+// reshape every column, so clients refresh the whole columns container.
+func (h *SwimlaneHandler) renderBoard(c echo.Context, svc *services.KanbanService, boardID int64) error {
+	publishBoardEvent(h.hub, services.BoardEvent{
+		Type:     "swimlane.updated",
+		BoardID:  boardID,
+		ClientID: requestClientID(c),
+	})
+
+	board, err := svc.GetBoardWithData(boardID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load board")
+	}
+
+	return templates.BoardContent(board).Render(c.Request().Context(), c.Response().Writer)
+}
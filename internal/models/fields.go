@@ -0,0 +1,58 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Custom field types. Values are stored as text: numbers in decimal form,
+// dates as YYYY-MM-DD, checkboxes as "true" and multi-selects as a JSON array.
+const (
+	FieldTypeText         = "text"
+	FieldTypeNumber       = "number"
+	FieldTypeDate         = "date"
+	FieldTypeSingleSelect = "single_select"
+	FieldTypeMultiSelect  = "multi_select"
+	FieldTypeCheckbox     = "checkbox"
+)
+
+// FieldTypes lists the custom field types in the order they are offered
+var FieldTypes = []string{
+	FieldTypeText,
+	FieldTypeNumber,
+	FieldTypeDate,
+	FieldTypeSingleSelect,
+	FieldTypeMultiSelect,
+	FieldTypeCheckbox,
+}
+
+type CustomField struct {
+	ID        int64
+	BoardID   int64
+	Name      string
+	Type      string
+	Options   []string // choices for select fields
+	Position  int
+	CreatedAt time.Time
+}
+
+// HasOptions reports whether the field picks from a fixed list of choices
+func (f *CustomField) HasOptions() bool {
+	return f.Type == FieldTypeSingleSelect || f.Type == FieldTypeMultiSelect
+}
+
+// FieldValueList splits a stored value into its parts. Multi-select values
+// hold several choices; every other type holds at most one.
+func FieldValueList(fieldType, value string) []string {
+	if value == "" {
+		return nil
+	}
+	if fieldType != FieldTypeMultiSelect {
+		return []string{value}
+	}
+	var values []string
+	if err := json.Unmarshal([]byte(value), &values); err != nil {
+		return nil
+	}
+	return values
+}
@@ -1,9 +1,19 @@
 package models
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 const DefaultPersonColor = "#00ADD8"
 
+// DefaultResolution labels done columns that were not given a resolution
+const DefaultResolution = "Done"
+
+// LaneGroupingAssignee shows one row per assignee instead of the board's
+// stored swimlanes
+const LaneGroupingAssignee = "assignee"
+
 type PgConnection struct {
 	ID        int64
 	Name      string
@@ -18,11 +28,31 @@ type PgConnection struct {
 type Board struct {
 	ID             int64
 	Name           string
-	DbType         string // "local" or "postgres"
+	DbType         string // "local", "file", "postgres" or "memory" (demo mode)
 	PgConnectionID *int64
 	PgDatabaseName string
+	PgSchemaName   string // schema in a shared PgDatabaseName, or "" for a database of its own
+	LaneGrouping   string // "" for stored swimlanes or LaneGroupingAssignee
 	CreatedAt      time.Time
 	Columns        []Column
+	Swimlanes      []Swimlane
+	Fields         []CustomField
+	Templates      []CardTemplate
+	Health         *BoardHealth
+}
+
+// HasLanes reports whether the board renders as rows of cells rather than
+// plain columns
+func (b *Board) HasLanes() bool {
+	return b.LaneGrouping == LaneGroupingAssignee || len(b.Swimlanes) > 0
+}
+
+// BoardHealth is the last known reachability of a board's storage. It is only
+// tracked for boards that live outside the local SQLite database.
+type BoardHealth struct {
+	Available bool
+	LastError string
+	CheckedAt time.Time
 }
 
 type Column struct {
@@ -31,22 +61,77 @@ type Column struct {
 	Name         string
 	Position     int
 	IsDoneColumn bool
+	Resolution   string // label given to cards completed here, e.g. "Done" or "Won't do"
 	CreatedAt    time.Time
 	Cards        []Card
 }
 
+type Swimlane struct {
+	ID        int64
+	BoardID   int64
+	Name      string
+	Position  int
+	Collapsed bool
+	CreatedAt time.Time
+}
+
 type Card struct {
 	ID          int64
 	ColumnID    int64
+	SwimlaneID  *int64
 	Title       string
 	Description string
 	Position    int
 	CompletedAt *time.Time
+	Resolution  string
+	ArchivedAt  *time.Time // archived cards are hidden from their column
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	Assignees   []Person
 	Comments    []Comment
 	Checklist   []ChecklistItem
+	Links       []CardLink
+	FieldValues map[int64]string // custom field ID to stored value
+}
+
+// SearchText is what the card filter's text query searches: the title, the
+// description and the full names of the assignees. The board filters cards
+// on it in the browser and exports filter them on it on the server, so both
+// keep the same cards.
+func (c *Card) SearchText() string {
+	text := []string{c.Title, c.Description}
+	for _, assignee := range c.Assignees {
+		text = append(text, assignee.Name)
+	}
+	return strings.Join(text, "\n")
+}
+
+// CardTemplate is a board-level blueprint for new cards. Title, description
+// and checklist items may contain placeholders such as {{date}}.
+type CardTemplate struct {
+	ID          int64
+	BoardID     int64
+	Name        string
+	Title       string
+	Description string
+	Checklist   []string
+	AssigneeIDs []int64
+	FieldValues map[int64]string
+	CreatedAt   time.Time
+}
+
+// Recurrence creates a card from a template in a column on a schedule.
+// NextRunAt is nil once the schedule has no further occurrences.
+type Recurrence struct {
+	ID         int64
+	BoardID    int64
+	TemplateID int64
+	ColumnID   int64
+	Schedule   string
+	Enabled    bool
+	NextRunAt  *time.Time
+	LastRunAt  *time.Time
+	CreatedAt  time.Time
 }
 
 type Person struct {
@@ -67,8 +152,27 @@ type Comment struct {
 type ChecklistItem struct {
 	ID          int64
 	CardID      int64
+	Checklist   string // name of the checklist the item belongs to, "" for the default one
 	Content     string
 	IsCompleted bool
 	Position    int
 	CreatedAt   time.Time
 }
+
+// CardLink is a titled URL on a card, such as an imported attachment
+type CardLink struct {
+	ID        int64
+	CardID    int64
+	Title     string
+	URL       string
+	Position  int
+	CreatedAt time.Time
+}
+
+// IntegrityIssue is a problem an integrity check found in a database, such
+// as a gap in card positions or an assignment of a deleted person
+type IntegrityIssue struct {
+	Check  string // "position", "reference", "board" or "completion"
+	Table  string
+	Detail string
+}
@@ -1,24 +1,23 @@
 package repository
 
 import (
-	"database/sql"
 	"krizzy/internal/models"
 )
 
 type SQLiteBoardRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewSQLiteBoardRepository(db *sql.DB) *SQLiteBoardRepository {
+func NewSQLiteBoardRepository(db DBTX) *SQLiteBoardRepository {
 	return &SQLiteBoardRepository{db: db}
 }
 
 func (r *SQLiteBoardRepository) GetByID(id int64) (*models.Board, error) {
 	board := &models.Board{}
 	err := r.db.QueryRow(
-		"SELECT id, name, db_type, pg_connection_id, pg_database_name, created_at FROM boards WHERE id = ?",
+		"SELECT id, name, db_type, pg_connection_id, pg_database_name, pg_schema_name, lane_grouping, created_at FROM boards WHERE id = ?",
 		id,
-	).Scan(&board.ID, &board.Name, &board.DbType, &board.PgConnectionID, &board.PgDatabaseName, &board.CreatedAt)
+	).Scan(&board.ID, &board.Name, &board.DbType, &board.PgConnectionID, &board.PgDatabaseName, &board.PgSchemaName, &board.LaneGrouping, &board.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -26,7 +25,7 @@ func (r *SQLiteBoardRepository) GetByID(id int64) (*models.Board, error) {
 }
 
 func (r *SQLiteBoardRepository) GetAll() ([]models.Board, error) {
-	rows, err := r.db.Query("SELECT id, name, db_type, pg_connection_id, pg_database_name, created_at FROM boards ORDER BY id")
+	rows, err := r.db.Query("SELECT id, name, db_type, pg_connection_id, pg_database_name, pg_schema_name, lane_grouping, created_at FROM boards ORDER BY id")
 	if err != nil {
 		return nil, err
 	}
@@ -35,7 +34,7 @@ func (r *SQLiteBoardRepository) GetAll() ([]models.Board, error) {
 	var boards []models.Board
 	for rows.Next() {
 		var board models.Board
-		if err := rows.Scan(&board.ID, &board.Name, &board.DbType, &board.PgConnectionID, &board.PgDatabaseName, &board.CreatedAt); err != nil {
+		if err := rows.Scan(&board.ID, &board.Name, &board.DbType, &board.PgConnectionID, &board.PgDatabaseName, &board.PgSchemaName, &board.LaneGrouping, &board.CreatedAt); err != nil {
 			return nil, err
 		}
 		boards = append(boards, board)
@@ -48,8 +47,8 @@ func (r *SQLiteBoardRepository) Create(board *models.Board) error {
 		board.DbType = "local"
 	}
 	result, err := r.db.Exec(
-		"INSERT INTO boards (name, db_type, pg_connection_id, pg_database_name) VALUES (?, ?, ?, ?)",
-		board.Name, board.DbType, board.PgConnectionID, board.PgDatabaseName,
+		"INSERT INTO boards (name, db_type, pg_connection_id, pg_database_name, pg_schema_name) VALUES (?, ?, ?, ?, ?)",
+		board.Name, board.DbType, board.PgConnectionID, board.PgDatabaseName, board.PgSchemaName,
 	)
 	if err != nil {
 		return err
@@ -64,8 +63,8 @@ func (r *SQLiteBoardRepository) Create(board *models.Board) error {
 
 func (r *SQLiteBoardRepository) Update(board *models.Board) error {
 	_, err := r.db.Exec(
-		"UPDATE boards SET name = ? WHERE id = ?",
-		board.Name, board.ID,
+		"UPDATE boards SET name = ?, lane_grouping = ? WHERE id = ?",
+		board.Name, board.LaneGrouping, board.ID,
 	)
 	return err
 }
@@ -78,8 +77,8 @@ func (r *SQLiteBoardRepository) Delete(id int64) error {
 func (r *SQLiteBoardRepository) GetDefault() (*models.Board, error) {
 	board := &models.Board{}
 	err := r.db.QueryRow(
-		"SELECT id, name, db_type, pg_connection_id, pg_database_name, created_at FROM boards ORDER BY id LIMIT 1",
-	).Scan(&board.ID, &board.Name, &board.DbType, &board.PgConnectionID, &board.PgDatabaseName, &board.CreatedAt)
+		"SELECT id, name, db_type, pg_connection_id, pg_database_name, pg_schema_name, lane_grouping, created_at FROM boards ORDER BY id LIMIT 1",
+	).Scan(&board.ID, &board.Name, &board.DbType, &board.PgConnectionID, &board.PgDatabaseName, &board.PgSchemaName, &board.LaneGrouping, &board.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
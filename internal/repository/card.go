@@ -7,38 +7,62 @@ import (
 )
 
 type SQLiteCardRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewSQLiteCardRepository(db *sql.DB) *SQLiteCardRepository {
+func NewSQLiteCardRepository(db DBTX) *SQLiteCardRepository {
 	return &SQLiteCardRepository{db: db}
 }
 
-func (r *SQLiteCardRepository) GetByID(id int64) (*models.Card, error) {
+func scanCard(row rowScanner) (*models.Card, error) {
 	card := &models.Card{}
-	var completedAt sql.NullTime
+	var completedAt, archivedAt sql.NullTime
 	var description sql.NullString
-	err := r.db.QueryRow(
-		"SELECT id, column_id, title, description, position, completed_at, created_at, updated_at FROM cards WHERE id = ?",
-		id,
-	).Scan(&card.ID, &card.ColumnID, &card.Title, &description, &card.Position, &completedAt, &card.CreatedAt, &card.UpdatedAt)
+	err := row.Scan(&card.ID, &card.ColumnID, &card.SwimlaneID, &card.Title, &description, &card.Position, &completedAt, &card.Resolution, &archivedAt, &card.CreatedAt, &card.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
 	if completedAt.Valid {
 		card.CompletedAt = &completedAt.Time
 	}
+	if archivedAt.Valid {
+		card.ArchivedAt = &archivedAt.Time
+	}
 	if description.Valid {
 		card.Description = description.String
 	}
 	return card, nil
 }
 
+func (r *SQLiteCardRepository) GetByID(id int64) (*models.Card, error) {
+	return scanCard(r.db.QueryRow(
+		"SELECT id, column_id, swimlane_id, title, description, position, completed_at, resolution, archived_at, created_at, updated_at FROM cards WHERE id = ?",
+		id,
+	))
+}
+
+// GetByColumnID returns a column's cards in order, leaving out archived cards
 func (r *SQLiteCardRepository) GetByColumnID(columnID int64) ([]models.Card, error) {
-	rows, err := r.db.Query(
-		"SELECT id, column_id, title, description, position, completed_at, created_at, updated_at FROM cards WHERE column_id = ? ORDER BY position",
+	return r.queryCards(
+		"SELECT id, column_id, swimlane_id, title, description, position, completed_at, resolution, archived_at, created_at, updated_at FROM cards WHERE column_id = ? AND archived_at IS NULL ORDER BY position",
 		columnID,
 	)
+}
+
+// GetArchivedByBoardID returns a board's archived cards, most recently
+// archived first
+func (r *SQLiteCardRepository) GetArchivedByBoardID(boardID int64) ([]models.Card, error) {
+	return r.queryCards(
+		`SELECT cards.id, cards.column_id, cards.swimlane_id, cards.title, cards.description, cards.position, cards.completed_at, cards.resolution, cards.archived_at, cards.created_at, cards.updated_at
+		FROM cards JOIN columns ON columns.id = cards.column_id
+		WHERE columns.board_id = ? AND cards.archived_at IS NOT NULL
+		ORDER BY cards.archived_at DESC, cards.id DESC`,
+		boardID,
+	)
+}
+
+func (r *SQLiteCardRepository) queryCards(query string, args ...any) ([]models.Card, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -46,33 +70,25 @@ func (r *SQLiteCardRepository) GetByColumnID(columnID int64) ([]models.Card, err
 
 	var cards []models.Card
 	for rows.Next() {
-		var card models.Card
-		var completedAt sql.NullTime
-		var description sql.NullString
-		if err := rows.Scan(&card.ID, &card.ColumnID, &card.Title, &description, &card.Position, &completedAt, &card.CreatedAt, &card.UpdatedAt); err != nil {
+		card, err := scanCard(rows)
+		if err != nil {
 			return nil, err
 		}
-		if completedAt.Valid {
-			card.CompletedAt = &completedAt.Time
-		}
-		if description.Valid {
-			card.Description = description.String
-		}
-		cards = append(cards, card)
+		cards = append(cards, *card)
 	}
 	return cards, rows.Err()
 }
 
 func (r *SQLiteCardRepository) Create(card *models.Card) error {
-	maxPos, err := r.GetMaxPosition(card.ColumnID)
+	maxPos, err := r.GetMaxPosition(card.ColumnID, card.SwimlaneID)
 	if err != nil {
 		return err
 	}
 	card.Position = maxPos + 1
 
 	result, err := r.db.Exec(
-		"INSERT INTO cards (column_id, title, description, position) VALUES (?, ?, ?, ?)",
-		card.ColumnID, card.Title, card.Description, card.Position,
+		"INSERT INTO cards (column_id, swimlane_id, title, description, position) VALUES (?, ?, ?, ?, ?)",
+		card.ColumnID, card.SwimlaneID, card.Title, card.Description, card.Position,
 	)
 	if err != nil {
 		return err
@@ -87,8 +103,8 @@ func (r *SQLiteCardRepository) Create(card *models.Card) error {
 
 func (r *SQLiteCardRepository) Update(card *models.Card) error {
 	_, err := r.db.Exec(
-		"UPDATE cards SET title = ?, description = ?, completed_at = ?, updated_at = ? WHERE id = ?",
-		card.Title, card.Description, card.CompletedAt, time.Now(), card.ID,
+		"UPDATE cards SET title = ?, description = ?, completed_at = ?, resolution = ?, updated_at = ? WHERE id = ?",
+		card.Title, card.Description, card.CompletedAt, card.Resolution, time.Now(), card.ID,
 	)
 	return err
 }
@@ -98,8 +114,8 @@ func (r *SQLiteCardRepository) Delete(id int64) error {
 	return err
 }
 
-func (r *SQLiteCardRepository) Move(cardID int64, newColumnID int64, newPosition int) error {
-	tx, err := r.db.Begin()
+func (r *SQLiteCardRepository) Move(cardID int64, newColumnID int64, newSwimlaneID *int64, newPosition int) error {
+	tx, err := beginTx(r.db)
 	if err != nil {
 		return err
 	}
@@ -107,48 +123,50 @@ func (r *SQLiteCardRepository) Move(cardID int64, newColumnID int64, newPosition
 
 	// Get current card info
 	var oldColumnID int64
+	var oldSwimlaneID *int64
 	var oldPosition int
 	err = tx.QueryRow(
-		"SELECT column_id, position FROM cards WHERE id = ?",
+		"SELECT column_id, swimlane_id, position FROM cards WHERE id = ?",
 		cardID,
-	).Scan(&oldColumnID, &oldPosition)
+	).Scan(&oldColumnID, &oldSwimlaneID, &oldPosition)
 	if err != nil {
 		return err
 	}
 
-	// If moving within same column
-	if oldColumnID == newColumnID {
+	// Positions are kept per (column, swimlane) cell. "swimlane_id IS ?"
+	// matches the unlaned row when the lane is NULL.
+	if oldColumnID == newColumnID && sameSwimlane(oldSwimlaneID, newSwimlaneID) {
 		if oldPosition < newPosition {
 			// Moving down
 			_, err = tx.Exec(
-				"UPDATE cards SET position = position - 1 WHERE column_id = ? AND position > ? AND position <= ?",
-				oldColumnID, oldPosition, newPosition,
+				"UPDATE cards SET position = position - 1 WHERE column_id = ? AND swimlane_id IS ? AND position > ? AND position <= ?",
+				oldColumnID, oldSwimlaneID, oldPosition, newPosition,
 			)
 		} else if oldPosition > newPosition {
 			// Moving up
 			_, err = tx.Exec(
-				"UPDATE cards SET position = position + 1 WHERE column_id = ? AND position >= ? AND position < ?",
-				oldColumnID, newPosition, oldPosition,
+				"UPDATE cards SET position = position + 1 WHERE column_id = ? AND swimlane_id IS ? AND position >= ? AND position < ?",
+				oldColumnID, oldSwimlaneID, newPosition, oldPosition,
 			)
 		}
 		if err != nil {
 			return err
 		}
 	} else {
-		// Moving to different column
-		// Close gap in old column
+		// Moving to a different cell
+		// Close gap in old cell
 		_, err = tx.Exec(
-			"UPDATE cards SET position = position - 1 WHERE column_id = ? AND position > ?",
-			oldColumnID, oldPosition,
+			"UPDATE cards SET position = position - 1 WHERE column_id = ? AND swimlane_id IS ? AND position > ?",
+			oldColumnID, oldSwimlaneID, oldPosition,
 		)
 		if err != nil {
 			return err
 		}
 
-		// Make space in new column
+		// Make space in new cell
 		_, err = tx.Exec(
-			"UPDATE cards SET position = position + 1 WHERE column_id = ? AND position >= ?",
-			newColumnID, newPosition,
+			"UPDATE cards SET position = position + 1 WHERE column_id = ? AND swimlane_id IS ? AND position >= ?",
+			newColumnID, newSwimlaneID, newPosition,
 		)
 		if err != nil {
 			return err
@@ -157,8 +175,8 @@ func (r *SQLiteCardRepository) Move(cardID int64, newColumnID int64, newPosition
 
 	// Update the card
 	_, err = tx.Exec(
-		"UPDATE cards SET column_id = ?, position = ?, updated_at = ? WHERE id = ?",
-		newColumnID, newPosition, time.Now(), cardID,
+		"UPDATE cards SET column_id = ?, swimlane_id = ?, position = ?, updated_at = ? WHERE id = ?",
+		newColumnID, newSwimlaneID, newPosition, time.Now(), cardID,
 	)
 	if err != nil {
 		return err
@@ -167,11 +185,92 @@ func (r *SQLiteCardRepository) Move(cardID int64, newColumnID int64, newPosition
 	return tx.Commit()
 }
 
-func (r *SQLiteCardRepository) GetMaxPosition(columnID int64) (int, error) {
+// Archive hides a card from its column and closes the gap it leaves.
+// Archived cards sit at position -1, outside their cell's ordering.
+func (r *SQLiteCardRepository) Archive(cardID int64, archivedAt time.Time) error {
+	tx, err := beginTx(r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var columnID int64
+	var swimlaneID *int64
+	var position int
+	err = tx.QueryRow(
+		"SELECT column_id, swimlane_id, position FROM cards WHERE id = ? AND archived_at IS NULL",
+		cardID,
+	).Scan(&columnID, &swimlaneID, &position)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		"UPDATE cards SET position = position - 1 WHERE column_id = ? AND swimlane_id IS ? AND position > ? AND archived_at IS NULL",
+		columnID, swimlaneID, position,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		"UPDATE cards SET archived_at = ?, position = -1, updated_at = ? WHERE id = ?",
+		archivedAt, time.Now(), cardID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Restore brings an archived card back at the end of its cell
+func (r *SQLiteCardRepository) Restore(cardID int64) error {
+	tx, err := beginTx(r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var columnID int64
+	var swimlaneID *int64
+	err = tx.QueryRow(
+		"SELECT column_id, swimlane_id FROM cards WHERE id = ? AND archived_at IS NOT NULL",
+		cardID,
+	).Scan(&columnID, &swimlaneID)
+	if err != nil {
+		return err
+	}
+
+	var maxPos sql.NullInt64
+	err = tx.QueryRow(
+		"SELECT MAX(position) FROM cards WHERE column_id = ? AND swimlane_id IS ? AND archived_at IS NULL",
+		columnID, swimlaneID,
+	).Scan(&maxPos)
+	if err != nil {
+		return err
+	}
+	position := 0
+	if maxPos.Valid {
+		position = int(maxPos.Int64) + 1
+	}
+
+	_, err = tx.Exec(
+		"UPDATE cards SET archived_at = NULL, position = ?, updated_at = ? WHERE id = ?",
+		position, time.Now(), cardID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *SQLiteCardRepository) GetMaxPosition(columnID int64, swimlaneID *int64) (int, error) {
 	var maxPos sql.NullInt64
 	err := r.db.QueryRow(
-		"SELECT MAX(position) FROM cards WHERE column_id = ?",
-		columnID,
+		"SELECT MAX(position) FROM cards WHERE column_id = ? AND swimlane_id IS ?",
+		columnID, swimlaneID,
 	).Scan(&maxPos)
 	if err != nil {
 		return -1, err
@@ -181,3 +280,10 @@ func (r *SQLiteCardRepository) GetMaxPosition(columnID int64) (int, error) {
 	}
 	return -1, nil
 }
+
+func sameSwimlane(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"database/sql"
+	"krizzy/internal/models"
+)
+
+type SQLiteCardLinkRepository struct {
+	db DBTX
+}
+
+func NewSQLiteCardLinkRepository(db DBTX) *SQLiteCardLinkRepository {
+	return &SQLiteCardLinkRepository{db: db}
+}
+
+func (r *SQLiteCardLinkRepository) GetByID(id int64) (*models.CardLink, error) {
+	link := &models.CardLink{}
+	err := r.db.QueryRow(
+		"SELECT id, card_id, title, url, position, created_at FROM card_links WHERE id = ?",
+		id,
+	).Scan(&link.ID, &link.CardID, &link.Title, &link.URL, &link.Position, &link.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+func (r *SQLiteCardLinkRepository) GetByCardID(cardID int64) ([]models.CardLink, error) {
+	rows, err := r.db.Query(
+		"SELECT id, card_id, title, url, position, created_at FROM card_links WHERE card_id = ? ORDER BY position",
+		cardID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []models.CardLink
+	for rows.Next() {
+		var link models.CardLink
+		if err := rows.Scan(&link.ID, &link.CardID, &link.Title, &link.URL, &link.Position, &link.CreatedAt); err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+	return links, rows.Err()
+}
+
+func (r *SQLiteCardLinkRepository) Create(link *models.CardLink) error {
+	var maxPos sql.NullInt64
+	if err := r.db.QueryRow("SELECT MAX(position) FROM card_links WHERE card_id = ?", link.CardID).Scan(&maxPos); err != nil {
+		return err
+	}
+	link.Position = 0
+	if maxPos.Valid {
+		link.Position = int(maxPos.Int64) + 1
+	}
+
+	result, err := r.db.Exec(
+		"INSERT INTO card_links (card_id, title, url, position) VALUES (?, ?, ?, ?)",
+		link.CardID, link.Title, link.URL, link.Position,
+	)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	link.ID = id
+	return nil
+}
+
+func (r *SQLiteCardLinkRepository) Delete(id int64) error {
+	_, err := r.db.Exec("DELETE FROM card_links WHERE id = ?", id)
+	return err
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"encoding/json"
+	"krizzy/internal/models"
+)
+
+type SQLiteCardTemplateRepository struct {
+	db DBTX
+}
+
+func NewSQLiteCardTemplateRepository(db DBTX) *SQLiteCardTemplateRepository {
+	return &SQLiteCardTemplateRepository{db: db}
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanCardTemplate(row rowScanner) (*models.CardTemplate, error) {
+	tpl := &models.CardTemplate{}
+	var checklist, assigneeIDs, fieldValues string
+	err := row.Scan(&tpl.ID, &tpl.BoardID, &tpl.Name, &tpl.Title, &tpl.Description, &checklist, &assigneeIDs, &fieldValues, &tpl.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(checklist), &tpl.Checklist); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(assigneeIDs), &tpl.AssigneeIDs); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(fieldValues), &tpl.FieldValues); err != nil {
+		return nil, err
+	}
+	return tpl, nil
+}
+
+// encodeCardTemplate returns the JSON columns of a template. Empty values are
+// stored as empty JSON rather than null so they always decode.
+func encodeCardTemplate(tpl *models.CardTemplate) (checklist, assigneeIDs, fieldValues string, err error) {
+	items := tpl.Checklist
+	if items == nil {
+		items = []string{}
+	}
+	ids := tpl.AssigneeIDs
+	if ids == nil {
+		ids = []int64{}
+	}
+	values := tpl.FieldValues
+	if values == nil {
+		values = map[int64]string{}
+	}
+
+	var data []byte
+	if data, err = json.Marshal(items); err != nil {
+		return
+	}
+	checklist = string(data)
+	if data, err = json.Marshal(ids); err != nil {
+		return
+	}
+	assigneeIDs = string(data)
+	if data, err = json.Marshal(values); err != nil {
+		return
+	}
+	fieldValues = string(data)
+	return
+}
+
+func (r *SQLiteCardTemplateRepository) GetByID(id int64) (*models.CardTemplate, error) {
+	return scanCardTemplate(r.db.QueryRow(
+		"SELECT id, board_id, name, title, description, checklist, assignee_ids, field_values, created_at FROM card_templates WHERE id = ?",
+		id,
+	))
+}
+
+func (r *SQLiteCardTemplateRepository) GetByBoardID(boardID int64) ([]models.CardTemplate, error) {
+	rows, err := r.db.Query(
+		"SELECT id, board_id, name, title, description, checklist, assignee_ids, field_values, created_at FROM card_templates WHERE board_id = ? ORDER BY name",
+		boardID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []models.CardTemplate
+	for rows.Next() {
+		tpl, err := scanCardTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *tpl)
+	}
+	return templates, rows.Err()
+}
+
+func (r *SQLiteCardTemplateRepository) Create(tpl *models.CardTemplate) error {
+	checklist, assigneeIDs, fieldValues, err := encodeCardTemplate(tpl)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.Exec(
+		"INSERT INTO card_templates (board_id, name, title, description, checklist, assignee_ids, field_values) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		tpl.BoardID, tpl.Name, tpl.Title, tpl.Description, checklist, assigneeIDs, fieldValues,
+	)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	tpl.ID = id
+	return nil
+}
+
+func (r *SQLiteCardTemplateRepository) Delete(id int64) error {
+	_, err := r.db.Exec("DELETE FROM card_templates WHERE id = ?", id)
+	return err
+}
@@ -6,19 +6,19 @@ import (
 )
 
 type SQLiteChecklistRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewSQLiteChecklistRepository(db *sql.DB) *SQLiteChecklistRepository {
+func NewSQLiteChecklistRepository(db DBTX) *SQLiteChecklistRepository {
 	return &SQLiteChecklistRepository{db: db}
 }
 
 func (r *SQLiteChecklistRepository) GetByID(id int64) (*models.ChecklistItem, error) {
 	item := &models.ChecklistItem{}
 	err := r.db.QueryRow(
-		"SELECT id, card_id, content, is_completed, position, created_at FROM checklist_items WHERE id = ?",
+		"SELECT id, card_id, checklist, content, is_completed, position, created_at FROM checklist_items WHERE id = ?",
 		id,
-	).Scan(&item.ID, &item.CardID, &item.Content, &item.IsCompleted, &item.Position, &item.CreatedAt)
+	).Scan(&item.ID, &item.CardID, &item.Checklist, &item.Content, &item.IsCompleted, &item.Position, &item.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -27,7 +27,7 @@ func (r *SQLiteChecklistRepository) GetByID(id int64) (*models.ChecklistItem, er
 
 func (r *SQLiteChecklistRepository) GetByCardID(cardID int64) ([]models.ChecklistItem, error) {
 	rows, err := r.db.Query(
-		"SELECT id, card_id, content, is_completed, position, created_at FROM checklist_items WHERE card_id = ? ORDER BY position",
+		"SELECT id, card_id, checklist, content, is_completed, position, created_at FROM checklist_items WHERE card_id = ? ORDER BY position",
 		cardID,
 	)
 	if err != nil {
@@ -38,7 +38,7 @@ func (r *SQLiteChecklistRepository) GetByCardID(cardID int64) ([]models.Checklis
 	var items []models.ChecklistItem
 	for rows.Next() {
 		var item models.ChecklistItem
-		if err := rows.Scan(&item.ID, &item.CardID, &item.Content, &item.IsCompleted, &item.Position, &item.CreatedAt); err != nil {
+		if err := rows.Scan(&item.ID, &item.CardID, &item.Checklist, &item.Content, &item.IsCompleted, &item.Position, &item.CreatedAt); err != nil {
 			return nil, err
 		}
 		items = append(items, item)
@@ -54,8 +54,8 @@ func (r *SQLiteChecklistRepository) Create(item *models.ChecklistItem) error {
 	item.Position = maxPos + 1
 
 	result, err := r.db.Exec(
-		"INSERT INTO checklist_items (card_id, content, is_completed, position) VALUES (?, ?, ?, ?)",
-		item.CardID, item.Content, item.IsCompleted, item.Position,
+		"INSERT INTO checklist_items (card_id, checklist, content, is_completed, position) VALUES (?, ?, ?, ?, ?)",
+		item.CardID, item.Checklist, item.Content, item.IsCompleted, item.Position,
 	)
 	if err != nil {
 		return err
@@ -82,7 +82,7 @@ func (r *SQLiteChecklistRepository) Delete(id int64) error {
 }
 
 func (r *SQLiteChecklistRepository) Reorder(cardID int64, itemIDs []int64) error {
-	tx, err := r.db.Begin()
+	tx, err := beginTx(r.db)
 	if err != nil {
 		return err
 	}
@@ -6,19 +6,19 @@ import (
 )
 
 type SQLiteColumnRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewSQLiteColumnRepository(db *sql.DB) *SQLiteColumnRepository {
+func NewSQLiteColumnRepository(db DBTX) *SQLiteColumnRepository {
 	return &SQLiteColumnRepository{db: db}
 }
 
 func (r *SQLiteColumnRepository) GetByID(id int64) (*models.Column, error) {
 	column := &models.Column{}
 	err := r.db.QueryRow(
-		"SELECT id, board_id, name, position, is_done_column, created_at FROM columns WHERE id = ?",
+		"SELECT id, board_id, name, position, is_done_column, resolution, created_at FROM columns WHERE id = ?",
 		id,
-	).Scan(&column.ID, &column.BoardID, &column.Name, &column.Position, &column.IsDoneColumn, &column.CreatedAt)
+	).Scan(&column.ID, &column.BoardID, &column.Name, &column.Position, &column.IsDoneColumn, &column.Resolution, &column.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -27,7 +27,7 @@ func (r *SQLiteColumnRepository) GetByID(id int64) (*models.Column, error) {
 
 func (r *SQLiteColumnRepository) GetByBoardID(boardID int64) ([]models.Column, error) {
 	rows, err := r.db.Query(
-		"SELECT id, board_id, name, position, is_done_column, created_at FROM columns WHERE board_id = ? ORDER BY position",
+		"SELECT id, board_id, name, position, is_done_column, resolution, created_at FROM columns WHERE board_id = ? ORDER BY position",
 		boardID,
 	)
 	if err != nil {
@@ -38,7 +38,7 @@ func (r *SQLiteColumnRepository) GetByBoardID(boardID int64) ([]models.Column, e
 	var columns []models.Column
 	for rows.Next() {
 		var column models.Column
-		if err := rows.Scan(&column.ID, &column.BoardID, &column.Name, &column.Position, &column.IsDoneColumn, &column.CreatedAt); err != nil {
+		if err := rows.Scan(&column.ID, &column.BoardID, &column.Name, &column.Position, &column.IsDoneColumn, &column.Resolution, &column.CreatedAt); err != nil {
 			return nil, err
 		}
 		columns = append(columns, column)
@@ -64,8 +64,8 @@ func (r *SQLiteColumnRepository) Create(column *models.Column) error {
 	column.Position = newPos
 
 	result, err := r.db.Exec(
-		"INSERT INTO columns (board_id, name, position, is_done_column) VALUES (?, ?, ?, ?)",
-		column.BoardID, column.Name, column.Position, column.IsDoneColumn,
+		"INSERT INTO columns (board_id, name, position, is_done_column, resolution) VALUES (?, ?, ?, ?, ?)",
+		column.BoardID, column.Name, column.Position, column.IsDoneColumn, column.Resolution,
 	)
 	if err != nil {
 		return err
@@ -80,8 +80,8 @@ func (r *SQLiteColumnRepository) Create(column *models.Column) error {
 
 func (r *SQLiteColumnRepository) Update(column *models.Column) error {
 	_, err := r.db.Exec(
-		"UPDATE columns SET name = ?, is_done_column = ? WHERE id = ?",
-		column.Name, column.IsDoneColumn, column.ID,
+		"UPDATE columns SET name = ?, is_done_column = ?, resolution = ? WHERE id = ?",
+		column.Name, column.IsDoneColumn, column.Resolution, column.ID,
 	)
 	return err
 }
@@ -92,7 +92,7 @@ func (r *SQLiteColumnRepository) Delete(id int64) error {
 }
 
 func (r *SQLiteColumnRepository) Reorder(boardID int64, columnIDs []int64) error {
-	tx, err := r.db.Begin()
+	tx, err := beginTx(r.db)
 	if err != nil {
 		return err
 	}
@@ -6,10 +6,10 @@ import (
 )
 
 type SQLiteCommentRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewSQLiteCommentRepository(db *sql.DB) *SQLiteCommentRepository {
+func NewSQLiteCommentRepository(db DBTX) *SQLiteCommentRepository {
 	return &SQLiteCommentRepository{db: db}
 }
 
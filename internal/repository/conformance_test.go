@@ -0,0 +1,432 @@
+package repository_test
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"krizzy/internal/database"
+	"krizzy/internal/models"
+	"krizzy/internal/repository"
+)
+
+// The conformance tests run every case against the SQLite repositories and
+// the memory store, so the memory store stays a faithful stand-in for tests
+// and the demo mode.
+
+func forEachStore(t *testing.T, fn func(t *testing.T, boards repository.BoardRepository, repos repository.Repositories)) {
+	t.Run("sqlite", func(t *testing.T) {
+		db, err := database.NewSQLite(filepath.Join(t.TempDir(), "krizzy.db"), database.DefaultSQLiteOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+		if err := db.Migrate(); err != nil {
+			t.Fatal(err)
+		}
+		fn(t, repository.NewSQLiteBoardRepository(db.ReadWrite()), repository.NewSQLiteRepositories(db.ReadWrite()))
+	})
+	t.Run("memory", func(t *testing.T) {
+		store := repository.NewMemoryStore()
+		fn(t, store.Boards(), store.Repositories())
+	})
+}
+
+func mustDo(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func createBoard(t *testing.T, boards repository.BoardRepository, repos repository.Repositories, columns ...string) (*models.Board, []models.Column) {
+	t.Helper()
+	board := &models.Board{Name: "Board"}
+	mustDo(t, boards.Create(board))
+	var created []models.Column
+	for _, name := range columns {
+		column := &models.Column{BoardID: board.ID, Name: name}
+		mustDo(t, repos.ColumnRepo.Create(column))
+		created = append(created, *column)
+	}
+	return board, created
+}
+
+func createCard(t *testing.T, repos repository.Repositories, columnID int64, swimlaneID *int64, title string) *models.Card {
+	t.Helper()
+	card := &models.Card{ColumnID: columnID, SwimlaneID: swimlaneID, Title: title}
+	mustDo(t, repos.CardRepo.Create(card))
+	return card
+}
+
+// cells lists a column's cards as "lane/title@position", sorted, with "-" for
+// the unlaned row. Cards in different lanes can share a position, so their
+// order within the column is not part of the contract.
+func cells(t *testing.T, repos repository.Repositories, columnID int64) []string {
+	t.Helper()
+	cards, err := repos.CardRepo.GetByColumnID(columnID)
+	mustDo(t, err)
+	var got []string
+	for _, card := range cards {
+		lane := "-"
+		if card.SwimlaneID != nil {
+			lane = fmt.Sprint(*card.SwimlaneID)
+		}
+		got = append(got, fmt.Sprintf("%s/%s@%d", lane, card.Title, card.Position))
+	}
+	slices.Sort(got)
+	return got
+}
+
+func assertCells(t *testing.T, repos repository.Repositories, columnID int64, want ...string) {
+	t.Helper()
+	slices.Sort(want)
+	if got := cells(t, repos, columnID); !reflect.DeepEqual(got, want) {
+		t.Errorf("column %d = %v, want %v", columnID, got, want)
+	}
+}
+
+func assertNoRows(t *testing.T, what string, err error) {
+	t.Helper()
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("%s: got %v, want sql.ErrNoRows", what, err)
+	}
+}
+
+func TestConformanceCardMove(t *testing.T) {
+	forEachStore(t, func(t *testing.T, boards repository.BoardRepository, repos repository.Repositories) {
+		board, columns := createBoard(t, boards, repos, "A", "B")
+		a, b := columns[0].ID, columns[1].ID
+		lane := &models.Swimlane{BoardID: board.ID, Name: "Lane"}
+		mustDo(t, repos.SwimlaneRepo.Create(lane))
+		l := fmt.Sprint(lane.ID)
+
+		a0 := createCard(t, repos, a, nil, "a0")
+		createCard(t, repos, a, nil, "a1")
+		a2 := createCard(t, repos, a, nil, "a2")
+		b0 := createCard(t, repos, b, nil, "b0")
+		assertCells(t, repos, a, "-/a0@0", "-/a1@1", "-/a2@2")
+
+		// Down within a cell
+		mustDo(t, repos.CardRepo.Move(a0.ID, a, nil, 2))
+		assertCells(t, repos, a, "-/a1@0", "-/a2@1", "-/a0@2")
+
+		// Up within a cell
+		mustDo(t, repos.CardRepo.Move(a0.ID, a, nil, 0))
+		assertCells(t, repos, a, "-/a0@0", "-/a1@1", "-/a2@2")
+
+		// To another column, closing the gap and making room
+		mustDo(t, repos.CardRepo.Move(a2.ID, b, nil, 0))
+		assertCells(t, repos, a, "-/a0@0", "-/a1@1")
+		assertCells(t, repos, b, "-/a2@0", "-/b0@1")
+
+		// Into a lane, which is a cell of its own
+		mustDo(t, repos.CardRepo.Move(b0.ID, a, &lane.ID, 0))
+		assertCells(t, repos, a, "-/a0@0", "-/a1@1", l+"/b0@0")
+		assertCells(t, repos, b, "-/a2@0")
+
+		for _, tc := range []struct {
+			column int64
+			lane   *int64
+			want   int
+		}{
+			{a, nil, 1},
+			{a, &lane.ID, 0},
+			{b, &lane.ID, -1},
+		} {
+			got, err := repos.CardRepo.GetMaxPosition(tc.column, tc.lane)
+			mustDo(t, err)
+			if got != tc.want {
+				t.Errorf("max position in column %d lane %v = %d, want %d", tc.column, tc.lane, got, tc.want)
+			}
+		}
+
+		moved, err := repos.CardRepo.GetByID(b0.ID)
+		mustDo(t, err)
+		if moved.ColumnID != a || moved.SwimlaneID == nil || *moved.SwimlaneID != lane.ID {
+			t.Errorf("moved card is in column %d lane %v", moved.ColumnID, moved.SwimlaneID)
+		}
+
+		assertNoRows(t, "moving a missing card", repos.CardRepo.Move(9999, a, nil, 0))
+	})
+}
+
+func TestConformanceArchiveAndRestore(t *testing.T) {
+	forEachStore(t, func(t *testing.T, boards repository.BoardRepository, repos repository.Repositories) {
+		board, columns := createBoard(t, boards, repos, "A")
+		a := columns[0].ID
+		c0 := createCard(t, repos, a, nil, "c0")
+		c1 := createCard(t, repos, a, nil, "c1")
+		createCard(t, repos, a, nil, "c2")
+
+		first := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+		mustDo(t, repos.CardRepo.Archive(c1.ID, first))
+		mustDo(t, repos.CardRepo.Archive(c0.ID, first.Add(time.Hour)))
+		assertCells(t, repos, a, "-/c2@0")
+		assertNoRows(t, "archiving an archived card", repos.CardRepo.Archive(c1.ID, first))
+
+		archived, err := repos.CardRepo.GetArchivedByBoardID(board.ID)
+		mustDo(t, err)
+		var titles []string
+		for _, card := range archived {
+			titles = append(titles, card.Title)
+			if card.Position != -1 || card.ArchivedAt == nil {
+				t.Errorf("archived card %q at position %d, archived at %v", card.Title, card.Position, card.ArchivedAt)
+			}
+		}
+		if want := []string{"c0", "c1"}; !reflect.DeepEqual(titles, want) {
+			t.Errorf("archived cards = %v, want most recent first %v", titles, want)
+		}
+
+		// Restored cards go to the end of their cell
+		mustDo(t, repos.CardRepo.Restore(c1.ID))
+		assertCells(t, repos, a, "-/c2@0", "-/c1@1")
+		assertNoRows(t, "restoring a card that is not archived", repos.CardRepo.Restore(c1.ID))
+	})
+}
+
+func TestConformanceSwimlaneDelete(t *testing.T) {
+	forEachStore(t, func(t *testing.T, boards repository.BoardRepository, repos repository.Repositories) {
+		board, columns := createBoard(t, boards, repos, "A")
+		a := columns[0].ID
+		lane := &models.Swimlane{BoardID: board.ID, Name: "Lane"}
+		mustDo(t, repos.SwimlaneRepo.Create(lane))
+
+		createCard(t, repos, a, nil, "open")
+		createCard(t, repos, a, &lane.ID, "laned0")
+		createCard(t, repos, a, &lane.ID, "laned1")
+
+		// The lane's cards join the end of the unlaned row in their order
+		mustDo(t, repos.SwimlaneRepo.Delete(lane.ID))
+		assertCells(t, repos, a, "-/open@0", "-/laned0@1", "-/laned1@2")
+		_, err := repos.SwimlaneRepo.GetByID(lane.ID)
+		assertNoRows(t, "deleted swimlane", err)
+	})
+}
+
+func TestConformanceOrdering(t *testing.T) {
+	forEachStore(t, func(t *testing.T, boards repository.BoardRepository, repos repository.Repositories) {
+		board, columns := createBoard(t, boards, repos, "A", "B", "C")
+		mustDo(t, repos.ColumnRepo.Reorder(board.ID, []int64{columns[2].ID, columns[0].ID, columns[1].ID}))
+		reordered, err := repos.ColumnRepo.GetByBoardID(board.ID)
+		mustDo(t, err)
+		var names []string
+		for _, column := range reordered {
+			names = append(names, fmt.Sprintf("%s@%d", column.Name, column.Position))
+		}
+		if want := []string{"C@0", "A@1", "B@2"}; !reflect.DeepEqual(names, want) {
+			t.Errorf("columns = %v, want %v", names, want)
+		}
+
+		card := createCard(t, repos, columns[0].ID, nil, "Card")
+		var items []int64
+		for _, content := range []string{"one", "two", "three"} {
+			item := &models.ChecklistItem{CardID: card.ID, Content: content}
+			mustDo(t, repos.ChecklistRepo.Create(item))
+			items = append(items, item.ID)
+		}
+		mustDo(t, repos.ChecklistRepo.Reorder(card.ID, []int64{items[2], items[0], items[1]}))
+		checklist, err := repos.ChecklistRepo.GetByCardID(card.ID)
+		mustDo(t, err)
+		var contents []string
+		for _, item := range checklist {
+			contents = append(contents, fmt.Sprintf("%s@%d", item.Content, item.Position))
+		}
+		if want := []string{"three@0", "one@1", "two@2"}; !reflect.DeepEqual(contents, want) {
+			t.Errorf("checklist = %v, want %v", contents, want)
+		}
+
+		for _, name := range []string{"Cy", "Ann", "Bob"} {
+			mustDo(t, repos.PersonRepo.Create(&models.Person{BoardID: board.ID, Name: name}))
+		}
+		people, err := repos.PersonRepo.GetByBoardID(board.ID)
+		mustDo(t, err)
+		names = nil
+		for _, person := range people {
+			names = append(names, person.Name)
+		}
+		if want := []string{"Ann", "Bob", "Cy"}; !reflect.DeepEqual(names, want) {
+			t.Errorf("people = %v, want %v", names, want)
+		}
+
+		// Comments come newest first
+		at := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+		mustDo(t, repos.CommentRepo.Create(&models.Comment{CardID: card.ID, Content: "older", CreatedAt: at}))
+		mustDo(t, repos.CommentRepo.Create(&models.Comment{CardID: card.ID, Content: "newer", CreatedAt: at.Add(time.Hour)}))
+		comments, err := repos.CommentRepo.GetByCardID(card.ID)
+		mustDo(t, err)
+		if len(comments) != 2 || comments[0].Content != "newer" {
+			t.Errorf("comments = %+v, want newest first", comments)
+		}
+	})
+}
+
+func TestConformanceCascades(t *testing.T) {
+	forEachStore(t, func(t *testing.T, boards repository.BoardRepository, repos repository.Repositories) {
+		board, columns := createBoard(t, boards, repos, "A", "B")
+		field := &models.CustomField{BoardID: board.ID, Name: "Points", Type: models.FieldTypeNumber}
+		mustDo(t, repos.FieldRepo.Create(field))
+		ann := &models.Person{BoardID: board.ID, Name: "Ann"}
+		mustDo(t, repos.PersonRepo.Create(ann))
+
+		card := createCard(t, repos, columns[0].ID, nil, "Card")
+		item := &models.ChecklistItem{CardID: card.ID, Content: "Item"}
+		mustDo(t, repos.ChecklistRepo.Create(item))
+		comment := &models.Comment{CardID: card.ID, Content: "Comment"}
+		mustDo(t, repos.CommentRepo.Create(comment))
+		link := &models.CardLink{CardID: card.ID, Title: "Spec", URL: "https://example.com"}
+		mustDo(t, repos.LinkRepo.Create(link))
+		mustDo(t, repos.PersonRepo.SetCardAssignees(card.ID, []int64{ann.ID}))
+		mustDo(t, repos.FieldRepo.SetValue(card.ID, field.ID, "3"))
+
+		// Deleting a person unassigns them
+		other := createCard(t, repos, columns[1].ID, nil, "Other")
+		mustDo(t, repos.PersonRepo.SetCardAssignees(other.ID, []int64{ann.ID}))
+		bob := &models.Person{BoardID: board.ID, Name: "Bob"}
+		mustDo(t, repos.PersonRepo.Create(bob))
+		mustDo(t, repos.PersonRepo.SetCardAssignees(other.ID, []int64{ann.ID, bob.ID}))
+		mustDo(t, repos.PersonRepo.Delete(bob.ID))
+		assignees, err := repos.PersonRepo.GetByCardID(other.ID)
+		mustDo(t, err)
+		if len(assignees) != 1 || assignees[0].ID != ann.ID {
+			t.Errorf("assignees after deleting a person = %+v, want only Ann", assignees)
+		}
+
+		// Deleting a column deletes its cards and everything on them
+		mustDo(t, repos.ColumnRepo.Delete(columns[0].ID))
+		_, err = repos.CardRepo.GetByID(card.ID)
+		assertNoRows(t, "card of a deleted column", err)
+		_, err = repos.ChecklistRepo.GetByID(item.ID)
+		assertNoRows(t, "checklist item of a deleted card", err)
+		_, err = repos.CommentRepo.GetByID(comment.ID)
+		assertNoRows(t, "comment of a deleted card", err)
+		_, err = repos.LinkRepo.GetByID(link.ID)
+		assertNoRows(t, "link of a deleted card", err)
+		values, err := repos.FieldRepo.GetValuesByCardID(card.ID)
+		mustDo(t, err)
+		if len(values) != 0 {
+			t.Errorf("deleted card still has field values %v", values)
+		}
+		if _, err := repos.CardRepo.GetByID(other.ID); err != nil {
+			t.Errorf("card in another column: %v", err)
+		}
+
+		// Deleting a board deletes its columns, people and fields
+		mustDo(t, boards.Delete(board.ID))
+		_, err = repos.ColumnRepo.GetByID(columns[1].ID)
+		assertNoRows(t, "column of a deleted board", err)
+		_, err = repos.CardRepo.GetByID(other.ID)
+		assertNoRows(t, "card of a deleted board", err)
+		_, err = repos.PersonRepo.GetByID(ann.ID)
+		assertNoRows(t, "person of a deleted board", err)
+		_, err = repos.FieldRepo.GetByID(field.ID)
+		assertNoRows(t, "field of a deleted board", err)
+	})
+}
+
+func TestConformanceNotFound(t *testing.T) {
+	forEachStore(t, func(t *testing.T, boards repository.BoardRepository, repos repository.Repositories) {
+		const missing = 9999
+		lookups := map[string]func() error{
+			"board":       func() error { _, err := boards.GetByID(missing); return err },
+			"column":      func() error { _, err := repos.ColumnRepo.GetByID(missing); return err },
+			"card":        func() error { _, err := repos.CardRepo.GetByID(missing); return err },
+			"swimlane":    func() error { _, err := repos.SwimlaneRepo.GetByID(missing); return err },
+			"person":      func() error { _, err := repos.PersonRepo.GetByID(missing); return err },
+			"comment":     func() error { _, err := repos.CommentRepo.GetByID(missing); return err },
+			"link":        func() error { _, err := repos.LinkRepo.GetByID(missing); return err },
+			"checklist":   func() error { _, err := repos.ChecklistRepo.GetByID(missing); return err },
+			"field":       func() error { _, err := repos.FieldRepo.GetByID(missing); return err },
+			"template":    func() error { _, err := repos.TemplateRepo.GetByID(missing); return err },
+			"recurrence":  func() error { _, err := repos.RecurrenceRepo.GetByID(missing); return err },
+			"archive":     func() error { return repos.CardRepo.Archive(missing, time.Now()) },
+			"restore":     func() error { return repos.CardRepo.Restore(missing) },
+			"move a card": func() error { return repos.CardRepo.Move(missing, 1, nil, 0) },
+		}
+		for name, lookup := range lookups {
+			assertNoRows(t, name, lookup())
+		}
+	})
+}
+
+func TestConformanceUnitOfWork(t *testing.T) {
+	forEachStore(t, func(t *testing.T, boards repository.BoardRepository, repos repository.Repositories) {
+		_, columns := createBoard(t, boards, repos, "A")
+		a := columns[0].ID
+
+		// Work is only visible outside the transaction once it commits
+		var created *models.Card
+		mustDo(t, repos.UnitOfWork.Do(func(tx repository.Repositories) error {
+			created = createCard(t, tx, a, nil, "Committed")
+			if _, err := tx.CardRepo.GetByID(created.ID); err != nil {
+				t.Errorf("transaction does not see its own card: %v", err)
+			}
+			_, err := repos.CardRepo.GetByID(created.ID)
+			assertNoRows(t, "uncommitted card read outside the transaction", err)
+			return nil
+		}))
+		if _, err := repos.CardRepo.GetByID(created.ID); err != nil {
+			t.Errorf("committed card: %v", err)
+		}
+
+		// A failure rolls back everything, nested work included
+		failed := errors.New("failed")
+		var rolledBack *models.Card
+		err := repos.UnitOfWork.Do(func(tx repository.Repositories) error {
+			mustDo(t, tx.UnitOfWork.Do(func(inner repository.Repositories) error {
+				rolledBack = createCard(t, inner, a, nil, "Rolled back")
+				return nil
+			}))
+			mustDo(t, tx.CardRepo.Move(created.ID, a, nil, 1))
+			return failed
+		})
+		if !errors.Is(err, failed) {
+			t.Fatalf("unit of work returned %v, want %v", err, failed)
+		}
+		_, err = repos.CardRepo.GetByID(rolledBack.ID)
+		assertNoRows(t, "card created in a rolled back transaction", err)
+		assertCells(t, repos, a, "-/Committed@0")
+	})
+}
+
+func TestConformanceRecurrenceAdvance(t *testing.T) {
+	forEachStore(t, func(t *testing.T, boards repository.BoardRepository, repos repository.Repositories) {
+		board, columns := createBoard(t, boards, repos, "A")
+		tpl := &models.CardTemplate{BoardID: board.ID, Name: "Chore", Title: "Chore"}
+		mustDo(t, repos.TemplateRepo.Create(tpl))
+
+		due := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+		rec := &models.Recurrence{BoardID: board.ID, TemplateID: tpl.ID, ColumnID: columns[0].ID, Schedule: "@hourly", Enabled: true, NextRunAt: &due}
+		mustDo(t, repos.RecurrenceRepo.Create(rec))
+
+		// Only the first run that expects 09:00 claims it
+		next := due.Add(time.Hour)
+		for i, want := range []bool{true, false} {
+			claimed, err := repos.RecurrenceRepo.Advance(rec.ID, due, &next, due)
+			mustDo(t, err)
+			if claimed != want {
+				t.Errorf("run %d claimed = %v, want %v", i+1, claimed, want)
+			}
+		}
+
+		stored, err := repos.RecurrenceRepo.GetByID(rec.ID)
+		mustDo(t, err)
+		if stored.NextRunAt == nil || !stored.NextRunAt.Equal(next) || stored.LastRunAt == nil || !stored.LastRunAt.Equal(due) {
+			t.Errorf("recurrence runs next at %v, last ran at %v; want %s and %s", stored.NextRunAt, stored.LastRunAt, next, due)
+		}
+
+		// Disabled recurrences are never claimed
+		stored.Enabled = false
+		mustDo(t, repos.RecurrenceRepo.Update(stored))
+		claimed, err := repos.RecurrenceRepo.Advance(rec.ID, next, nil, next)
+		mustDo(t, err)
+		if claimed {
+			t.Error("disabled recurrence was claimed")
+		}
+	})
+}
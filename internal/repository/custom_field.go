@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"krizzy/internal/models"
+)
+
+type SQLiteCustomFieldRepository struct {
+	db DBTX
+}
+
+func NewSQLiteCustomFieldRepository(db DBTX) *SQLiteCustomFieldRepository {
+	return &SQLiteCustomFieldRepository{db: db}
+}
+
+func (r *SQLiteCustomFieldRepository) GetByID(id int64) (*models.CustomField, error) {
+	field := &models.CustomField{}
+	var options string
+	err := r.db.QueryRow(
+		"SELECT id, board_id, name, field_type, options, position, created_at FROM custom_fields WHERE id = ?",
+		id,
+	).Scan(&field.ID, &field.BoardID, &field.Name, &field.Type, &options, &field.Position, &field.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	field.Options = decodeFieldOptions(options)
+	return field, nil
+}
+
+func (r *SQLiteCustomFieldRepository) GetByBoardID(boardID int64) ([]models.CustomField, error) {
+	rows, err := r.db.Query(
+		"SELECT id, board_id, name, field_type, options, position, created_at FROM custom_fields WHERE board_id = ? ORDER BY position",
+		boardID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var fields []models.CustomField
+	for rows.Next() {
+		var field models.CustomField
+		var options string
+		if err := rows.Scan(&field.ID, &field.BoardID, &field.Name, &field.Type, &options, &field.Position, &field.CreatedAt); err != nil {
+			return nil, err
+		}
+		field.Options = decodeFieldOptions(options)
+		fields = append(fields, field)
+	}
+	return fields, rows.Err()
+}
+
+func (r *SQLiteCustomFieldRepository) Create(field *models.CustomField) error {
+	var maxPos sql.NullInt64
+	err := r.db.QueryRow(
+		"SELECT MAX(position) FROM custom_fields WHERE board_id = ?",
+		field.BoardID,
+	).Scan(&maxPos)
+	if err != nil {
+		return err
+	}
+
+	field.Position = 0
+	if maxPos.Valid {
+		field.Position = int(maxPos.Int64) + 1
+	}
+
+	result, err := r.db.Exec(
+		"INSERT INTO custom_fields (board_id, name, field_type, options, position) VALUES (?, ?, ?, ?, ?)",
+		field.BoardID, field.Name, field.Type, encodeFieldOptions(field.Options), field.Position,
+	)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	field.ID = id
+	return nil
+}
+
+// Update saves a field's name and options. The type is fixed once created
+// so stored values keep their meaning.
+func (r *SQLiteCustomFieldRepository) Update(field *models.CustomField) error {
+	_, err := r.db.Exec(
+		"UPDATE custom_fields SET name = ?, options = ? WHERE id = ?",
+		field.Name, encodeFieldOptions(field.Options), field.ID,
+	)
+	return err
+}
+
+func (r *SQLiteCustomFieldRepository) Delete(id int64) error {
+	_, err := r.db.Exec("DELETE FROM custom_fields WHERE id = ?", id)
+	return err
+}
+
+func (r *SQLiteCustomFieldRepository) GetValuesByCardID(cardID int64) (map[int64]string, error) {
+	rows, err := r.db.Query(
+		"SELECT field_id, value FROM card_field_values WHERE card_id = ?",
+		cardID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make(map[int64]string)
+	for rows.Next() {
+		var fieldID int64
+		var value string
+		if err := rows.Scan(&fieldID, &value); err != nil {
+			return nil, err
+		}
+		values[fieldID] = value
+	}
+	return values, rows.Err()
+}
+
+// SetValue stores a card's value for a field. An empty value clears it.
+func (r *SQLiteCustomFieldRepository) SetValue(cardID, fieldID int64, value string) error {
+	if value == "" {
+		_, err := r.db.Exec(
+			"DELETE FROM card_field_values WHERE card_id = ? AND field_id = ?",
+			cardID, fieldID,
+		)
+		return err
+	}
+	_, err := r.db.Exec(
+		"INSERT INTO card_field_values (card_id, field_id, value) VALUES (?, ?, ?) ON CONFLICT (card_id, field_id) DO UPDATE SET value = excluded.value",
+		cardID, fieldID, value,
+	)
+	return err
+}
+
+func encodeFieldOptions(options []string) string {
+	if len(options) == 0 {
+		return "[]"
+	}
+	data, err := json.Marshal(options)
+	if err != nil {
+		return "[]"
+	}
+	return string(data)
+}
+
+func decodeFieldOptions(data string) []string {
+	var options []string
+	if err := json.Unmarshal([]byte(data), &options); err != nil {
+		return nil
+	}
+	return options
+}
@@ -0,0 +1,449 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"krizzy/internal/models"
+)
+
+// SQLIntegrityRepository checks a SQLite or Postgres board database. The
+// queries are plain SQL both understand; only placeholders differ.
+type SQLIntegrityRepository struct {
+	db DBTX
+	pg bool
+}
+
+// NewSQLiteIntegrityRepository checks the local database, or a board file,
+// including the board list and people's boards
+func NewSQLiteIntegrityRepository(db DBTX) *SQLIntegrityRepository {
+	return &SQLIntegrityRepository{db: db}
+}
+
+// NewPgIntegrityRepository checks a Postgres board's database, which holds
+// a single board and no board list
+func NewPgIntegrityRepository(db DBTX) *SQLIntegrityRepository {
+	return &SQLIntegrityRepository{db: db, pg: true}
+}
+
+func (r *SQLIntegrityRepository) Check(repair bool) ([]models.IntegrityIssue, error) {
+	c := &integrityCheck{db: r.db, pg: r.pg, repair: repair}
+	var tx txn
+	if repair {
+		var err error
+		tx, err = beginTx(r.db)
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Rollback()
+		c.db = tx
+	}
+
+	// References go first, so positions are renumbered without rows the
+	// repair deletes or moves out of their lane
+	steps := []func() error{c.people, c.references, c.crossBoard, c.completion, c.positions}
+	if r.pg {
+		steps = []func() error{c.references, c.completion, c.positions}
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return nil, err
+		}
+	}
+
+	if repair {
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+	}
+	return c.issues, nil
+}
+
+type integrityCheck struct {
+	db     DBTX
+	pg     bool
+	repair bool
+	issues []models.IntegrityIssue
+}
+
+func (c *integrityCheck) report(check, table, format string, args ...any) {
+	c.issues = append(c.issues, models.IntegrityIssue{Check: check, Table: table, Detail: fmt.Sprintf(format, args...)})
+}
+
+// bind rewrites ? placeholders for Postgres
+func (c *integrityCheck) bind(query string) string {
+	if !c.pg {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// ids returns the first column of a query's rows
+func (c *integrityCheck) ids(query string) ([]int64, error) {
+	rows, err := c.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (c *integrityCheck) count(query string) (int, error) {
+	var n int
+	err := c.db.QueryRow(query).Scan(&n)
+	return n, err
+}
+
+// fix runs repair statements when repairing
+func (c *integrityCheck) fix(statements ...string) error {
+	if !c.repair {
+		return nil
+	}
+	for _, statement := range statements {
+		if _, err := c.db.Exec(statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// people finds people without a board, which 002_multi_board could leave
+// behind. They move to the board they are assigned on, or to the first
+// board, as 004_backfill_people_board_id did; without boards they go.
+func (c *integrityCheck) people() error {
+	ids, err := c.ids("SELECT id FROM people WHERE board_id IS NULL ORDER BY id")
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+	c.report("board", "people", "%d people have no board (ids %s)", len(ids), idList(ids))
+	return c.fix(
+		`UPDATE people SET board_id = COALESCE(
+			(SELECT MIN(columns.board_id) FROM card_assignees
+				JOIN cards ON cards.id = card_assignees.card_id
+				JOIN columns ON columns.id = cards.column_id
+				WHERE card_assignees.person_id = people.id),
+			(SELECT MIN(id) FROM boards))
+		WHERE board_id IS NULL`,
+		"DELETE FROM people WHERE board_id IS NULL",
+	)
+}
+
+// integrityRef is a reference from child.column to parent.id. Dangling
+// references are deleted, or cleared when setNull is set.
+type integrityRef struct {
+	child, column, parent string
+	setNull               bool
+	boardList             bool // only in databases with the board list
+}
+
+// integrityRefs are ordered parents first, so deleting a dangling row
+// cascades before its children are looked at
+var integrityRefs = []integrityRef{
+	{child: "columns", column: "board_id", parent: "boards", boardList: true},
+	{child: "swimlanes", column: "board_id", parent: "boards", boardList: true},
+	{child: "people", column: "board_id", parent: "boards", boardList: true},
+	{child: "custom_fields", column: "board_id", parent: "boards", boardList: true},
+	{child: "card_templates", column: "board_id", parent: "boards", boardList: true},
+	{child: "recurrences", column: "board_id", parent: "boards", boardList: true},
+	{child: "cards", column: "column_id", parent: "columns"},
+	{child: "cards", column: "swimlane_id", parent: "swimlanes", setNull: true},
+	{child: "card_assignees", column: "card_id", parent: "cards"},
+	{child: "card_assignees", column: "person_id", parent: "people"},
+	{child: "comments", column: "card_id", parent: "cards"},
+	{child: "checklist_items", column: "card_id", parent: "cards"},
+	{child: "card_links", column: "card_id", parent: "cards"},
+	{child: "card_field_values", column: "card_id", parent: "cards"},
+	{child: "card_field_values", column: "field_id", parent: "custom_fields"},
+	{child: "recurrences", column: "template_id", parent: "card_templates"},
+	{child: "recurrences", column: "column_id", parent: "columns"},
+}
+
+func (c *integrityCheck) references() error {
+	for _, ref := range integrityRefs {
+		if ref.boardList && c.pg {
+			continue
+		}
+		where := fmt.Sprintf("%s IS NOT NULL AND %s NOT IN (SELECT id FROM %s)", ref.column, ref.column, ref.parent)
+		n, err := c.count(fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", ref.child, where))
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			continue
+		}
+		c.report("reference", ref.child, "%d rows have a %s of a missing %s row", n, ref.column, ref.parent)
+
+		repair := fmt.Sprintf("DELETE FROM %s WHERE %s", ref.child, where)
+		if ref.setNull {
+			repair = fmt.Sprintf("UPDATE %s SET %s = NULL WHERE %s", ref.child, ref.column, where)
+		}
+		if err := c.fix(repair); err != nil {
+			return err
+		}
+	}
+
+	// A board's connection cannot be guessed, so those are only reported
+	if !c.pg {
+		ids, err := c.ids("SELECT id FROM boards WHERE pg_connection_id IS NOT NULL AND pg_connection_id NOT IN (SELECT id FROM pg_connections) ORDER BY id")
+		if err != nil {
+			return err
+		}
+		if len(ids) > 0 {
+			c.report("reference", "boards", "boards %s use a Postgres connection that no longer exists; recreate it or delete the boards", idList(ids))
+		}
+	}
+	return nil
+}
+
+// crossBoard finds rows that join two boards of the local database
+func (c *integrityCheck) crossBoard() error {
+	checks := []struct {
+		table, detail, query, repair string
+	}{
+		{
+			"cards", "cards %s sit in a swimlane of another board",
+			`SELECT cards.id FROM cards
+				JOIN columns ON columns.id = cards.column_id
+				JOIN swimlanes ON swimlanes.id = cards.swimlane_id
+				WHERE swimlanes.board_id <> columns.board_id ORDER BY cards.id`,
+			`UPDATE cards SET swimlane_id = NULL WHERE id IN (SELECT cards.id FROM cards
+				JOIN columns ON columns.id = cards.column_id
+				JOIN swimlanes ON swimlanes.id = cards.swimlane_id
+				WHERE swimlanes.board_id <> columns.board_id)`,
+		},
+		{
+			"card_assignees", "cards %s are assigned to people of another board",
+			`SELECT DISTINCT cards.id FROM card_assignees
+				JOIN cards ON cards.id = card_assignees.card_id
+				JOIN columns ON columns.id = cards.column_id
+				JOIN people ON people.id = card_assignees.person_id
+				WHERE people.board_id <> columns.board_id ORDER BY cards.id`,
+			`DELETE FROM card_assignees WHERE EXISTS (SELECT 1 FROM cards
+				JOIN columns ON columns.id = cards.column_id
+				JOIN people ON people.id = card_assignees.person_id
+				WHERE cards.id = card_assignees.card_id AND people.board_id <> columns.board_id)`,
+		},
+		{
+			"card_field_values", "cards %s have values of another board's custom fields",
+			`SELECT DISTINCT cards.id FROM card_field_values
+				JOIN cards ON cards.id = card_field_values.card_id
+				JOIN columns ON columns.id = cards.column_id
+				JOIN custom_fields ON custom_fields.id = card_field_values.field_id
+				WHERE custom_fields.board_id <> columns.board_id ORDER BY cards.id`,
+			`DELETE FROM card_field_values WHERE EXISTS (SELECT 1 FROM cards
+				JOIN columns ON columns.id = cards.column_id
+				JOIN custom_fields ON custom_fields.id = card_field_values.field_id
+				WHERE cards.id = card_field_values.card_id AND custom_fields.board_id <> columns.board_id)`,
+		},
+		{
+			"recurrences", "recurrences %s use a template or column of another board",
+			`SELECT recurrences.id FROM recurrences
+				JOIN columns ON columns.id = recurrences.column_id
+				JOIN card_templates ON card_templates.id = recurrences.template_id
+				WHERE columns.board_id <> recurrences.board_id OR card_templates.board_id <> recurrences.board_id
+				ORDER BY recurrences.id`,
+			`DELETE FROM recurrences WHERE id IN (SELECT recurrences.id FROM recurrences
+				JOIN columns ON columns.id = recurrences.column_id
+				JOIN card_templates ON card_templates.id = recurrences.template_id
+				WHERE columns.board_id <> recurrences.board_id OR card_templates.board_id <> recurrences.board_id)`,
+		},
+	}
+
+	for _, check := range checks {
+		ids, err := c.ids(check.query)
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		c.report("board", check.table, check.detail, idList(ids))
+		if err := c.fix(check.repair); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// completion finds cards whose completion disagrees with their column. A
+// card in a done column is taken to have been completed when it was last
+// changed.
+func (c *integrityCheck) completion() error {
+	const inDone = "column_id IN (SELECT id FROM columns WHERE COALESCE(is_done_column, FALSE))"
+
+	ids, err := c.ids("SELECT id FROM cards WHERE completed_at IS NULL AND " + inDone + " ORDER BY id")
+	if err != nil {
+		return err
+	}
+	if len(ids) > 0 {
+		c.report("completion", "cards", "cards %s are in a done column but not completed", idList(ids))
+		err := c.fix(`UPDATE cards SET
+			completed_at = COALESCE(updated_at, created_at),
+			resolution = (SELECT CASE WHEN columns.resolution = '' THEN 'Done' ELSE columns.resolution END
+				FROM columns WHERE columns.id = cards.column_id)
+			WHERE completed_at IS NULL AND ` + inDone)
+		if err != nil {
+			return err
+		}
+	}
+
+	ids, err = c.ids("SELECT id FROM cards WHERE completed_at IS NOT NULL AND NOT " + inDone + " ORDER BY id")
+	if err != nil {
+		return err
+	}
+	if len(ids) > 0 {
+		c.report("completion", "cards", "cards %s are completed but not in a done column", idList(ids))
+		return c.fix("UPDATE cards SET completed_at = NULL, resolution = '' WHERE completed_at IS NOT NULL AND NOT " + inDone)
+	}
+	return nil
+}
+
+// integrityOrder is a table whose rows are numbered 0, 1, 2... within
+// groups. group lists up to two grouping columns.
+type integrityOrder struct {
+	table, where, describe string
+	group                  [2]string
+}
+
+var integrityOrders = []integrityOrder{
+	{table: "columns", group: [2]string{"board_id", "0"}, describe: "board %d"},
+	{table: "swimlanes", group: [2]string{"board_id", "0"}, describe: "board %d"},
+	{table: "custom_fields", group: [2]string{"board_id", "0"}, describe: "board %d"},
+	{table: "cards", where: "archived_at IS NULL", group: [2]string{"column_id", "COALESCE(swimlane_id, 0)"}, describe: "column %d, lane %d"},
+	{table: "checklist_items", group: [2]string{"card_id", "0"}, describe: "card %d"},
+	{table: "card_links", group: [2]string{"card_id", "0"}, describe: "card %d"},
+}
+
+// positions finds position gaps and duplicates, and archived cards that are
+// not at position -1. Repairs keep the current order, breaking ties by ID.
+func (c *integrityCheck) positions() error {
+	ids, err := c.ids("SELECT id FROM cards WHERE archived_at IS NOT NULL AND position <> -1 ORDER BY id")
+	if err != nil {
+		return err
+	}
+	if len(ids) > 0 {
+		c.report("position", "cards", "archived cards %s are not at position -1", idList(ids))
+		if err := c.fix("UPDATE cards SET position = -1 WHERE archived_at IS NOT NULL AND position <> -1"); err != nil {
+			return err
+		}
+	}
+
+	for _, order := range integrityOrders {
+		if err := c.checkOrder(order); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type positionRow struct {
+	id       int64
+	position int
+}
+
+func (c *integrityCheck) checkOrder(order integrityOrder) error {
+	where := ""
+	if order.where != "" {
+		where = " WHERE " + order.where
+	}
+	rows, err := c.db.Query(fmt.Sprintf(
+		"SELECT id, %s AS group_a, %s AS group_b, position FROM %s%s ORDER BY group_a, group_b, position, id",
+		order.group[0], order.group[1], order.table, where,
+	))
+	if err != nil {
+		return err
+	}
+
+	type groupKey [2]int64
+	var keys []groupKey
+	groups := make(map[groupKey][]positionRow)
+	for rows.Next() {
+		var key groupKey
+		var row positionRow
+		if err := rows.Scan(&row.id, &key[0], &key[1], &row.position); err != nil {
+			rows.Close()
+			return err
+		}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], row)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		group := groups[key]
+		if positionsInOrder(group) {
+			continue
+		}
+
+		positions := make([]string, len(group))
+		for i, row := range group {
+			positions[i] = strconv.Itoa(row.position)
+		}
+		describe := fmt.Sprintf(order.describe, key[0])
+		if order.group[1] != "0" {
+			describe = fmt.Sprintf(order.describe, key[0], key[1])
+		}
+		c.report("position", order.table, "%s has positions %s, expected 0 to %d", describe, strings.Join(positions, ", "), len(group)-1)
+
+		if !c.repair {
+			continue
+		}
+		for i, row := range group {
+			if row.position == i {
+				continue
+			}
+			if _, err := c.db.Exec(c.bind(fmt.Sprintf("UPDATE %s SET position = ? WHERE id = ?", order.table)), i, row.id); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func positionsInOrder(rows []positionRow) bool {
+	for i, row := range rows {
+		if row.position != i {
+			return false
+		}
+	}
+	return true
+}
+
+// idList formats IDs for a report, shortening long lists
+func idList(ids []int64) string {
+	const shown = 10
+	parts := make([]string, 0, shown+1)
+	for i, id := range ids {
+		if i == shown {
+			parts = append(parts, fmt.Sprintf("and %d more", len(ids)-shown))
+			break
+		}
+		parts = append(parts, strconv.FormatInt(id, 10))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,380 @@
+package repository
+
+import (
+	"cmp"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Constraint errors the memory store returns where SQLite would refuse a write
+var (
+	errMemoryForeignKey = errors.New("memory: FOREIGN KEY constraint failed")
+	errMemoryUnique     = errors.New("memory: UNIQUE constraint failed")
+)
+
+// MemoryStore keeps the board list, Postgres connections and every board's
+// data in process memory. Its repositories follow the SQLite ones: the same
+// ordering, positions, defaults and cascades, and sql.ErrNoRows for missing
+// rows. It backs the demo mode and tests that should not need a database.
+//
+// Writes are serialized. A transaction holds the write lock until it ends, so
+// writing through the store's own repositories rather than the transaction's
+// from inside it deadlocks. It works on a copy of the data that replaces the
+// store's on commit, so reads outside the transaction, like those on another
+// SQLite connection, only see its writes once it commits.
+type MemoryStore struct {
+	txMu sync.Mutex
+	mu   sync.RWMutex
+	data *memoryData
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{data: newMemoryData()}
+}
+
+// Boards returns the store's board list
+func (s *MemoryStore) Boards() BoardRepository {
+	return &MemoryBoardRepository{h: memoryHandle{store: s}}
+}
+
+// PgConnections returns the store's Postgres connections
+func (s *MemoryStore) PgConnections() PgConnectionRepository {
+	return &MemoryPgConnectionRepository{h: memoryHandle{store: s}}
+}
+
+// Repositories returns the board repositories of the store. Every board in
+// the store shares them, as local boards share the SQLite database.
+func (s *MemoryStore) Repositories() Repositories {
+	return memoryRepositories(memoryHandle{store: s})
+}
+
+// Transaction runs fn with the board list and board repositories in one
+// transaction. It commits when fn returns nil and rolls back otherwise.
+func (s *MemoryStore) Transaction(fn func(boards BoardRepository, repos Repositories) error) error {
+	return memoryHandle{store: s}.transaction(func(tx memoryHandle) error {
+		return fn(&MemoryBoardRepository{h: tx}, memoryRepositories(tx))
+	})
+}
+
+// Reset drops all data, including ID counters, so the next rows get the IDs
+// a new store would give them
+func (s *MemoryStore) Reset() {
+	s.txMu.Lock()
+	defer s.txMu.Unlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data = newMemoryData()
+}
+
+func memoryRepositories(h memoryHandle) Repositories {
+	return Repositories{
+		ColumnRepo:     &MemoryColumnRepository{h: h},
+		CardRepo:       &MemoryCardRepository{h: h},
+		PersonRepo:     &MemoryPersonRepository{h: h},
+		CommentRepo:    &MemoryCommentRepository{h: h},
+		LinkRepo:       &MemoryCardLinkRepository{h: h},
+		ChecklistRepo:  &MemoryChecklistRepository{h: h},
+		SwimlaneRepo:   &MemorySwimlaneRepository{h: h},
+		FieldRepo:      &MemoryCustomFieldRepository{h: h},
+		TemplateRepo:   &MemoryCardTemplateRepository{h: h},
+		RecurrenceRepo: &MemoryRecurrenceRepository{h: h},
+		UnitOfWork:     memoryUnitOfWork{h: h},
+	}
+}
+
+type memoryUnitOfWork struct {
+	h memoryHandle
+}
+
+func (u memoryUnitOfWork) Do(fn func(tx Repositories) error) error {
+	return u.h.transaction(func(tx memoryHandle) error {
+		return fn(memoryRepositories(tx))
+	})
+}
+
+// memoryHandle is what the memory repositories run on: the store itself, or
+// a transaction open on it
+type memoryHandle struct {
+	store *MemoryStore
+	// tx is an open transaction's working copy of the data, nil outside one
+	tx *memoryData
+}
+
+func (h memoryHandle) read(fn func(d *memoryData)) {
+	if h.tx != nil {
+		fn(h.tx)
+		return
+	}
+	h.store.mu.RLock()
+	defer h.store.mu.RUnlock()
+	fn(h.store.data)
+}
+
+// write applies fn atomically. fn must check everything that can fail before
+// it changes anything, since a failed write outside a transaction is not
+// rolled back.
+func (h memoryHandle) write(fn func(d *memoryData) error) error {
+	if h.tx != nil {
+		return fn(h.tx)
+	}
+	h.store.txMu.Lock()
+	defer h.store.txMu.Unlock()
+	h.store.mu.Lock()
+	defer h.store.mu.Unlock()
+	return fn(h.store.data)
+}
+
+// transaction runs fn on a working copy of the data, which replaces the
+// store's data when fn succeeds. Inside another transaction the work joins
+// it.
+func (h memoryHandle) transaction(fn func(tx memoryHandle) error) error {
+	if h.tx != nil {
+		return fn(h)
+	}
+
+	s := h.store
+	s.txMu.Lock()
+	defer s.txMu.Unlock()
+
+	s.mu.RLock()
+	working := s.data.clone()
+	s.mu.RUnlock()
+
+	if err := fn(memoryHandle{store: s, tx: working}); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.data = working
+	s.mu.Unlock()
+	return nil
+}
+
+// cardPerson keys card_assignees
+type cardPerson struct {
+	cardID, personID int64
+}
+
+// cardField keys card_field_values
+type cardField struct {
+	cardID, fieldID int64
+}
+
+// memoryData holds one row per map entry. Rows are replaced rather than
+// changed in place, so a shallow copy of the maps is a snapshot.
+type memoryData struct {
+	lastIDs       map[string]int64
+	boards        map[int64]boardRow
+	pgConnections map[int64]pgConnectionRow
+	columns       map[int64]columnRow
+	swimlanes     map[int64]swimlaneRow
+	cards         map[int64]cardRow
+	people        map[int64]personRow
+	assignees     map[cardPerson]struct{}
+	comments      map[int64]commentRow
+	checklist     map[int64]checklistRow
+	links         map[int64]cardLinkRow
+	fields        map[int64]customFieldRow
+	fieldValues   map[cardField]string
+	templates     map[int64]cardTemplateRow
+	recurrences   map[int64]recurrenceRow
+}
+
+func newMemoryData() *memoryData {
+	return &memoryData{
+		lastIDs:       make(map[string]int64),
+		boards:        make(map[int64]boardRow),
+		pgConnections: make(map[int64]pgConnectionRow),
+		columns:       make(map[int64]columnRow),
+		swimlanes:     make(map[int64]swimlaneRow),
+		cards:         make(map[int64]cardRow),
+		people:        make(map[int64]personRow),
+		assignees:     make(map[cardPerson]struct{}),
+		comments:      make(map[int64]commentRow),
+		checklist:     make(map[int64]checklistRow),
+		links:         make(map[int64]cardLinkRow),
+		fields:        make(map[int64]customFieldRow),
+		fieldValues:   make(map[cardField]string),
+		templates:     make(map[int64]cardTemplateRow),
+		recurrences:   make(map[int64]recurrenceRow),
+	}
+}
+
+func (d *memoryData) clone() *memoryData {
+	return &memoryData{
+		lastIDs:       maps.Clone(d.lastIDs),
+		boards:        maps.Clone(d.boards),
+		pgConnections: maps.Clone(d.pgConnections),
+		columns:       maps.Clone(d.columns),
+		swimlanes:     maps.Clone(d.swimlanes),
+		cards:         maps.Clone(d.cards),
+		people:        maps.Clone(d.people),
+		assignees:     maps.Clone(d.assignees),
+		comments:      maps.Clone(d.comments),
+		checklist:     maps.Clone(d.checklist),
+		links:         maps.Clone(d.links),
+		fields:        maps.Clone(d.fields),
+		fieldValues:   maps.Clone(d.fieldValues),
+		templates:     maps.Clone(d.templates),
+		recurrences:   maps.Clone(d.recurrences),
+	}
+}
+
+// nextID hands out IDs per table like AUTOINCREMENT: never reused, even
+// after the newest row is deleted
+func (d *memoryData) nextID(table string) int64 {
+	d.lastIDs[table]++
+	return d.lastIDs[table]
+}
+
+// deleteBoard removes a board and everything that belongs to it
+func (d *memoryData) deleteBoard(id int64) {
+	for _, column := range d.columns {
+		if column.BoardID == id {
+			d.deleteColumn(column.ID)
+		}
+	}
+	for _, person := range d.people {
+		if person.BoardID == id {
+			d.deletePerson(person.ID)
+		}
+	}
+	for _, field := range d.fields {
+		if field.BoardID == id {
+			d.deleteField(field.ID)
+		}
+	}
+	for _, tpl := range d.templates {
+		if tpl.BoardID == id {
+			d.deleteTemplate(tpl.ID)
+		}
+	}
+	for _, lane := range d.swimlanes {
+		if lane.BoardID == id {
+			delete(d.swimlanes, lane.ID)
+		}
+	}
+	for _, rec := range d.recurrences {
+		if rec.BoardID == id {
+			delete(d.recurrences, rec.ID)
+		}
+	}
+	delete(d.boards, id)
+}
+
+func (d *memoryData) deleteColumn(id int64) {
+	for _, card := range d.cards {
+		if card.ColumnID == id {
+			d.deleteCard(card.ID)
+		}
+	}
+	for _, rec := range d.recurrences {
+		if rec.ColumnID == id {
+			delete(d.recurrences, rec.ID)
+		}
+	}
+	delete(d.columns, id)
+}
+
+func (d *memoryData) deleteCard(id int64) {
+	for key := range d.assignees {
+		if key.cardID == id {
+			delete(d.assignees, key)
+		}
+	}
+	for key := range d.fieldValues {
+		if key.cardID == id {
+			delete(d.fieldValues, key)
+		}
+	}
+	for _, comment := range d.comments {
+		if comment.CardID == id {
+			delete(d.comments, comment.ID)
+		}
+	}
+	for _, item := range d.checklist {
+		if item.CardID == id {
+			delete(d.checklist, item.ID)
+		}
+	}
+	for _, link := range d.links {
+		if link.CardID == id {
+			delete(d.links, link.ID)
+		}
+	}
+	delete(d.cards, id)
+}
+
+func (d *memoryData) deletePerson(id int64) {
+	for key := range d.assignees {
+		if key.personID == id {
+			delete(d.assignees, key)
+		}
+	}
+	delete(d.people, id)
+}
+
+func (d *memoryData) deleteField(id int64) {
+	for key := range d.fieldValues {
+		if key.fieldID == id {
+			delete(d.fieldValues, key)
+		}
+	}
+	delete(d.fields, id)
+}
+
+func (d *memoryData) deleteTemplate(id int64) {
+	for _, rec := range d.recurrences {
+		if rec.TemplateID == id {
+			delete(d.recurrences, rec.ID)
+		}
+	}
+	delete(d.templates, id)
+}
+
+// memoryNow stands in for CURRENT_TIMESTAMP
+func memoryNow() time.Time {
+	return time.Now().UTC()
+}
+
+// sortedRows returns the rows that pass keep, ordered by compare and then by
+// ID, as the SQL repositories order them
+func sortedRows[R any](rows map[int64]R, keep func(R) bool, compare func(a, b R) int, id func(R) int64) []R {
+	var out []R
+	for _, row := range rows {
+		if keep(row) {
+			out = append(out, row)
+		}
+	}
+	slices.SortFunc(out, func(a, b R) int {
+		if c := compare(a, b); c != 0 {
+			return c
+		}
+		return cmp.Compare(id(a), id(b))
+	})
+	return out
+}
+
+// maxPosition is MAX(position) over the rows that pass keep, or -1 for none
+func maxPosition[R any](rows map[int64]R, keep func(R) bool, position func(R) int) int {
+	maxPos := -1
+	found := false
+	for _, row := range rows {
+		if keep(row) && (!found || position(row) > maxPos) {
+			maxPos = position(row)
+			found = true
+		}
+	}
+	return maxPos
+}
+
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"cmp"
+	"database/sql"
+
+	"krizzy/internal/models"
+)
+
+// boardRow is a stored board, without the data loaded into it
+type boardRow models.Board
+
+func newBoardRow(board *models.Board) boardRow {
+	return boardRow{
+		ID:             board.ID,
+		Name:           board.Name,
+		DbType:         board.DbType,
+		PgConnectionID: clonePtr(board.PgConnectionID),
+		PgDatabaseName: board.PgDatabaseName,
+		PgSchemaName:   board.PgSchemaName,
+		LaneGrouping:   board.LaneGrouping,
+		CreatedAt:      board.CreatedAt,
+	}
+}
+
+func (r boardRow) model() models.Board {
+	board := models.Board(r)
+	board.PgConnectionID = clonePtr(r.PgConnectionID)
+	return board
+}
+
+type MemoryBoardRepository struct {
+	h memoryHandle
+}
+
+func (r *MemoryBoardRepository) GetByID(id int64) (*models.Board, error) {
+	var board *models.Board
+	r.h.read(func(d *memoryData) {
+		if row, ok := d.boards[id]; ok {
+			b := row.model()
+			board = &b
+		}
+	})
+	if board == nil {
+		return nil, sql.ErrNoRows
+	}
+	return board, nil
+}
+
+func (r *MemoryBoardRepository) GetAll() ([]models.Board, error) {
+	var boards []models.Board
+	r.h.read(func(d *memoryData) {
+		for _, row := range r.sorted(d) {
+			boards = append(boards, row.model())
+		}
+	})
+	return boards, nil
+}
+
+func (r *MemoryBoardRepository) sorted(d *memoryData) []boardRow {
+	return sortedRows(d.boards,
+		func(boardRow) bool { return true },
+		func(a, b boardRow) int { return cmp.Compare(a.ID, b.ID) },
+		func(row boardRow) int64 { return row.ID },
+	)
+}
+
+func (r *MemoryBoardRepository) Create(board *models.Board) error {
+	if board.DbType == "" {
+		board.DbType = "local"
+	}
+	return r.h.write(func(d *memoryData) error {
+		if board.PgConnectionID != nil {
+			if _, ok := d.pgConnections[*board.PgConnectionID]; !ok {
+				return errMemoryForeignKey
+			}
+		}
+		row := newBoardRow(board)
+		row.ID = d.nextID("boards")
+		row.LaneGrouping = ""
+		row.CreatedAt = memoryNow()
+		d.boards[row.ID] = row
+		board.ID = row.ID
+		return nil
+	})
+}
+
+func (r *MemoryBoardRepository) Update(board *models.Board) error {
+	return r.h.write(func(d *memoryData) error {
+		row, ok := d.boards[board.ID]
+		if !ok {
+			return nil
+		}
+		row.Name = board.Name
+		row.LaneGrouping = board.LaneGrouping
+		d.boards[row.ID] = row
+		return nil
+	})
+}
+
+func (r *MemoryBoardRepository) Delete(id int64) error {
+	return r.h.write(func(d *memoryData) error {
+		d.deleteBoard(id)
+		return nil
+	})
+}
+
+func (r *MemoryBoardRepository) GetDefault() (*models.Board, error) {
+	var board *models.Board
+	r.h.read(func(d *memoryData) {
+		if rows := r.sorted(d); len(rows) > 0 {
+			b := rows[0].model()
+			board = &b
+		}
+	})
+	if board == nil {
+		return nil, sql.ErrNoRows
+	}
+	return board, nil
+}
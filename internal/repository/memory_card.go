@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"cmp"
+	"database/sql"
+	"time"
+
+	"krizzy/internal/models"
+)
+
+// cardRow is a stored card, without its assignees, comments, checklist,
+// links and field values
+type cardRow models.Card
+
+func (r cardRow) model() models.Card {
+	return models.Card{
+		ID:          r.ID,
+		ColumnID:    r.ColumnID,
+		SwimlaneID:  clonePtr(r.SwimlaneID),
+		Title:       r.Title,
+		Description: r.Description,
+		Position:    r.Position,
+		CompletedAt: clonePtr(r.CompletedAt),
+		Resolution:  r.Resolution,
+		ArchivedAt:  clonePtr(r.ArchivedAt),
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
+
+// inCell reports whether the card is in a column and swimlane cell, like
+// "column_id = ? AND swimlane_id IS ?"
+func (r cardRow) inCell(columnID int64, swimlaneID *int64) bool {
+	return r.ColumnID == columnID && sameSwimlane(r.SwimlaneID, swimlaneID)
+}
+
+type MemoryCardRepository struct {
+	h memoryHandle
+}
+
+func (r *MemoryCardRepository) GetByID(id int64) (*models.Card, error) {
+	var card *models.Card
+	r.h.read(func(d *memoryData) {
+		if row, ok := d.cards[id]; ok {
+			c := row.model()
+			card = &c
+		}
+	})
+	if card == nil {
+		return nil, sql.ErrNoRows
+	}
+	return card, nil
+}
+
+// GetByColumnID returns a column's cards in order, leaving out archived cards
+func (r *MemoryCardRepository) GetByColumnID(columnID int64) ([]models.Card, error) {
+	var cards []models.Card
+	r.h.read(func(d *memoryData) {
+		rows := sortedRows(d.cards,
+			func(row cardRow) bool { return row.ColumnID == columnID && row.ArchivedAt == nil },
+			func(a, b cardRow) int { return cmp.Compare(a.Position, b.Position) },
+			func(row cardRow) int64 { return row.ID },
+		)
+		for _, row := range rows {
+			cards = append(cards, row.model())
+		}
+	})
+	return cards, nil
+}
+
+// GetArchivedByBoardID returns a board's archived cards, most recently
+// archived first
+func (r *MemoryCardRepository) GetArchivedByBoardID(boardID int64) ([]models.Card, error) {
+	var cards []models.Card
+	r.h.read(func(d *memoryData) {
+		rows := sortedRows(d.cards,
+			func(row cardRow) bool {
+				column, ok := d.columns[row.ColumnID]
+				return ok && column.BoardID == boardID && row.ArchivedAt != nil
+			},
+			func(a, b cardRow) int {
+				if c := b.ArchivedAt.Compare(*a.ArchivedAt); c != 0 {
+					return c
+				}
+				return cmp.Compare(b.ID, a.ID)
+			},
+			func(row cardRow) int64 { return row.ID },
+		)
+		for _, row := range rows {
+			cards = append(cards, row.model())
+		}
+	})
+	return cards, nil
+}
+
+func (r *MemoryCardRepository) Create(card *models.Card) error {
+	return r.h.write(func(d *memoryData) error {
+		if _, ok := d.columns[card.ColumnID]; !ok {
+			return errMemoryForeignKey
+		}
+		card.Position = d.maxCardPosition(card.ColumnID, card.SwimlaneID) + 1
+
+		now := memoryNow()
+		row := cardRow{
+			ID:          d.nextID("cards"),
+			ColumnID:    card.ColumnID,
+			SwimlaneID:  clonePtr(card.SwimlaneID),
+			Title:       card.Title,
+			Description: card.Description,
+			Position:    card.Position,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		d.cards[row.ID] = row
+		card.ID = row.ID
+		return nil
+	})
+}
+
+func (r *MemoryCardRepository) Update(card *models.Card) error {
+	return r.h.write(func(d *memoryData) error {
+		row, ok := d.cards[card.ID]
+		if !ok {
+			return nil
+		}
+		row.Title = card.Title
+		row.Description = card.Description
+		row.CompletedAt = clonePtr(card.CompletedAt)
+		row.Resolution = card.Resolution
+		row.UpdatedAt = memoryNow()
+		d.cards[row.ID] = row
+		return nil
+	})
+}
+
+func (r *MemoryCardRepository) Delete(id int64) error {
+	return r.h.write(func(d *memoryData) error {
+		d.deleteCard(id)
+		return nil
+	})
+}
+
+func (r *MemoryCardRepository) Move(cardID int64, newColumnID int64, newSwimlaneID *int64, newPosition int) error {
+	return r.h.write(func(d *memoryData) error {
+		card, ok := d.cards[cardID]
+		if !ok {
+			return sql.ErrNoRows
+		}
+		if _, ok := d.columns[newColumnID]; !ok {
+			return errMemoryForeignKey
+		}
+		oldColumnID, oldSwimlaneID, oldPosition := card.ColumnID, card.SwimlaneID, card.Position
+
+		// Positions are kept per (column, swimlane) cell
+		shift := func(columnID int64, swimlaneID *int64, delta int, match func(position int) bool) {
+			for id, row := range d.cards {
+				if id != cardID && row.inCell(columnID, swimlaneID) && match(row.Position) {
+					row.Position += delta
+					d.cards[id] = row
+				}
+			}
+		}
+		if oldColumnID == newColumnID && sameSwimlane(oldSwimlaneID, newSwimlaneID) {
+			if oldPosition < newPosition {
+				// Moving down
+				shift(oldColumnID, oldSwimlaneID, -1, func(p int) bool { return p > oldPosition && p <= newPosition })
+			} else if oldPosition > newPosition {
+				// Moving up
+				shift(oldColumnID, oldSwimlaneID, 1, func(p int) bool { return p >= newPosition && p < oldPosition })
+			}
+		} else {
+			// Close gap in old cell, then make space in new cell
+			shift(oldColumnID, oldSwimlaneID, -1, func(p int) bool { return p > oldPosition })
+			shift(newColumnID, newSwimlaneID, 1, func(p int) bool { return p >= newPosition })
+		}
+
+		card.ColumnID = newColumnID
+		card.SwimlaneID = clonePtr(newSwimlaneID)
+		card.Position = newPosition
+		card.UpdatedAt = memoryNow()
+		d.cards[cardID] = card
+		return nil
+	})
+}
+
+// Archive hides a card from its column and closes the gap it leaves.
+// Archived cards sit at position -1, outside their cell's ordering.
+func (r *MemoryCardRepository) Archive(cardID int64, archivedAt time.Time) error {
+	return r.h.write(func(d *memoryData) error {
+		card, ok := d.cards[cardID]
+		if !ok || card.ArchivedAt != nil {
+			return sql.ErrNoRows
+		}
+
+		for id, row := range d.cards {
+			if row.inCell(card.ColumnID, card.SwimlaneID) && row.Position > card.Position && row.ArchivedAt == nil {
+				row.Position--
+				d.cards[id] = row
+			}
+		}
+
+		card.ArchivedAt = &archivedAt
+		card.Position = -1
+		card.UpdatedAt = memoryNow()
+		d.cards[cardID] = card
+		return nil
+	})
+}
+
+// Restore brings an archived card back at the end of its cell
+func (r *MemoryCardRepository) Restore(cardID int64) error {
+	return r.h.write(func(d *memoryData) error {
+		card, ok := d.cards[cardID]
+		if !ok || card.ArchivedAt == nil {
+			return sql.ErrNoRows
+		}
+
+		maxPos := maxPosition(d.cards,
+			func(row cardRow) bool { return row.inCell(card.ColumnID, card.SwimlaneID) && row.ArchivedAt == nil },
+			func(row cardRow) int { return row.Position },
+		)
+		card.ArchivedAt = nil
+		card.Position = maxPos + 1
+		card.UpdatedAt = memoryNow()
+		d.cards[cardID] = card
+		return nil
+	})
+}
+
+func (r *MemoryCardRepository) GetMaxPosition(columnID int64, swimlaneID *int64) (int, error) {
+	var maxPos int
+	r.h.read(func(d *memoryData) {
+		maxPos = d.maxCardPosition(columnID, swimlaneID)
+	})
+	return maxPos, nil
+}
+
+// maxCardPosition is the highest position in a cell, archived cards
+// included, or -1 for an empty cell
+func (d *memoryData) maxCardPosition(columnID int64, swimlaneID *int64) int {
+	return maxPosition(d.cards,
+		func(row cardRow) bool { return row.inCell(columnID, swimlaneID) },
+		func(row cardRow) int { return row.Position },
+	)
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"cmp"
+	"database/sql"
+
+	"krizzy/internal/models"
+)
+
+type cardLinkRow models.CardLink
+
+type MemoryCardLinkRepository struct {
+	h memoryHandle
+}
+
+func (r *MemoryCardLinkRepository) GetByID(id int64) (*models.CardLink, error) {
+	var link *models.CardLink
+	r.h.read(func(d *memoryData) {
+		if row, ok := d.links[id]; ok {
+			l := models.CardLink(row)
+			link = &l
+		}
+	})
+	if link == nil {
+		return nil, sql.ErrNoRows
+	}
+	return link, nil
+}
+
+func (r *MemoryCardLinkRepository) GetByCardID(cardID int64) ([]models.CardLink, error) {
+	var links []models.CardLink
+	r.h.read(func(d *memoryData) {
+		rows := sortedRows(d.links,
+			func(row cardLinkRow) bool { return row.CardID == cardID },
+			func(a, b cardLinkRow) int { return cmp.Compare(a.Position, b.Position) },
+			func(row cardLinkRow) int64 { return row.ID },
+		)
+		for _, row := range rows {
+			links = append(links, models.CardLink(row))
+		}
+	})
+	return links, nil
+}
+
+func (r *MemoryCardLinkRepository) Create(link *models.CardLink) error {
+	return r.h.write(func(d *memoryData) error {
+		if _, ok := d.cards[link.CardID]; !ok {
+			return errMemoryForeignKey
+		}
+		link.Position = maxPosition(d.links,
+			func(row cardLinkRow) bool { return row.CardID == link.CardID },
+			func(row cardLinkRow) int { return row.Position },
+		) + 1
+
+		row := cardLinkRow(*link)
+		row.ID = d.nextID("card_links")
+		row.CreatedAt = memoryNow()
+		d.links[row.ID] = row
+		link.ID = row.ID
+		return nil
+	})
+}
+
+func (r *MemoryCardLinkRepository) Delete(id int64) error {
+	return r.h.write(func(d *memoryData) error {
+		delete(d.links, id)
+		return nil
+	})
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"database/sql"
+	"maps"
+	"slices"
+	"strings"
+
+	"krizzy/internal/models"
+)
+
+type cardTemplateRow models.CardTemplate
+
+// model returns a copy of the template. Empty lists come back empty rather
+// than nil, as they do after the SQL repositories decode their JSON.
+func (r cardTemplateRow) model() models.CardTemplate {
+	tpl := models.CardTemplate(r)
+	tpl.Checklist = append([]string{}, r.Checklist...)
+	tpl.AssigneeIDs = append([]int64{}, r.AssigneeIDs...)
+	tpl.FieldValues = make(map[int64]string, len(r.FieldValues))
+	maps.Copy(tpl.FieldValues, r.FieldValues)
+	return tpl
+}
+
+type MemoryCardTemplateRepository struct {
+	h memoryHandle
+}
+
+func (r *MemoryCardTemplateRepository) GetByID(id int64) (*models.CardTemplate, error) {
+	var tpl *models.CardTemplate
+	r.h.read(func(d *memoryData) {
+		if row, ok := d.templates[id]; ok {
+			t := row.model()
+			tpl = &t
+		}
+	})
+	if tpl == nil {
+		return nil, sql.ErrNoRows
+	}
+	return tpl, nil
+}
+
+func (r *MemoryCardTemplateRepository) GetByBoardID(boardID int64) ([]models.CardTemplate, error) {
+	var templates []models.CardTemplate
+	r.h.read(func(d *memoryData) {
+		rows := sortedRows(d.templates,
+			func(row cardTemplateRow) bool { return row.BoardID == boardID },
+			func(a, b cardTemplateRow) int { return strings.Compare(a.Name, b.Name) },
+			func(row cardTemplateRow) int64 { return row.ID },
+		)
+		for _, row := range rows {
+			templates = append(templates, row.model())
+		}
+	})
+	return templates, nil
+}
+
+func (r *MemoryCardTemplateRepository) Create(tpl *models.CardTemplate) error {
+	return r.h.write(func(d *memoryData) error {
+		if _, ok := d.boards[tpl.BoardID]; !ok {
+			return errMemoryForeignKey
+		}
+		row := cardTemplateRow(*tpl)
+		row.ID = d.nextID("card_templates")
+		row.Checklist = slices.Clone(tpl.Checklist)
+		row.AssigneeIDs = slices.Clone(tpl.AssigneeIDs)
+		row.FieldValues = maps.Clone(tpl.FieldValues)
+		row.CreatedAt = memoryNow()
+		d.templates[row.ID] = row
+		tpl.ID = row.ID
+		return nil
+	})
+}
+
+func (r *MemoryCardTemplateRepository) Delete(id int64) error {
+	return r.h.write(func(d *memoryData) error {
+		d.deleteTemplate(id)
+		return nil
+	})
+}
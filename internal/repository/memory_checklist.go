@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"cmp"
+	"database/sql"
+
+	"krizzy/internal/models"
+)
+
+type checklistRow models.ChecklistItem
+
+type MemoryChecklistRepository struct {
+	h memoryHandle
+}
+
+func (r *MemoryChecklistRepository) GetByID(id int64) (*models.ChecklistItem, error) {
+	var item *models.ChecklistItem
+	r.h.read(func(d *memoryData) {
+		if row, ok := d.checklist[id]; ok {
+			i := models.ChecklistItem(row)
+			item = &i
+		}
+	})
+	if item == nil {
+		return nil, sql.ErrNoRows
+	}
+	return item, nil
+}
+
+func (r *MemoryChecklistRepository) GetByCardID(cardID int64) ([]models.ChecklistItem, error) {
+	var items []models.ChecklistItem
+	r.h.read(func(d *memoryData) {
+		rows := sortedRows(d.checklist,
+			func(row checklistRow) bool { return row.CardID == cardID },
+			func(a, b checklistRow) int { return cmp.Compare(a.Position, b.Position) },
+			func(row checklistRow) int64 { return row.ID },
+		)
+		for _, row := range rows {
+			items = append(items, models.ChecklistItem(row))
+		}
+	})
+	return items, nil
+}
+
+func (r *MemoryChecklistRepository) Create(item *models.ChecklistItem) error {
+	return r.h.write(func(d *memoryData) error {
+		if _, ok := d.cards[item.CardID]; !ok {
+			return errMemoryForeignKey
+		}
+		item.Position = d.maxChecklistPosition(item.CardID) + 1
+
+		row := checklistRow(*item)
+		row.ID = d.nextID("checklist_items")
+		row.CreatedAt = memoryNow()
+		d.checklist[row.ID] = row
+		item.ID = row.ID
+		return nil
+	})
+}
+
+func (r *MemoryChecklistRepository) Update(item *models.ChecklistItem) error {
+	return r.h.write(func(d *memoryData) error {
+		row, ok := d.checklist[item.ID]
+		if !ok {
+			return nil
+		}
+		row.Content = item.Content
+		row.IsCompleted = item.IsCompleted
+		d.checklist[row.ID] = row
+		return nil
+	})
+}
+
+func (r *MemoryChecklistRepository) Delete(id int64) error {
+	return r.h.write(func(d *memoryData) error {
+		delete(d.checklist, id)
+		return nil
+	})
+}
+
+func (r *MemoryChecklistRepository) Reorder(cardID int64, itemIDs []int64) error {
+	return r.h.write(func(d *memoryData) error {
+		for i, id := range itemIDs {
+			if row, ok := d.checklist[id]; ok && row.CardID == cardID {
+				row.Position = i
+				d.checklist[id] = row
+			}
+		}
+		return nil
+	})
+}
+
+func (r *MemoryChecklistRepository) GetMaxPosition(cardID int64) (int, error) {
+	var maxPos int
+	r.h.read(func(d *memoryData) {
+		maxPos = d.maxChecklistPosition(cardID)
+	})
+	return maxPos, nil
+}
+
+func (d *memoryData) maxChecklistPosition(cardID int64) int {
+	return maxPosition(d.checklist,
+		func(row checklistRow) bool { return row.CardID == cardID },
+		func(row checklistRow) int { return row.Position },
+	)
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"cmp"
+	"database/sql"
+
+	"krizzy/internal/models"
+)
+
+// columnRow is a stored column, without its cards
+type columnRow models.Column
+
+func (r columnRow) model() models.Column {
+	return models.Column(r)
+}
+
+type MemoryColumnRepository struct {
+	h memoryHandle
+}
+
+func (r *MemoryColumnRepository) GetByID(id int64) (*models.Column, error) {
+	var column *models.Column
+	r.h.read(func(d *memoryData) {
+		if row, ok := d.columns[id]; ok {
+			c := row.model()
+			column = &c
+		}
+	})
+	if column == nil {
+		return nil, sql.ErrNoRows
+	}
+	return column, nil
+}
+
+func (r *MemoryColumnRepository) GetByBoardID(boardID int64) ([]models.Column, error) {
+	var columns []models.Column
+	r.h.read(func(d *memoryData) {
+		rows := sortedRows(d.columns,
+			func(row columnRow) bool { return row.BoardID == boardID },
+			func(a, b columnRow) int { return cmp.Compare(a.Position, b.Position) },
+			func(row columnRow) int64 { return row.ID },
+		)
+		for _, row := range rows {
+			columns = append(columns, row.model())
+		}
+	})
+	return columns, nil
+}
+
+func (r *MemoryColumnRepository) Create(column *models.Column) error {
+	return r.h.write(func(d *memoryData) error {
+		if _, ok := d.boards[column.BoardID]; !ok {
+			return errMemoryForeignKey
+		}
+		column.Position = maxPosition(d.columns,
+			func(row columnRow) bool { return row.BoardID == column.BoardID },
+			func(row columnRow) int { return row.Position },
+		) + 1
+
+		row := columnRow{
+			ID:           d.nextID("columns"),
+			BoardID:      column.BoardID,
+			Name:         column.Name,
+			Position:     column.Position,
+			IsDoneColumn: column.IsDoneColumn,
+			Resolution:   column.Resolution,
+			CreatedAt:    memoryNow(),
+		}
+		d.columns[row.ID] = row
+		column.ID = row.ID
+		return nil
+	})
+}
+
+func (r *MemoryColumnRepository) Update(column *models.Column) error {
+	return r.h.write(func(d *memoryData) error {
+		row, ok := d.columns[column.ID]
+		if !ok {
+			return nil
+		}
+		row.Name = column.Name
+		row.IsDoneColumn = column.IsDoneColumn
+		row.Resolution = column.Resolution
+		d.columns[row.ID] = row
+		return nil
+	})
+}
+
+func (r *MemoryColumnRepository) Delete(id int64) error {
+	return r.h.write(func(d *memoryData) error {
+		d.deleteColumn(id)
+		return nil
+	})
+}
+
+func (r *MemoryColumnRepository) Reorder(boardID int64, columnIDs []int64) error {
+	return r.h.write(func(d *memoryData) error {
+		for i, id := range columnIDs {
+			if row, ok := d.columns[id]; ok && row.BoardID == boardID {
+				row.Position = i
+				d.columns[id] = row
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"cmp"
+	"database/sql"
+
+	"krizzy/internal/models"
+)
+
+type commentRow models.Comment
+
+type MemoryCommentRepository struct {
+	h memoryHandle
+}
+
+func (r *MemoryCommentRepository) GetByID(id int64) (*models.Comment, error) {
+	var comment *models.Comment
+	r.h.read(func(d *memoryData) {
+		if row, ok := d.comments[id]; ok {
+			c := models.Comment(row)
+			comment = &c
+		}
+	})
+	if comment == nil {
+		return nil, sql.ErrNoRows
+	}
+	return comment, nil
+}
+
+// GetByCardID returns a card's comments, newest first
+func (r *MemoryCommentRepository) GetByCardID(cardID int64) ([]models.Comment, error) {
+	var comments []models.Comment
+	r.h.read(func(d *memoryData) {
+		rows := sortedRows(d.comments,
+			func(row commentRow) bool { return row.CardID == cardID },
+			func(a, b commentRow) int {
+				if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+					return c
+				}
+				return cmp.Compare(b.ID, a.ID)
+			},
+			func(row commentRow) int64 { return row.ID },
+		)
+		for _, row := range rows {
+			comments = append(comments, models.Comment(row))
+		}
+	})
+	return comments, nil
+}
+
+func (r *MemoryCommentRepository) Create(comment *models.Comment) error {
+	return r.h.write(func(d *memoryData) error {
+		if _, ok := d.cards[comment.CardID]; !ok {
+			return errMemoryForeignKey
+		}
+		row := commentRow(*comment)
+		row.ID = d.nextID("comments")
+		if row.CreatedAt.IsZero() {
+			row.CreatedAt = memoryNow()
+		}
+		d.comments[row.ID] = row
+		comment.ID = row.ID
+		return nil
+	})
+}
+
+func (r *MemoryCommentRepository) Delete(id int64) error {
+	return r.h.write(func(d *memoryData) error {
+		delete(d.comments, id)
+		return nil
+	})
+}
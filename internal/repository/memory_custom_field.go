@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"cmp"
+	"database/sql"
+	"slices"
+
+	"krizzy/internal/models"
+)
+
+type customFieldRow models.CustomField
+
+func (r customFieldRow) model() models.CustomField {
+	field := models.CustomField(r)
+	field.Options = slices.Clone(r.Options)
+	return field
+}
+
+type MemoryCustomFieldRepository struct {
+	h memoryHandle
+}
+
+func (r *MemoryCustomFieldRepository) GetByID(id int64) (*models.CustomField, error) {
+	var field *models.CustomField
+	r.h.read(func(d *memoryData) {
+		if row, ok := d.fields[id]; ok {
+			f := row.model()
+			field = &f
+		}
+	})
+	if field == nil {
+		return nil, sql.ErrNoRows
+	}
+	return field, nil
+}
+
+func (r *MemoryCustomFieldRepository) GetByBoardID(boardID int64) ([]models.CustomField, error) {
+	var fields []models.CustomField
+	r.h.read(func(d *memoryData) {
+		rows := sortedRows(d.fields,
+			func(row customFieldRow) bool { return row.BoardID == boardID },
+			func(a, b customFieldRow) int { return cmp.Compare(a.Position, b.Position) },
+			func(row customFieldRow) int64 { return row.ID },
+		)
+		for _, row := range rows {
+			fields = append(fields, row.model())
+		}
+	})
+	return fields, nil
+}
+
+func (r *MemoryCustomFieldRepository) Create(field *models.CustomField) error {
+	return r.h.write(func(d *memoryData) error {
+		if _, ok := d.boards[field.BoardID]; !ok {
+			return errMemoryForeignKey
+		}
+		field.Position = maxPosition(d.fields,
+			func(row customFieldRow) bool { return row.BoardID == field.BoardID },
+			func(row customFieldRow) int { return row.Position },
+		) + 1
+
+		row := customFieldRow(*field)
+		row.ID = d.nextID("custom_fields")
+		row.Options = slices.Clone(field.Options)
+		row.CreatedAt = memoryNow()
+		d.fields[row.ID] = row
+		field.ID = row.ID
+		return nil
+	})
+}
+
+// Update saves a field's name and options. The type is fixed once created
+// so stored values keep their meaning.
+func (r *MemoryCustomFieldRepository) Update(field *models.CustomField) error {
+	return r.h.write(func(d *memoryData) error {
+		row, ok := d.fields[field.ID]
+		if !ok {
+			return nil
+		}
+		row.Name = field.Name
+		row.Options = slices.Clone(field.Options)
+		d.fields[row.ID] = row
+		return nil
+	})
+}
+
+func (r *MemoryCustomFieldRepository) Delete(id int64) error {
+	return r.h.write(func(d *memoryData) error {
+		d.deleteField(id)
+		return nil
+	})
+}
+
+func (r *MemoryCustomFieldRepository) GetValuesByCardID(cardID int64) (map[int64]string, error) {
+	values := make(map[int64]string)
+	r.h.read(func(d *memoryData) {
+		for key, value := range d.fieldValues {
+			if key.cardID == cardID {
+				values[key.fieldID] = value
+			}
+		}
+	})
+	return values, nil
+}
+
+// SetValue stores a card's value for a field. An empty value clears it.
+func (r *MemoryCustomFieldRepository) SetValue(cardID, fieldID int64, value string) error {
+	return r.h.write(func(d *memoryData) error {
+		key := cardField{cardID, fieldID}
+		if value == "" {
+			delete(d.fieldValues, key)
+			return nil
+		}
+		if _, ok := d.cards[cardID]; !ok {
+			return errMemoryForeignKey
+		}
+		if _, ok := d.fields[fieldID]; !ok {
+			return errMemoryForeignKey
+		}
+		d.fieldValues[key] = value
+		return nil
+	})
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"database/sql"
+	"strings"
+
+	"krizzy/internal/models"
+)
+
+type personRow models.Person
+
+type MemoryPersonRepository struct {
+	h memoryHandle
+}
+
+func (r *MemoryPersonRepository) GetByID(id int64) (*models.Person, error) {
+	var person *models.Person
+	r.h.read(func(d *memoryData) {
+		if row, ok := d.people[id]; ok {
+			p := models.Person(row)
+			person = &p
+		}
+	})
+	if person == nil {
+		return nil, sql.ErrNoRows
+	}
+	return person, nil
+}
+
+func (r *MemoryPersonRepository) GetByBoardID(boardID int64) ([]models.Person, error) {
+	var people []models.Person
+	r.h.read(func(d *memoryData) {
+		people = sortedPeople(d, func(row personRow) bool { return row.BoardID == boardID })
+	})
+	return people, nil
+}
+
+func sortedPeople(d *memoryData, keep func(personRow) bool) []models.Person {
+	rows := sortedRows(d.people, keep,
+		func(a, b personRow) int { return strings.Compare(a.Name, b.Name) },
+		func(row personRow) int64 { return row.ID },
+	)
+	var people []models.Person
+	for _, row := range rows {
+		people = append(people, models.Person(row))
+	}
+	return people
+}
+
+// nameTaken applies the UNIQUE (board_id, name) constraint on people
+func (d *memoryData) nameTaken(person *models.Person) bool {
+	for _, row := range d.people {
+		if row.ID != person.ID && row.BoardID == person.BoardID && row.Name == person.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *MemoryPersonRepository) Create(person *models.Person) error {
+	if person.Color == "" {
+		person.Color = models.DefaultPersonColor
+	}
+	return r.h.write(func(d *memoryData) error {
+		if _, ok := d.boards[person.BoardID]; !ok {
+			return errMemoryForeignKey
+		}
+		if d.nameTaken(&models.Person{BoardID: person.BoardID, Name: person.Name}) {
+			return errMemoryUnique
+		}
+
+		row := personRow{
+			ID:        d.nextID("people"),
+			BoardID:   person.BoardID,
+			Name:      person.Name,
+			Color:     person.Color,
+			CreatedAt: memoryNow(),
+		}
+		d.people[row.ID] = row
+		person.ID = row.ID
+		return nil
+	})
+}
+
+func (r *MemoryPersonRepository) Update(person *models.Person) error {
+	return r.h.write(func(d *memoryData) error {
+		row, ok := d.people[person.ID]
+		if !ok || row.BoardID != person.BoardID {
+			return nil
+		}
+		if d.nameTaken(person) {
+			return errMemoryUnique
+		}
+		row.Name = person.Name
+		row.Color = person.Color
+		d.people[row.ID] = row
+		return nil
+	})
+}
+
+func (r *MemoryPersonRepository) Delete(id int64) error {
+	return r.h.write(func(d *memoryData) error {
+		d.deletePerson(id)
+		return nil
+	})
+}
+
+func (r *MemoryPersonRepository) GetByCardID(cardID int64) ([]models.Person, error) {
+	var people []models.Person
+	r.h.read(func(d *memoryData) {
+		people = sortedPeople(d, func(row personRow) bool {
+			_, ok := d.assignees[cardPerson{cardID, row.ID}]
+			return ok
+		})
+	})
+	return people, nil
+}
+
+func (r *MemoryPersonRepository) SetCardAssignees(cardID int64, personIDs []int64) error {
+	return r.h.write(func(d *memoryData) error {
+		if len(personIDs) > 0 {
+			if _, ok := d.cards[cardID]; !ok {
+				return errMemoryForeignKey
+			}
+		}
+		seen := make(map[int64]bool, len(personIDs))
+		for _, personID := range personIDs {
+			if _, ok := d.people[personID]; !ok {
+				return errMemoryForeignKey
+			}
+			if seen[personID] {
+				return errMemoryUnique
+			}
+			seen[personID] = true
+		}
+
+		for key := range d.assignees {
+			if key.cardID == cardID {
+				delete(d.assignees, key)
+			}
+		}
+		for _, personID := range personIDs {
+			d.assignees[cardPerson{cardID, personID}] = struct{}{}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"database/sql"
+	"strings"
+
+	"krizzy/internal/models"
+)
+
+type pgConnectionRow models.PgConnection
+
+type MemoryPgConnectionRepository struct {
+	h memoryHandle
+}
+
+func (r *MemoryPgConnectionRepository) GetByID(id int64) (*models.PgConnection, error) {
+	var conn *models.PgConnection
+	r.h.read(func(d *memoryData) {
+		if row, ok := d.pgConnections[id]; ok {
+			c := models.PgConnection(row)
+			conn = &c
+		}
+	})
+	if conn == nil {
+		return nil, sql.ErrNoRows
+	}
+	return conn, nil
+}
+
+func (r *MemoryPgConnectionRepository) GetAll() ([]models.PgConnection, error) {
+	var conns []models.PgConnection
+	r.h.read(func(d *memoryData) {
+		rows := sortedRows(d.pgConnections,
+			func(pgConnectionRow) bool { return true },
+			func(a, b pgConnectionRow) int { return strings.Compare(a.Name, b.Name) },
+			func(row pgConnectionRow) int64 { return row.ID },
+		)
+		for _, row := range rows {
+			conns = append(conns, models.PgConnection(row))
+		}
+	})
+	return conns, nil
+}
+
+func (r *MemoryPgConnectionRepository) Create(conn *models.PgConnection) error {
+	if conn.Port == 0 {
+		conn.Port = 5432
+	}
+	if conn.SSLMode == "" {
+		conn.SSLMode = "disable"
+	}
+	return r.h.write(func(d *memoryData) error {
+		row := pgConnectionRow(*conn)
+		row.ID = d.nextID("pg_connections")
+		row.CreatedAt = memoryNow()
+		d.pgConnections[row.ID] = row
+		conn.ID = row.ID
+		return nil
+	})
+}
+
+func (r *MemoryPgConnectionRepository) Update(conn *models.PgConnection) error {
+	if conn.Port == 0 {
+		conn.Port = 5432
+	}
+	if conn.SSLMode == "" {
+		conn.SSLMode = "disable"
+	}
+	return r.h.write(func(d *memoryData) error {
+		old, ok := d.pgConnections[conn.ID]
+		if !ok {
+			return nil
+		}
+		row := pgConnectionRow(*conn)
+		row.CreatedAt = old.CreatedAt
+		d.pgConnections[row.ID] = row
+		return nil
+	})
+}
+
+// Delete refuses to remove a connection boards still use, as the foreign key
+// on boards does
+func (r *MemoryPgConnectionRepository) Delete(id int64) error {
+	return r.h.write(func(d *memoryData) error {
+		for _, board := range d.boards {
+			if board.PgConnectionID != nil && *board.PgConnectionID == id {
+				return errMemoryForeignKey
+			}
+		}
+		delete(d.pgConnections, id)
+		return nil
+	})
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"cmp"
+	"database/sql"
+	"time"
+
+	"krizzy/internal/models"
+)
+
+type recurrenceRow models.Recurrence
+
+func (r recurrenceRow) model() models.Recurrence {
+	rec := models.Recurrence(r)
+	rec.NextRunAt = clonePtr(r.NextRunAt)
+	rec.LastRunAt = clonePtr(r.LastRunAt)
+	return rec
+}
+
+// memoryRunTime stores a run time the way nullRunTime writes it
+func memoryRunTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	v := runTime(*t)
+	return &v
+}
+
+type MemoryRecurrenceRepository struct {
+	h memoryHandle
+}
+
+func (r *MemoryRecurrenceRepository) GetByID(id int64) (*models.Recurrence, error) {
+	var rec *models.Recurrence
+	r.h.read(func(d *memoryData) {
+		if row, ok := d.recurrences[id]; ok {
+			v := row.model()
+			rec = &v
+		}
+	})
+	if rec == nil {
+		return nil, sql.ErrNoRows
+	}
+	return rec, nil
+}
+
+func (r *MemoryRecurrenceRepository) GetByBoardID(boardID int64) ([]models.Recurrence, error) {
+	var recurrences []models.Recurrence
+	r.h.read(func(d *memoryData) {
+		rows := sortedRows(d.recurrences,
+			func(row recurrenceRow) bool { return row.BoardID == boardID },
+			func(a, b recurrenceRow) int { return cmp.Compare(a.ID, b.ID) },
+			func(row recurrenceRow) int64 { return row.ID },
+		)
+		for _, row := range rows {
+			recurrences = append(recurrences, row.model())
+		}
+	})
+	return recurrences, nil
+}
+
+// checkRecurrenceRefs applies the foreign keys of a recurrence's template and column
+func (d *memoryData) checkRecurrenceRefs(rec *models.Recurrence) error {
+	if _, ok := d.templates[rec.TemplateID]; !ok {
+		return errMemoryForeignKey
+	}
+	if _, ok := d.columns[rec.ColumnID]; !ok {
+		return errMemoryForeignKey
+	}
+	return nil
+}
+
+func (r *MemoryRecurrenceRepository) Create(rec *models.Recurrence) error {
+	return r.h.write(func(d *memoryData) error {
+		if _, ok := d.boards[rec.BoardID]; !ok {
+			return errMemoryForeignKey
+		}
+		if err := d.checkRecurrenceRefs(rec); err != nil {
+			return err
+		}
+		row := recurrenceRow{
+			ID:         d.nextID("recurrences"),
+			BoardID:    rec.BoardID,
+			TemplateID: rec.TemplateID,
+			ColumnID:   rec.ColumnID,
+			Schedule:   rec.Schedule,
+			Enabled:    rec.Enabled,
+			NextRunAt:  memoryRunTime(rec.NextRunAt),
+			CreatedAt:  memoryNow(),
+		}
+		d.recurrences[row.ID] = row
+		rec.ID = row.ID
+		return nil
+	})
+}
+
+func (r *MemoryRecurrenceRepository) Update(rec *models.Recurrence) error {
+	return r.h.write(func(d *memoryData) error {
+		row, ok := d.recurrences[rec.ID]
+		if !ok {
+			return nil
+		}
+		if err := d.checkRecurrenceRefs(rec); err != nil {
+			return err
+		}
+		row.TemplateID = rec.TemplateID
+		row.ColumnID = rec.ColumnID
+		row.Schedule = rec.Schedule
+		row.Enabled = rec.Enabled
+		row.NextRunAt = memoryRunTime(rec.NextRunAt)
+		d.recurrences[row.ID] = row
+		return nil
+	})
+}
+
+func (r *MemoryRecurrenceRepository) Delete(id int64) error {
+	return r.h.write(func(d *memoryData) error {
+		delete(d.recurrences, id)
+		return nil
+	})
+}
+
+func (r *MemoryRecurrenceRepository) Advance(id int64, expected time.Time, next *time.Time, ranAt time.Time) (bool, error) {
+	claimed := false
+	err := r.h.write(func(d *memoryData) error {
+		row, ok := d.recurrences[id]
+		if !ok || !row.Enabled || row.NextRunAt == nil || !row.NextRunAt.Equal(runTime(expected)) {
+			return nil
+		}
+		row.NextRunAt = memoryRunTime(next)
+		row.LastRunAt = memoryRunTime(&ranAt)
+		d.recurrences[id] = row
+		claimed = true
+		return nil
+	})
+	return claimed, err
+}
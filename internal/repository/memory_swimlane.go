@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"cmp"
+	"database/sql"
+
+	"krizzy/internal/models"
+)
+
+type swimlaneRow models.Swimlane
+
+type MemorySwimlaneRepository struct {
+	h memoryHandle
+}
+
+func (r *MemorySwimlaneRepository) GetByID(id int64) (*models.Swimlane, error) {
+	var lane *models.Swimlane
+	r.h.read(func(d *memoryData) {
+		if row, ok := d.swimlanes[id]; ok {
+			l := models.Swimlane(row)
+			lane = &l
+		}
+	})
+	if lane == nil {
+		return nil, sql.ErrNoRows
+	}
+	return lane, nil
+}
+
+func (r *MemorySwimlaneRepository) GetByBoardID(boardID int64) ([]models.Swimlane, error) {
+	var lanes []models.Swimlane
+	r.h.read(func(d *memoryData) {
+		rows := sortedRows(d.swimlanes,
+			func(row swimlaneRow) bool { return row.BoardID == boardID },
+			func(a, b swimlaneRow) int { return cmp.Compare(a.Position, b.Position) },
+			func(row swimlaneRow) int64 { return row.ID },
+		)
+		for _, row := range rows {
+			lanes = append(lanes, models.Swimlane(row))
+		}
+	})
+	return lanes, nil
+}
+
+func (r *MemorySwimlaneRepository) Create(lane *models.Swimlane) error {
+	return r.h.write(func(d *memoryData) error {
+		if _, ok := d.boards[lane.BoardID]; !ok {
+			return errMemoryForeignKey
+		}
+		lane.Position = maxPosition(d.swimlanes,
+			func(row swimlaneRow) bool { return row.BoardID == lane.BoardID },
+			func(row swimlaneRow) int { return row.Position },
+		) + 1
+
+		row := swimlaneRow(*lane)
+		row.ID = d.nextID("swimlanes")
+		row.CreatedAt = memoryNow()
+		d.swimlanes[row.ID] = row
+		lane.ID = row.ID
+		return nil
+	})
+}
+
+func (r *MemorySwimlaneRepository) Update(lane *models.Swimlane) error {
+	return r.h.write(func(d *memoryData) error {
+		row, ok := d.swimlanes[lane.ID]
+		if !ok {
+			return nil
+		}
+		row.Name = lane.Name
+		row.Collapsed = lane.Collapsed
+		d.swimlanes[row.ID] = row
+		return nil
+	})
+}
+
+// Delete removes a swimlane and moves its cards to the end of the unlaned
+// row in their columns
+func (r *MemorySwimlaneRepository) Delete(id int64) error {
+	return r.h.write(func(d *memoryData) error {
+		offsets := make(map[int64]int)
+		for _, card := range d.cards {
+			if card.SwimlaneID == nil || *card.SwimlaneID != id {
+				continue
+			}
+			if _, ok := offsets[card.ColumnID]; ok {
+				continue
+			}
+			columnID := card.ColumnID
+			offsets[columnID] = maxPosition(d.cards,
+				func(row cardRow) bool { return row.ColumnID == columnID && row.SwimlaneID == nil },
+				func(row cardRow) int { return row.Position },
+			) + 1
+		}
+
+		for _, card := range d.cards {
+			if card.SwimlaneID == nil || *card.SwimlaneID != id {
+				continue
+			}
+			// Archived cards stay outside the ordering and just lose their lane
+			if card.ArchivedAt == nil {
+				card.Position += offsets[card.ColumnID]
+			}
+			card.SwimlaneID = nil
+			d.cards[card.ID] = card
+		}
+
+		delete(d.swimlanes, id)
+		return nil
+	})
+}
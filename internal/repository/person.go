@@ -1,15 +1,14 @@
 package repository
 
 import (
-	"database/sql"
 	"krizzy/internal/models"
 )
 
 type SQLitePersonRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewSQLitePersonRepository(db *sql.DB) *SQLitePersonRepository {
+func NewSQLitePersonRepository(db DBTX) *SQLitePersonRepository {
 	return &SQLitePersonRepository{db: db}
 }
 
@@ -71,8 +70,21 @@ func (r *SQLitePersonRepository) Update(person *models.Person) error {
 }
 
 func (r *SQLitePersonRepository) Delete(id int64) error {
-	_, err := r.db.Exec("DELETE FROM people WHERE id = ?", id)
-	return err
+	tx, err := beginTx(r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Assignments go first, so no card is left pointing at the person
+	if _, err := tx.Exec("DELETE FROM card_assignees WHERE person_id = ?", id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM people WHERE id = ?", id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (r *SQLitePersonRepository) GetByCardID(cardID int64) ([]models.Person, error) {
@@ -101,7 +113,7 @@ func (r *SQLitePersonRepository) GetByCardID(cardID int64) ([]models.Person, err
 }
 
 func (r *SQLitePersonRepository) SetCardAssignees(cardID int64, personIDs []int64) error {
-	tx, err := r.db.Begin()
+	tx, err := beginTx(r.db)
 	if err != nil {
 		return err
 	}
@@ -7,38 +7,42 @@ import (
 )
 
 type PgCardRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewPgCardRepository(db *sql.DB) *PgCardRepository {
+func NewPgCardRepository(db DBTX) *PgCardRepository {
 	return &PgCardRepository{db: db}
 }
 
 func (r *PgCardRepository) GetByID(id int64) (*models.Card, error) {
-	card := &models.Card{}
-	var completedAt sql.NullTime
-	var description sql.NullString
-	err := r.db.QueryRow(
-		"SELECT id, column_id, title, description, position, completed_at, created_at, updated_at FROM cards WHERE id = $1",
+	return scanCard(r.db.QueryRow(
+		"SELECT id, column_id, swimlane_id, title, description, position, completed_at, resolution, archived_at, created_at, updated_at FROM cards WHERE id = $1",
 		id,
-	).Scan(&card.ID, &card.ColumnID, &card.Title, &description, &card.Position, &completedAt, &card.CreatedAt, &card.UpdatedAt)
-	if err != nil {
-		return nil, err
-	}
-	if completedAt.Valid {
-		card.CompletedAt = &completedAt.Time
-	}
-	if description.Valid {
-		card.Description = description.String
-	}
-	return card, nil
+	))
 }
 
+// GetByColumnID returns a column's cards in order, leaving out archived cards
 func (r *PgCardRepository) GetByColumnID(columnID int64) ([]models.Card, error) {
-	rows, err := r.db.Query(
-		"SELECT id, column_id, title, description, position, completed_at, created_at, updated_at FROM cards WHERE column_id = $1 ORDER BY position",
+	return r.queryCards(
+		"SELECT id, column_id, swimlane_id, title, description, position, completed_at, resolution, archived_at, created_at, updated_at FROM cards WHERE column_id = $1 AND archived_at IS NULL ORDER BY position",
 		columnID,
 	)
+}
+
+// GetArchivedByBoardID returns a board's archived cards, most recently
+// archived first
+func (r *PgCardRepository) GetArchivedByBoardID(boardID int64) ([]models.Card, error) {
+	return r.queryCards(
+		`SELECT cards.id, cards.column_id, cards.swimlane_id, cards.title, cards.description, cards.position, cards.completed_at, cards.resolution, cards.archived_at, cards.created_at, cards.updated_at
+		FROM cards JOIN columns ON columns.id = cards.column_id
+		WHERE columns.board_id = $1 AND cards.archived_at IS NOT NULL
+		ORDER BY cards.archived_at DESC, cards.id DESC`,
+		boardID,
+	)
+}
+
+func (r *PgCardRepository) queryCards(query string, args ...any) ([]models.Card, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -46,41 +50,33 @@ func (r *PgCardRepository) GetByColumnID(columnID int64) ([]models.Card, error)
 
 	var cards []models.Card
 	for rows.Next() {
-		var card models.Card
-		var completedAt sql.NullTime
-		var description sql.NullString
-		if err := rows.Scan(&card.ID, &card.ColumnID, &card.Title, &description, &card.Position, &completedAt, &card.CreatedAt, &card.UpdatedAt); err != nil {
+		card, err := scanCard(rows)
+		if err != nil {
 			return nil, err
 		}
-		if completedAt.Valid {
-			card.CompletedAt = &completedAt.Time
-		}
-		if description.Valid {
-			card.Description = description.String
-		}
-		cards = append(cards, card)
+		cards = append(cards, *card)
 	}
 	return cards, rows.Err()
 }
 
 func (r *PgCardRepository) Create(card *models.Card) error {
-	maxPos, err := r.GetMaxPosition(card.ColumnID)
+	maxPos, err := r.GetMaxPosition(card.ColumnID, card.SwimlaneID)
 	if err != nil {
 		return err
 	}
 	card.Position = maxPos + 1
 
 	err = r.db.QueryRow(
-		"INSERT INTO cards (column_id, title, description, position) VALUES ($1, $2, $3, $4) RETURNING id",
-		card.ColumnID, card.Title, card.Description, card.Position,
+		"INSERT INTO cards (column_id, swimlane_id, title, description, position) VALUES ($1, $2, $3, $4, $5) RETURNING id",
+		card.ColumnID, card.SwimlaneID, card.Title, card.Description, card.Position,
 	).Scan(&card.ID)
 	return err
 }
 
 func (r *PgCardRepository) Update(card *models.Card) error {
 	_, err := r.db.Exec(
-		"UPDATE cards SET title = $1, description = $2, completed_at = $3, updated_at = $4 WHERE id = $5",
-		card.Title, card.Description, card.CompletedAt, time.Now(), card.ID,
+		"UPDATE cards SET title = $1, description = $2, completed_at = $3, resolution = $4, updated_at = $5 WHERE id = $6",
+		card.Title, card.Description, card.CompletedAt, card.Resolution, time.Now(), card.ID,
 	)
 	return err
 }
@@ -90,33 +86,34 @@ func (r *PgCardRepository) Delete(id int64) error {
 	return err
 }
 
-func (r *PgCardRepository) Move(cardID int64, newColumnID int64, newPosition int) error {
-	tx, err := r.db.Begin()
+func (r *PgCardRepository) Move(cardID int64, newColumnID int64, newSwimlaneID *int64, newPosition int) error {
+	tx, err := beginTx(r.db)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
 	var oldColumnID int64
+	var oldSwimlaneID *int64
 	var oldPosition int
 	err = tx.QueryRow(
-		"SELECT column_id, position FROM cards WHERE id = $1",
+		"SELECT column_id, swimlane_id, position FROM cards WHERE id = $1",
 		cardID,
-	).Scan(&oldColumnID, &oldPosition)
+	).Scan(&oldColumnID, &oldSwimlaneID, &oldPosition)
 	if err != nil {
 		return err
 	}
 
-	if oldColumnID == newColumnID {
+	if oldColumnID == newColumnID && sameSwimlane(oldSwimlaneID, newSwimlaneID) {
 		if oldPosition < newPosition {
 			_, err = tx.Exec(
-				"UPDATE cards SET position = position - 1 WHERE column_id = $1 AND position > $2 AND position <= $3",
-				oldColumnID, oldPosition, newPosition,
+				"UPDATE cards SET position = position - 1 WHERE column_id = $1 AND swimlane_id IS NOT DISTINCT FROM $2 AND position > $3 AND position <= $4",
+				oldColumnID, oldSwimlaneID, oldPosition, newPosition,
 			)
 		} else if oldPosition > newPosition {
 			_, err = tx.Exec(
-				"UPDATE cards SET position = position + 1 WHERE column_id = $1 AND position >= $2 AND position < $3",
-				oldColumnID, newPosition, oldPosition,
+				"UPDATE cards SET position = position + 1 WHERE column_id = $1 AND swimlane_id IS NOT DISTINCT FROM $2 AND position >= $3 AND position < $4",
+				oldColumnID, oldSwimlaneID, newPosition, oldPosition,
 			)
 		}
 		if err != nil {
@@ -124,16 +121,16 @@ func (r *PgCardRepository) Move(cardID int64, newColumnID int64, newPosition int
 		}
 	} else {
 		_, err = tx.Exec(
-			"UPDATE cards SET position = position - 1 WHERE column_id = $1 AND position > $2",
-			oldColumnID, oldPosition,
+			"UPDATE cards SET position = position - 1 WHERE column_id = $1 AND swimlane_id IS NOT DISTINCT FROM $2 AND position > $3",
+			oldColumnID, oldSwimlaneID, oldPosition,
 		)
 		if err != nil {
 			return err
 		}
 
 		_, err = tx.Exec(
-			"UPDATE cards SET position = position + 1 WHERE column_id = $1 AND position >= $2",
-			newColumnID, newPosition,
+			"UPDATE cards SET position = position + 1 WHERE column_id = $1 AND swimlane_id IS NOT DISTINCT FROM $2 AND position >= $3",
+			newColumnID, newSwimlaneID, newPosition,
 		)
 		if err != nil {
 			return err
@@ -141,8 +138,47 @@ func (r *PgCardRepository) Move(cardID int64, newColumnID int64, newPosition int
 	}
 
 	_, err = tx.Exec(
-		"UPDATE cards SET column_id = $1, position = $2, updated_at = $3 WHERE id = $4",
-		newColumnID, newPosition, time.Now(), cardID,
+		"UPDATE cards SET column_id = $1, swimlane_id = $2, position = $3, updated_at = $4 WHERE id = $5",
+		newColumnID, newSwimlaneID, newPosition, time.Now(), cardID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Archive hides a card from its column and closes the gap it leaves.
+// Archived cards sit at position -1, outside their cell's ordering.
+func (r *PgCardRepository) Archive(cardID int64, archivedAt time.Time) error {
+	tx, err := beginTx(r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var columnID int64
+	var swimlaneID *int64
+	var position int
+	err = tx.QueryRow(
+		"SELECT column_id, swimlane_id, position FROM cards WHERE id = $1 AND archived_at IS NULL",
+		cardID,
+	).Scan(&columnID, &swimlaneID, &position)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		"UPDATE cards SET position = position - 1 WHERE column_id = $1 AND swimlane_id IS NOT DISTINCT FROM $2 AND position > $3 AND archived_at IS NULL",
+		columnID, swimlaneID, position,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		"UPDATE cards SET archived_at = $1, position = -1, updated_at = $2 WHERE id = $3",
+		archivedAt, time.Now(), cardID,
 	)
 	if err != nil {
 		return err
@@ -151,11 +187,53 @@ func (r *PgCardRepository) Move(cardID int64, newColumnID int64, newPosition int
 	return tx.Commit()
 }
 
-func (r *PgCardRepository) GetMaxPosition(columnID int64) (int, error) {
+// Restore brings an archived card back at the end of its cell
+func (r *PgCardRepository) Restore(cardID int64) error {
+	tx, err := beginTx(r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var columnID int64
+	var swimlaneID *int64
+	err = tx.QueryRow(
+		"SELECT column_id, swimlane_id FROM cards WHERE id = $1 AND archived_at IS NOT NULL",
+		cardID,
+	).Scan(&columnID, &swimlaneID)
+	if err != nil {
+		return err
+	}
+
+	var maxPos sql.NullInt64
+	err = tx.QueryRow(
+		"SELECT MAX(position) FROM cards WHERE column_id = $1 AND swimlane_id IS NOT DISTINCT FROM $2 AND archived_at IS NULL",
+		columnID, swimlaneID,
+	).Scan(&maxPos)
+	if err != nil {
+		return err
+	}
+	position := 0
+	if maxPos.Valid {
+		position = int(maxPos.Int64) + 1
+	}
+
+	_, err = tx.Exec(
+		"UPDATE cards SET archived_at = NULL, position = $1, updated_at = $2 WHERE id = $3",
+		position, time.Now(), cardID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *PgCardRepository) GetMaxPosition(columnID int64, swimlaneID *int64) (int, error) {
 	var maxPos sql.NullInt64
 	err := r.db.QueryRow(
-		"SELECT MAX(position) FROM cards WHERE column_id = $1",
-		columnID,
+		"SELECT MAX(position) FROM cards WHERE column_id = $1 AND swimlane_id IS NOT DISTINCT FROM $2",
+		columnID, swimlaneID,
 	).Scan(&maxPos)
 	if err != nil {
 		return -1, err
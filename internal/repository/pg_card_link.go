@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"database/sql"
+	"krizzy/internal/models"
+)
+
+type PgCardLinkRepository struct {
+	db DBTX
+}
+
+func NewPgCardLinkRepository(db DBTX) *PgCardLinkRepository {
+	return &PgCardLinkRepository{db: db}
+}
+
+func (r *PgCardLinkRepository) GetByID(id int64) (*models.CardLink, error) {
+	link := &models.CardLink{}
+	err := r.db.QueryRow(
+		"SELECT id, card_id, title, url, position, created_at FROM card_links WHERE id = $1",
+		id,
+	).Scan(&link.ID, &link.CardID, &link.Title, &link.URL, &link.Position, &link.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+func (r *PgCardLinkRepository) GetByCardID(cardID int64) ([]models.CardLink, error) {
+	rows, err := r.db.Query(
+		"SELECT id, card_id, title, url, position, created_at FROM card_links WHERE card_id = $1 ORDER BY position",
+		cardID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []models.CardLink
+	for rows.Next() {
+		var link models.CardLink
+		if err := rows.Scan(&link.ID, &link.CardID, &link.Title, &link.URL, &link.Position, &link.CreatedAt); err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+	return links, rows.Err()
+}
+
+func (r *PgCardLinkRepository) Create(link *models.CardLink) error {
+	var maxPos sql.NullInt64
+	if err := r.db.QueryRow("SELECT MAX(position) FROM card_links WHERE card_id = $1", link.CardID).Scan(&maxPos); err != nil {
+		return err
+	}
+	link.Position = 0
+	if maxPos.Valid {
+		link.Position = int(maxPos.Int64) + 1
+	}
+
+	return r.db.QueryRow(
+		"INSERT INTO card_links (card_id, title, url, position) VALUES ($1, $2, $3, $4) RETURNING id",
+		link.CardID, link.Title, link.URL, link.Position,
+	).Scan(&link.ID)
+}
+
+func (r *PgCardLinkRepository) Delete(id int64) error {
+	_, err := r.db.Exec("DELETE FROM card_links WHERE id = $1", id)
+	return err
+}
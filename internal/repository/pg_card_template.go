@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"krizzy/internal/models"
+)
+
+type PgCardTemplateRepository struct {
+	db DBTX
+}
+
+func NewPgCardTemplateRepository(db DBTX) *PgCardTemplateRepository {
+	return &PgCardTemplateRepository{db: db}
+}
+
+func (r *PgCardTemplateRepository) GetByID(id int64) (*models.CardTemplate, error) {
+	return scanCardTemplate(r.db.QueryRow(
+		"SELECT id, board_id, name, title, description, checklist, assignee_ids, field_values, created_at FROM card_templates WHERE id = $1",
+		id,
+	))
+}
+
+func (r *PgCardTemplateRepository) GetByBoardID(boardID int64) ([]models.CardTemplate, error) {
+	rows, err := r.db.Query(
+		"SELECT id, board_id, name, title, description, checklist, assignee_ids, field_values, created_at FROM card_templates WHERE board_id = $1 ORDER BY name",
+		boardID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []models.CardTemplate
+	for rows.Next() {
+		tpl, err := scanCardTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *tpl)
+	}
+	return templates, rows.Err()
+}
+
+func (r *PgCardTemplateRepository) Create(tpl *models.CardTemplate) error {
+	checklist, assigneeIDs, fieldValues, err := encodeCardTemplate(tpl)
+	if err != nil {
+		return err
+	}
+
+	return r.db.QueryRow(
+		"INSERT INTO card_templates (board_id, name, title, description, checklist, assignee_ids, field_values) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id",
+		tpl.BoardID, tpl.Name, tpl.Title, tpl.Description, checklist, assigneeIDs, fieldValues,
+	).Scan(&tpl.ID)
+}
+
+func (r *PgCardTemplateRepository) Delete(id int64) error {
+	_, err := r.db.Exec("DELETE FROM card_templates WHERE id = $1", id)
+	return err
+}
@@ -6,19 +6,19 @@ import (
 )
 
 type PgChecklistRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewPgChecklistRepository(db *sql.DB) *PgChecklistRepository {
+func NewPgChecklistRepository(db DBTX) *PgChecklistRepository {
 	return &PgChecklistRepository{db: db}
 }
 
 func (r *PgChecklistRepository) GetByID(id int64) (*models.ChecklistItem, error) {
 	item := &models.ChecklistItem{}
 	err := r.db.QueryRow(
-		"SELECT id, card_id, content, is_completed, position, created_at FROM checklist_items WHERE id = $1",
+		"SELECT id, card_id, checklist, content, is_completed, position, created_at FROM checklist_items WHERE id = $1",
 		id,
-	).Scan(&item.ID, &item.CardID, &item.Content, &item.IsCompleted, &item.Position, &item.CreatedAt)
+	).Scan(&item.ID, &item.CardID, &item.Checklist, &item.Content, &item.IsCompleted, &item.Position, &item.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -27,7 +27,7 @@ func (r *PgChecklistRepository) GetByID(id int64) (*models.ChecklistItem, error)
 
 func (r *PgChecklistRepository) GetByCardID(cardID int64) ([]models.ChecklistItem, error) {
 	rows, err := r.db.Query(
-		"SELECT id, card_id, content, is_completed, position, created_at FROM checklist_items WHERE card_id = $1 ORDER BY position",
+		"SELECT id, card_id, checklist, content, is_completed, position, created_at FROM checklist_items WHERE card_id = $1 ORDER BY position",
 		cardID,
 	)
 	if err != nil {
@@ -38,7 +38,7 @@ func (r *PgChecklistRepository) GetByCardID(cardID int64) ([]models.ChecklistIte
 	var items []models.ChecklistItem
 	for rows.Next() {
 		var item models.ChecklistItem
-		if err := rows.Scan(&item.ID, &item.CardID, &item.Content, &item.IsCompleted, &item.Position, &item.CreatedAt); err != nil {
+		if err := rows.Scan(&item.ID, &item.CardID, &item.Checklist, &item.Content, &item.IsCompleted, &item.Position, &item.CreatedAt); err != nil {
 			return nil, err
 		}
 		items = append(items, item)
@@ -54,8 +54,8 @@ func (r *PgChecklistRepository) Create(item *models.ChecklistItem) error {
 	item.Position = maxPos + 1
 
 	err = r.db.QueryRow(
-		"INSERT INTO checklist_items (card_id, content, is_completed, position) VALUES ($1, $2, $3, $4) RETURNING id",
-		item.CardID, item.Content, item.IsCompleted, item.Position,
+		"INSERT INTO checklist_items (card_id, checklist, content, is_completed, position) VALUES ($1, $2, $3, $4, $5) RETURNING id",
+		item.CardID, item.Checklist, item.Content, item.IsCompleted, item.Position,
 	).Scan(&item.ID)
 	return err
 }
@@ -74,7 +74,7 @@ func (r *PgChecklistRepository) Delete(id int64) error {
 }
 
 func (r *PgChecklistRepository) Reorder(cardID int64, itemIDs []int64) error {
-	tx, err := r.db.Begin()
+	tx, err := beginTx(r.db)
 	if err != nil {
 		return err
 	}
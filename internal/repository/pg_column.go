@@ -6,20 +6,20 @@ import (
 )
 
 type PgColumnRepository struct {
-	db      *sql.DB
+	db      DBTX
 	boardID int64
 }
 
-func NewPgColumnRepository(db *sql.DB, boardID int64) *PgColumnRepository {
+func NewPgColumnRepository(db DBTX, boardID int64) *PgColumnRepository {
 	return &PgColumnRepository{db: db, boardID: boardID}
 }
 
 func (r *PgColumnRepository) GetByID(id int64) (*models.Column, error) {
 	column := &models.Column{}
 	err := r.db.QueryRow(
-		"SELECT id, board_id, name, position, is_done_column, created_at FROM columns WHERE id = $1",
+		"SELECT id, board_id, name, position, is_done_column, resolution, created_at FROM columns WHERE id = $1",
 		id,
-	).Scan(&column.ID, &column.BoardID, &column.Name, &column.Position, &column.IsDoneColumn, &column.CreatedAt)
+	).Scan(&column.ID, &column.BoardID, &column.Name, &column.Position, &column.IsDoneColumn, &column.Resolution, &column.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -28,7 +28,7 @@ func (r *PgColumnRepository) GetByID(id int64) (*models.Column, error) {
 
 func (r *PgColumnRepository) GetByBoardID(boardID int64) ([]models.Column, error) {
 	rows, err := r.db.Query(
-		"SELECT id, board_id, name, position, is_done_column, created_at FROM columns WHERE board_id = $1 ORDER BY position",
+		"SELECT id, board_id, name, position, is_done_column, resolution, created_at FROM columns WHERE board_id = $1 ORDER BY position",
 		boardID,
 	)
 	if err != nil {
@@ -39,7 +39,7 @@ func (r *PgColumnRepository) GetByBoardID(boardID int64) ([]models.Column, error
 	var columns []models.Column
 	for rows.Next() {
 		var column models.Column
-		if err := rows.Scan(&column.ID, &column.BoardID, &column.Name, &column.Position, &column.IsDoneColumn, &column.CreatedAt); err != nil {
+		if err := rows.Scan(&column.ID, &column.BoardID, &column.Name, &column.Position, &column.IsDoneColumn, &column.Resolution, &column.CreatedAt); err != nil {
 			return nil, err
 		}
 		columns = append(columns, column)
@@ -64,16 +64,16 @@ func (r *PgColumnRepository) Create(column *models.Column) error {
 	column.Position = newPos
 
 	err = r.db.QueryRow(
-		"INSERT INTO columns (board_id, name, position, is_done_column) VALUES ($1, $2, $3, $4) RETURNING id",
-		column.BoardID, column.Name, column.Position, column.IsDoneColumn,
+		"INSERT INTO columns (board_id, name, position, is_done_column, resolution) VALUES ($1, $2, $3, $4, $5) RETURNING id",
+		column.BoardID, column.Name, column.Position, column.IsDoneColumn, column.Resolution,
 	).Scan(&column.ID)
 	return err
 }
 
 func (r *PgColumnRepository) Update(column *models.Column) error {
 	_, err := r.db.Exec(
-		"UPDATE columns SET name = $1, is_done_column = $2 WHERE id = $3",
-		column.Name, column.IsDoneColumn, column.ID,
+		"UPDATE columns SET name = $1, is_done_column = $2, resolution = $3 WHERE id = $4",
+		column.Name, column.IsDoneColumn, column.Resolution, column.ID,
 	)
 	return err
 }
@@ -84,7 +84,7 @@ func (r *PgColumnRepository) Delete(id int64) error {
 }
 
 func (r *PgColumnRepository) Reorder(boardID int64, columnIDs []int64) error {
-	tx, err := r.db.Begin()
+	tx, err := beginTx(r.db)
 	if err != nil {
 		return err
 	}
@@ -1,15 +1,14 @@
 package repository
 
 import (
-	"database/sql"
 	"krizzy/internal/models"
 )
 
 type PgCommentRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewPgCommentRepository(db *sql.DB) *PgCommentRepository {
+func NewPgCommentRepository(db DBTX) *PgCommentRepository {
 	return &PgCommentRepository{db: db}
 }
 
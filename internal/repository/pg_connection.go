@@ -66,6 +66,12 @@ func (r *SQLitePgConnectionRepository) Create(conn *models.PgConnection) error {
 }
 
 func (r *SQLitePgConnectionRepository) Update(conn *models.PgConnection) error {
+	if conn.Port == 0 {
+		conn.Port = 5432
+	}
+	if conn.SSLMode == "" {
+		conn.SSLMode = "disable"
+	}
 	_, err := r.db.Exec(
 		"UPDATE pg_connections SET name = ?, host = ?, port = ?, username = ?, password = ?, ssl_mode = ? WHERE id = ?",
 		conn.Name, conn.Host, conn.Port, conn.User, conn.Password, conn.SSLMode, conn.ID,
@@ -1,16 +1,15 @@
 package repository
 
 import (
-	"database/sql"
 	"krizzy/internal/models"
 )
 
 type PgPersonRepository struct {
-	db      *sql.DB
+	db      DBTX
 	boardID int64
 }
 
-func NewPgPersonRepository(db *sql.DB, boardID int64) *PgPersonRepository {
+func NewPgPersonRepository(db DBTX, boardID int64) *PgPersonRepository {
 	return &PgPersonRepository{db: db, boardID: boardID}
 }
 
@@ -71,8 +70,21 @@ func (r *PgPersonRepository) Update(person *models.Person) error {
 }
 
 func (r *PgPersonRepository) Delete(id int64) error {
-	_, err := r.db.Exec("DELETE FROM people WHERE id = $1", id)
-	return err
+	tx, err := beginTx(r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Assignments go first, so no card is left pointing at the person
+	if _, err := tx.Exec("DELETE FROM card_assignees WHERE person_id = $1", id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM people WHERE id = $1", id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (r *PgPersonRepository) GetByCardID(cardID int64) ([]models.Person, error) {
@@ -102,7 +114,7 @@ func (r *PgPersonRepository) GetByCardID(cardID int64) ([]models.Person, error)
 }
 
 func (r *PgPersonRepository) SetCardAssignees(cardID int64, personIDs []int64) error {
-	tx, err := r.db.Begin()
+	tx, err := beginTx(r.db)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"krizzy/internal/models"
+	"time"
+)
+
+type PgRecurrenceRepository struct {
+	db DBTX
+}
+
+func NewPgRecurrenceRepository(db DBTX) *PgRecurrenceRepository {
+	return &PgRecurrenceRepository{db: db}
+}
+
+func (r *PgRecurrenceRepository) GetByID(id int64) (*models.Recurrence, error) {
+	return scanRecurrence(r.db.QueryRow(
+		"SELECT id, board_id, template_id, column_id, schedule, enabled, next_run_at, last_run_at, created_at FROM recurrences WHERE id = $1",
+		id,
+	))
+}
+
+func (r *PgRecurrenceRepository) GetByBoardID(boardID int64) ([]models.Recurrence, error) {
+	rows, err := r.db.Query(
+		"SELECT id, board_id, template_id, column_id, schedule, enabled, next_run_at, last_run_at, created_at FROM recurrences WHERE board_id = $1 ORDER BY id",
+		boardID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recurrences []models.Recurrence
+	for rows.Next() {
+		rec, err := scanRecurrence(rows)
+		if err != nil {
+			return nil, err
+		}
+		recurrences = append(recurrences, *rec)
+	}
+	return recurrences, rows.Err()
+}
+
+func (r *PgRecurrenceRepository) Create(rec *models.Recurrence) error {
+	return r.db.QueryRow(
+		"INSERT INTO recurrences (board_id, template_id, column_id, schedule, enabled, next_run_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id",
+		rec.BoardID, rec.TemplateID, rec.ColumnID, rec.Schedule, rec.Enabled, nullRunTime(rec.NextRunAt),
+	).Scan(&rec.ID)
+}
+
+func (r *PgRecurrenceRepository) Update(rec *models.Recurrence) error {
+	_, err := r.db.Exec(
+		"UPDATE recurrences SET template_id = $1, column_id = $2, schedule = $3, enabled = $4, next_run_at = $5 WHERE id = $6",
+		rec.TemplateID, rec.ColumnID, rec.Schedule, rec.Enabled, nullRunTime(rec.NextRunAt), rec.ID,
+	)
+	return err
+}
+
+func (r *PgRecurrenceRepository) Delete(id int64) error {
+	_, err := r.db.Exec("DELETE FROM recurrences WHERE id = $1", id)
+	return err
+}
+
+func (r *PgRecurrenceRepository) Advance(id int64, expected time.Time, next *time.Time, ranAt time.Time) (bool, error) {
+	result, err := r.db.Exec(
+		"UPDATE recurrences SET next_run_at = $1, last_run_at = $2 WHERE id = $3 AND enabled AND next_run_at = $4",
+		nullRunTime(next), runTime(ranAt), id, runTime(expected),
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n == 1, err
+}
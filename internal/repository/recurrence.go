@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"database/sql"
+	"krizzy/internal/models"
+	"time"
+)
+
+type SQLiteRecurrenceRepository struct {
+	db DBTX
+}
+
+func NewSQLiteRecurrenceRepository(db DBTX) *SQLiteRecurrenceRepository {
+	return &SQLiteRecurrenceRepository{db: db}
+}
+
+// runTime normalises scheduler timestamps to whole UTC seconds so Advance can
+// match a stored next_run_at exactly on both backends
+func runTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Second)
+}
+
+func nullRunTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: runTime(*t), Valid: true}
+}
+
+func scanRecurrence(row rowScanner) (*models.Recurrence, error) {
+	rec := &models.Recurrence{}
+	var nextRunAt, lastRunAt sql.NullTime
+	err := row.Scan(&rec.ID, &rec.BoardID, &rec.TemplateID, &rec.ColumnID, &rec.Schedule, &rec.Enabled, &nextRunAt, &lastRunAt, &rec.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if nextRunAt.Valid {
+		rec.NextRunAt = &nextRunAt.Time
+	}
+	if lastRunAt.Valid {
+		rec.LastRunAt = &lastRunAt.Time
+	}
+	return rec, nil
+}
+
+func (r *SQLiteRecurrenceRepository) GetByID(id int64) (*models.Recurrence, error) {
+	return scanRecurrence(r.db.QueryRow(
+		"SELECT id, board_id, template_id, column_id, schedule, enabled, next_run_at, last_run_at, created_at FROM recurrences WHERE id = ?",
+		id,
+	))
+}
+
+func (r *SQLiteRecurrenceRepository) GetByBoardID(boardID int64) ([]models.Recurrence, error) {
+	rows, err := r.db.Query(
+		"SELECT id, board_id, template_id, column_id, schedule, enabled, next_run_at, last_run_at, created_at FROM recurrences WHERE board_id = ? ORDER BY id",
+		boardID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recurrences []models.Recurrence
+	for rows.Next() {
+		rec, err := scanRecurrence(rows)
+		if err != nil {
+			return nil, err
+		}
+		recurrences = append(recurrences, *rec)
+	}
+	return recurrences, rows.Err()
+}
+
+func (r *SQLiteRecurrenceRepository) Create(rec *models.Recurrence) error {
+	result, err := r.db.Exec(
+		"INSERT INTO recurrences (board_id, template_id, column_id, schedule, enabled, next_run_at) VALUES (?, ?, ?, ?, ?, ?)",
+		rec.BoardID, rec.TemplateID, rec.ColumnID, rec.Schedule, rec.Enabled, nullRunTime(rec.NextRunAt),
+	)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	rec.ID = id
+	return nil
+}
+
+func (r *SQLiteRecurrenceRepository) Update(rec *models.Recurrence) error {
+	_, err := r.db.Exec(
+		"UPDATE recurrences SET template_id = ?, column_id = ?, schedule = ?, enabled = ?, next_run_at = ? WHERE id = ?",
+		rec.TemplateID, rec.ColumnID, rec.Schedule, rec.Enabled, nullRunTime(rec.NextRunAt), rec.ID,
+	)
+	return err
+}
+
+func (r *SQLiteRecurrenceRepository) Delete(id int64) error {
+	_, err := r.db.Exec("DELETE FROM recurrences WHERE id = ?", id)
+	return err
+}
+
+func (r *SQLiteRecurrenceRepository) Advance(id int64, expected time.Time, next *time.Time, ranAt time.Time) (bool, error) {
+	result, err := r.db.Exec(
+		"UPDATE recurrences SET next_run_at = ?, last_run_at = ? WHERE id = ? AND enabled AND next_run_at = ?",
+		nullRunTime(next), runTime(ranAt), id, runTime(expected),
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n == 1, err
+}
@@ -1,6 +1,9 @@
 package repository
 
-import "krizzy/internal/models"
+import (
+	"krizzy/internal/models"
+	"time"
+)
 
 type BoardRepository interface {
 	GetByID(id int64) (*models.Board, error)
@@ -26,8 +29,19 @@ type CardRepository interface {
 	Create(card *models.Card) error
 	Update(card *models.Card) error
 	Delete(id int64) error
-	Move(cardID int64, newColumnID int64, newPosition int) error
-	GetMaxPosition(columnID int64) (int, error)
+	Move(cardID int64, newColumnID int64, newSwimlaneID *int64, newPosition int) error
+	GetMaxPosition(columnID int64, swimlaneID *int64) (int, error)
+	GetArchivedByBoardID(boardID int64) ([]models.Card, error)
+	Archive(cardID int64, archivedAt time.Time) error
+	Restore(cardID int64) error
+}
+
+type SwimlaneRepository interface {
+	GetByID(id int64) (*models.Swimlane, error)
+	GetByBoardID(boardID int64) ([]models.Swimlane, error)
+	Create(lane *models.Swimlane) error
+	Update(lane *models.Swimlane) error
+	Delete(id int64) error
 }
 
 type PersonRepository interface {
@@ -47,6 +61,13 @@ type CommentRepository interface {
 	Delete(id int64) error
 }
 
+type CardLinkRepository interface {
+	GetByID(id int64) (*models.CardLink, error)
+	GetByCardID(cardID int64) ([]models.CardLink, error)
+	Create(link *models.CardLink) error
+	Delete(id int64) error
+}
+
 type ChecklistRepository interface {
 	GetByID(id int64) (*models.ChecklistItem, error)
 	GetByCardID(cardID int64) ([]models.ChecklistItem, error)
@@ -57,6 +78,35 @@ type ChecklistRepository interface {
 	GetMaxPosition(cardID int64) (int, error)
 }
 
+type CustomFieldRepository interface {
+	GetByID(id int64) (*models.CustomField, error)
+	GetByBoardID(boardID int64) ([]models.CustomField, error)
+	Create(field *models.CustomField) error
+	Update(field *models.CustomField) error
+	Delete(id int64) error
+	GetValuesByCardID(cardID int64) (map[int64]string, error)
+	SetValue(cardID, fieldID int64, value string) error
+}
+
+type CardTemplateRepository interface {
+	GetByID(id int64) (*models.CardTemplate, error)
+	GetByBoardID(boardID int64) ([]models.CardTemplate, error)
+	Create(tpl *models.CardTemplate) error
+	Delete(id int64) error
+}
+
+type RecurrenceRepository interface {
+	GetByID(id int64) (*models.Recurrence, error)
+	GetByBoardID(boardID int64) ([]models.Recurrence, error)
+	Create(rec *models.Recurrence) error
+	Update(rec *models.Recurrence) error
+	Delete(id int64) error
+	// Advance moves a recurrence on to its next occurrence, but only if its
+	// next run is still expected. It reports false when another run got there
+	// first.
+	Advance(id int64, expected time.Time, next *time.Time, ranAt time.Time) (bool, error)
+}
+
 type PgConnectionRepository interface {
 	GetByID(id int64) (*models.PgConnection, error)
 	GetAll() ([]models.PgConnection, error)
@@ -64,3 +114,11 @@ type PgConnectionRepository interface {
 	Update(conn *models.PgConnection) error
 	Delete(id int64) error
 }
+
+// IntegrityRepository finds data a database's constraints let through, such
+// as position gaps and rows pointing at deleted parents
+type IntegrityRepository interface {
+	// Check returns the problems found. With repair set it also fixes them,
+	// all in one transaction.
+	Check(repair bool) ([]models.IntegrityIssue, error)
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"database/sql"
+	"krizzy/internal/models"
+)
+
+type SQLiteSwimlaneRepository struct {
+	db DBTX
+}
+
+func NewSQLiteSwimlaneRepository(db DBTX) *SQLiteSwimlaneRepository {
+	return &SQLiteSwimlaneRepository{db: db}
+}
+
+func (r *SQLiteSwimlaneRepository) GetByID(id int64) (*models.Swimlane, error) {
+	lane := &models.Swimlane{}
+	err := r.db.QueryRow(
+		"SELECT id, board_id, name, position, collapsed, created_at FROM swimlanes WHERE id = ?",
+		id,
+	).Scan(&lane.ID, &lane.BoardID, &lane.Name, &lane.Position, &lane.Collapsed, &lane.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return lane, nil
+}
+
+func (r *SQLiteSwimlaneRepository) GetByBoardID(boardID int64) ([]models.Swimlane, error) {
+	rows, err := r.db.Query(
+		"SELECT id, board_id, name, position, collapsed, created_at FROM swimlanes WHERE board_id = ? ORDER BY position",
+		boardID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lanes []models.Swimlane
+	for rows.Next() {
+		var lane models.Swimlane
+		if err := rows.Scan(&lane.ID, &lane.BoardID, &lane.Name, &lane.Position, &lane.Collapsed, &lane.CreatedAt); err != nil {
+			return nil, err
+		}
+		lanes = append(lanes, lane)
+	}
+	return lanes, rows.Err()
+}
+
+func (r *SQLiteSwimlaneRepository) Create(lane *models.Swimlane) error {
+	var maxPos sql.NullInt64
+	err := r.db.QueryRow(
+		"SELECT MAX(position) FROM swimlanes WHERE board_id = ?",
+		lane.BoardID,
+	).Scan(&maxPos)
+	if err != nil {
+		return err
+	}
+
+	lane.Position = 0
+	if maxPos.Valid {
+		lane.Position = int(maxPos.Int64) + 1
+	}
+
+	result, err := r.db.Exec(
+		"INSERT INTO swimlanes (board_id, name, position, collapsed) VALUES (?, ?, ?, ?)",
+		lane.BoardID, lane.Name, lane.Position, lane.Collapsed,
+	)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	lane.ID = id
+	return nil
+}
+
+func (r *SQLiteSwimlaneRepository) Update(lane *models.Swimlane) error {
+	_, err := r.db.Exec(
+		"UPDATE swimlanes SET name = ?, collapsed = ? WHERE id = ?",
+		lane.Name, lane.Collapsed, lane.ID,
+	)
+	return err
+}
+
+// Delete removes a swimlane and moves its cards to the end of the unlaned
+// row in their columns
+func (r *SQLiteSwimlaneRepository) Delete(id int64) error {
+	tx, err := beginTx(r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query("SELECT DISTINCT column_id FROM cards WHERE swimlane_id = ?", id)
+	if err != nil {
+		return err
+	}
+	var columnIDs []int64
+	for rows.Next() {
+		var columnID int64
+		if err := rows.Scan(&columnID); err != nil {
+			rows.Close()
+			return err
+		}
+		columnIDs = append(columnIDs, columnID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, columnID := range columnIDs {
+		var maxPos sql.NullInt64
+		err := tx.QueryRow(
+			"SELECT MAX(position) FROM cards WHERE column_id = ? AND swimlane_id IS NULL",
+			columnID,
+		).Scan(&maxPos)
+		if err != nil {
+			return err
+		}
+		offset := int64(0)
+		if maxPos.Valid {
+			offset = maxPos.Int64 + 1
+		}
+		_, err = tx.Exec(
+			"UPDATE cards SET swimlane_id = NULL, position = position + ? WHERE column_id = ? AND swimlane_id = ? AND archived_at IS NULL",
+			offset, columnID, id,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Archived cards stay outside the ordering and just lose their lane
+	if _, err := tx.Exec("UPDATE cards SET swimlane_id = NULL WHERE swimlane_id = ?", id); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM swimlanes WHERE id = ?", id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// DBTX is what repositories need from *sql.DB and *sql.Tx, so the same
+// repository can run on its own or inside a caller's transaction
+type DBTX interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// txn is a transaction a repository method opened for itself
+type txn interface {
+	DBTX
+	Commit() error
+	Rollback() error
+}
+
+// beginTx starts a transaction on db. When db is already a transaction the
+// work joins it, and committing or rolling back is left to whoever began it.
+func beginTx(db DBTX) (txn, error) {
+	switch d := db.(type) {
+	case *sql.Tx:
+		return joinedTx{d}, nil
+	case joinedTx:
+		return d, nil
+	case txBeginner:
+		tx, err := d.Begin()
+		if err != nil {
+			return nil, err
+		}
+		return tx, nil
+	}
+	return nil, errors.New("repository: database does not support transactions")
+}
+
+// txBeginner is a handle that starts transactions: *sql.DB, or a SQLite
+// database's read/write pools
+type txBeginner interface {
+	Begin() (*sql.Tx, error)
+}
+
+type joinedTx struct {
+	*sql.Tx
+}
+
+func (joinedTx) Commit() error   { return nil }
+func (joinedTx) Rollback() error { return nil }
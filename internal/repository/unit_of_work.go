@@ -0,0 +1,82 @@
+package repository
+
+// Repositories is the set of a board's repositories on one database handle
+type Repositories struct {
+	ColumnRepo     ColumnRepository
+	CardRepo       CardRepository
+	PersonRepo     PersonRepository
+	CommentRepo    CommentRepository
+	LinkRepo       CardLinkRepository
+	ChecklistRepo  ChecklistRepository
+	SwimlaneRepo   SwimlaneRepository
+	FieldRepo      CustomFieldRepository
+	TemplateRepo   CardTemplateRepository
+	RecurrenceRepo RecurrenceRepository
+
+	// UnitOfWork runs work in a transaction on the same handle. When the
+	// handle is a transaction already, the work joins it.
+	UnitOfWork UnitOfWork
+}
+
+// UnitOfWork runs a function against repositories that share one
+// transaction. The transaction commits when fn returns nil and rolls back
+// otherwise.
+type UnitOfWork interface {
+	Do(fn func(tx Repositories) error) error
+}
+
+type sqlUnitOfWork struct {
+	db    DBTX
+	repos func(db DBTX) Repositories
+}
+
+func (u sqlUnitOfWork) Do(fn func(tx Repositories) error) error {
+	tx, err := beginTx(u.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(u.repos(tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// NewSQLiteRepositories builds a board's repositories on a SQLite database
+// or a transaction open on it
+func NewSQLiteRepositories(db DBTX) Repositories {
+	return Repositories{
+		ColumnRepo:     NewSQLiteColumnRepository(db),
+		CardRepo:       NewSQLiteCardRepository(db),
+		PersonRepo:     NewSQLitePersonRepository(db),
+		CommentRepo:    NewSQLiteCommentRepository(db),
+		LinkRepo:       NewSQLiteCardLinkRepository(db),
+		ChecklistRepo:  NewSQLiteChecklistRepository(db),
+		SwimlaneRepo:   NewSQLiteSwimlaneRepository(db),
+		FieldRepo:      NewSQLiteCustomFieldRepository(db),
+		TemplateRepo:   NewSQLiteCardTemplateRepository(db),
+		RecurrenceRepo: NewSQLiteRecurrenceRepository(db),
+		UnitOfWork:     sqlUnitOfWork{db: db, repos: NewSQLiteRepositories},
+	}
+}
+
+// NewPgRepositories builds a board's repositories on its Postgres database
+// or a transaction open on it
+func NewPgRepositories(db DBTX, boardID int64) Repositories {
+	return Repositories{
+		ColumnRepo:     NewPgColumnRepository(db, boardID),
+		CardRepo:       NewPgCardRepository(db),
+		PersonRepo:     NewPgPersonRepository(db, boardID),
+		CommentRepo:    NewPgCommentRepository(db),
+		LinkRepo:       NewPgCardLinkRepository(db),
+		ChecklistRepo:  NewPgChecklistRepository(db),
+		SwimlaneRepo:   NewPgSwimlaneRepository(db, boardID),
+		FieldRepo:      NewPgCustomFieldRepository(db),
+		TemplateRepo:   NewPgCardTemplateRepository(db),
+		RecurrenceRepo: NewPgRecurrenceRepository(db),
+		UnitOfWork: sqlUnitOfWork{db: db, repos: func(db DBTX) Repositories {
+			return NewPgRepositories(db, boardID)
+		}},
+	}
+}
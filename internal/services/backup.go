@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"krizzy/internal/database"
+)
+
+const backupFilePrefix = "krizzy-"
+
+// backupTimeFormat has microseconds, so backups taken in the same second get
+// names of their own
+const backupTimeFormat = "20060102-150405.000000"
+
+// BackupService takes online snapshots of the local SQLite database and keeps
+// the newest few of them.
+type BackupService struct {
+	db        *database.SQLiteDB
+	dir       string
+	interval  time.Duration
+	retention int
+	mu        sync.Mutex
+	stop      chan struct{}
+	stopOnce  sync.Once
+}
+
+func NewBackupService(db *database.SQLiteDB, dir string, interval time.Duration, retention int) *BackupService {
+	return &BackupService{
+		db:        db,
+		dir:       dir,
+		interval:  interval,
+		retention: retention,
+		stop:      make(chan struct{}),
+	}
+}
+
+// Start runs backups on the configured interval until Close is called. An
+// interval of zero leaves scheduling off; RunBackup still works.
+func (s *BackupService) Start() {
+	if s.interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				if path, err := s.RunBackup(context.Background()); err != nil {
+					log.Printf("scheduled backup failed: %v", err)
+				} else {
+					log.Printf("scheduled backup written to %s", path)
+				}
+			}
+		}
+	}()
+}
+
+func (s *BackupService) Close() {
+	s.stopOnce.Do(func() { close(s.stop) })
+}
+
+// RunBackup writes a new snapshot and prunes old ones. Concurrent calls are
+// serialised so a manual backup never races the scheduler.
+func (s *BackupService) RunBackup(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	name := fmt.Sprintf("%s%s.db", backupFilePrefix, time.Now().UTC().Format(backupTimeFormat))
+	path := filepath.Join(s.dir, name)
+	if err := s.db.Backup(ctx, path); err != nil {
+		return "", err
+	}
+
+	if err := s.prune(); err != nil {
+		log.Printf("failed to prune old backups: %v", err)
+	}
+	return path, nil
+}
+
+// ListBackups returns backup file names, newest first
+func (s *BackupService) ListBackups() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, backupFilePrefix) || !strings.HasSuffix(name, ".db") {
+			continue
+		}
+		names = append(names, name)
+	}
+	// Timestamps in the name sort lexically
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+	return names, nil
+}
+
+func (s *BackupService) prune() error {
+	if s.retention <= 0 {
+		return nil
+	}
+
+	names, err := s.ListBackups()
+	if err != nil {
+		return err
+	}
+	for _, name := range names[min(len(names), s.retention):] {
+		if err := os.Remove(filepath.Join(s.dir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBackupsInTheSameSecondKeepTheirOwnFiles(t *testing.T) {
+	_, db := newTestBoardManager(t)
+	backups := NewBackupService(db, filepath.Join(t.TempDir(), "backups"), 0, 0)
+
+	first, err := backups.RunBackup(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := backups.RunBackup(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == second {
+		t.Fatalf("both backups were written to %s", first)
+	}
+
+	names, err := backups.ListBackups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[0] != filepath.Base(second) {
+		t.Errorf("backups = %v, want %s first of two", names, filepath.Base(second))
+	}
+
+	if err := db.Backup(context.Background(), first); !errors.Is(err, os.ErrExist) {
+		t.Errorf("backup over an existing file returned %v, want os.ErrExist", err)
+	}
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+
+	"krizzy/internal/models"
+)
+
+// CloneBoard copies a board's columns, swimlanes, people, custom fields,
+// cards, checklists and card templates into a new board, which may use a
+// different storage backend. Archived cards are copied archived, with their
+// archive time. Comments are copied when includeComments is set. Recurring
+// cards are left out so a copy never creates cards on its own.
+// The copy is written like an import, in one transaction on the new board's
+// storage, so a failure part way leaves no board behind.
+func (bm *BoardManager) CloneBoard(srcBoardID int64, name, dbType string, pgConnectionID *int64, pgDatabaseName, pgSchemaName string, includeComments bool) (*models.Board, error) {
+	src, err := bm.GetServiceForBoard(srcBoardID)
+	if err != nil {
+		return nil, err
+	}
+	source, err := src.GetBoardWithData(srcBoardID)
+	if err != nil {
+		return nil, err
+	}
+	archived, err := src.CardRepo.GetArchivedByBoardID(srcBoardID)
+	if err != nil {
+		return nil, err
+	}
+	archivedByColumn := make(map[int64][]models.Card)
+	for _, card := range archived {
+		detailed, err := src.GetCardWithDetails(card.ID)
+		if err != nil {
+			return nil, err
+		}
+		archivedByColumn[card.ColumnID] = append(archivedByColumn[card.ColumnID], *detailed)
+	}
+
+	return bm.CreateBoardInTx(context.Background(), name, dbType, pgConnectionID, pgDatabaseName, pgSchemaName, func(board *models.Board, dst *KanbanService) error {
+		return copyBoardContents(src, dst, source, archivedByColumn, board, includeComments)
+	})
+}
+
+func copyBoardContents(src, dst *KanbanService, source *models.Board, archivedByColumn map[int64][]models.Card, board *models.Board, includeComments bool) error {
+	if source.LaneGrouping != "" {
+		board.LaneGrouping = source.LaneGrouping
+		if err := dst.BoardRepo.Update(board); err != nil {
+			return err
+		}
+	}
+
+	people, err := src.PersonRepo.GetByBoardID(source.ID)
+	if err != nil {
+		return err
+	}
+	personIDs := make(map[int64]int64, len(people))
+	for _, person := range people {
+		copied := &models.Person{BoardID: board.ID, Name: person.Name, Color: person.Color}
+		if err := dst.PersonRepo.Create(copied); err != nil {
+			return err
+		}
+		personIDs[person.ID] = copied.ID
+	}
+
+	fieldIDs := make(map[int64]int64, len(source.Fields))
+	for _, field := range source.Fields {
+		copied := &models.CustomField{BoardID: board.ID, Name: field.Name, Type: field.Type, Options: field.Options}
+		if err := dst.FieldRepo.Create(copied); err != nil {
+			return err
+		}
+		fieldIDs[field.ID] = copied.ID
+	}
+
+	swimlaneIDs := make(map[int64]int64, len(source.Swimlanes))
+	for _, lane := range source.Swimlanes {
+		copied := &models.Swimlane{BoardID: board.ID, Name: lane.Name, Collapsed: lane.Collapsed}
+		if err := dst.SwimlaneRepo.Create(copied); err != nil {
+			return err
+		}
+		swimlaneIDs[lane.ID] = copied.ID
+	}
+
+	for _, column := range source.Columns {
+		copiedColumn := &models.Column{
+			BoardID:      board.ID,
+			Name:         column.Name,
+			IsDoneColumn: column.IsDoneColumn,
+			Resolution:   column.Resolution,
+		}
+		if err := dst.ColumnRepo.Create(copiedColumn); err != nil {
+			return err
+		}
+
+		// Cards come back in position order, so creating them in turn keeps
+		// each cell's order
+		for _, card := range column.Cards {
+			if err := copyBoardCard(src, dst, &card, copiedColumn.ID, swimlaneIDs, personIDs, fieldIDs, includeComments); err != nil {
+				return err
+			}
+		}
+		for _, card := range archivedByColumn[column.ID] {
+			if err := copyBoardCard(src, dst, &card, copiedColumn.ID, swimlaneIDs, personIDs, fieldIDs, includeComments); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, tpl := range source.Templates {
+		copied := &models.CardTemplate{
+			BoardID:     board.ID,
+			Name:        tpl.Name,
+			Title:       tpl.Title,
+			Description: tpl.Description,
+			Checklist:   tpl.Checklist,
+			FieldValues: make(map[int64]string, len(tpl.FieldValues)),
+		}
+		for _, id := range tpl.AssigneeIDs {
+			if newID, ok := personIDs[id]; ok {
+				copied.AssigneeIDs = append(copied.AssigneeIDs, newID)
+			}
+		}
+		for id, value := range tpl.FieldValues {
+			if newID, ok := fieldIDs[id]; ok {
+				copied.FieldValues[newID] = value
+			}
+		}
+		if err := dst.TemplateRepo.Create(copied); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// copyBoardCard copies one card of a board being cloned, translating its
+// swimlane, assignees and field values to the new board's IDs
+func copyBoardCard(src, dst *KanbanService, card *models.Card, columnID int64, swimlaneIDs, personIDs, fieldIDs map[int64]int64, includeComments bool) error {
+	copied := &models.Card{
+		ColumnID:    columnID,
+		Title:       card.Title,
+		Description: card.Description,
+	}
+	if card.SwimlaneID != nil {
+		if id, ok := swimlaneIDs[*card.SwimlaneID]; ok {
+			copied.SwimlaneID = &id
+		}
+	}
+	if err := dst.CardRepo.Create(copied); err != nil {
+		return err
+	}
+
+	if card.CompletedAt != nil {
+		copied.CompletedAt = card.CompletedAt
+		copied.Resolution = card.Resolution
+		if err := dst.CardRepo.Update(copied); err != nil {
+			return err
+		}
+	}
+
+	for _, item := range card.Checklist {
+		if err := dst.ChecklistRepo.Create(&models.ChecklistItem{
+			CardID:      copied.ID,
+			Checklist:   item.Checklist,
+			Content:     item.Content,
+			IsCompleted: item.IsCompleted,
+		}); err != nil {
+			return err
+		}
+	}
+
+	links, err := src.LinkRepo.GetByCardID(card.ID)
+	if err != nil {
+		return err
+	}
+	for _, link := range links {
+		if err := dst.LinkRepo.Create(&models.CardLink{
+			CardID: copied.ID,
+			Title:  link.Title,
+			URL:    link.URL,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if includeComments {
+		comments, err := src.CommentRepo.GetByCardID(card.ID)
+		if err != nil {
+			return err
+		}
+		for _, comment := range comments {
+			if err := dst.CommentRepo.Create(&models.Comment{
+				CardID:    copied.ID,
+				Content:   comment.Content,
+				CreatedAt: comment.CreatedAt,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	var assigneeIDs []int64
+	for _, person := range card.Assignees {
+		if id, ok := personIDs[person.ID]; ok {
+			assigneeIDs = append(assigneeIDs, id)
+		}
+	}
+	if len(assigneeIDs) > 0 {
+		if err := dst.PersonRepo.SetCardAssignees(copied.ID, assigneeIDs); err != nil {
+			return err
+		}
+	}
+
+	for id, value := range card.FieldValues {
+		if newID, ok := fieldIDs[id]; ok {
+			if err := dst.FieldRepo.SetValue(copied.ID, newID, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	if card.ArchivedAt != nil {
+		return dst.CardRepo.Archive(copied.ID, *card.ArchivedAt)
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"krizzy/internal/models"
+)
+
+// boardSnapshot describes a board by names and values rather than IDs, so a
+// board and its copy compare equal
+func boardSnapshot(t *testing.T, svc *KanbanService, boardID int64) []string {
+	t.Helper()
+
+	board, err := svc.GetBoardWithData(boardID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	people, err := svc.PersonRepo.GetByBoardID(boardID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archived, err := svc.CardRepo.GetArchivedByBoardID(boardID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fieldNames := make(map[int64]string)
+	personNames := make(map[int64]string)
+	laneNames := make(map[int64]string)
+	columnNames := make(map[int64]string)
+	var lines []string
+	add := func(format string, args ...any) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	}
+	stamp := func(at *time.Time) string {
+		if at == nil {
+			return "-"
+		}
+		return at.UTC().Format(time.RFC3339)
+	}
+
+	for _, person := range people {
+		personNames[person.ID] = person.Name
+		add("person %s %s", person.Name, person.Color)
+	}
+	for _, field := range board.Fields {
+		fieldNames[field.ID] = field.Name
+		add("field %s %s %v", field.Name, field.Type, field.Options)
+	}
+	for _, lane := range board.Swimlanes {
+		laneNames[lane.ID] = lane.Name
+		add("lane %s collapsed=%v", lane.Name, lane.Collapsed)
+	}
+
+	describe := func(prefix string, card *models.Card) {
+		detailed, err := svc.GetCardWithDetails(card.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lane := "-"
+		if card.SwimlaneID != nil {
+			lane = laneNames[*card.SwimlaneID]
+		}
+		add("%s %q lane=%s pos=%d desc=%q completed=%s resolution=%q archived=%s",
+			prefix, card.Title, lane, card.Position, card.Description, stamp(card.CompletedAt), card.Resolution, stamp(card.ArchivedAt))
+		for _, person := range detailed.Assignees {
+			add("%s %q assignee %s", prefix, card.Title, person.Name)
+		}
+		for _, item := range detailed.Checklist {
+			add("%s %q item %s/%s done=%v", prefix, card.Title, item.Checklist, item.Content, item.IsCompleted)
+		}
+		for _, link := range detailed.Links {
+			add("%s %q link %s %s", prefix, card.Title, link.Title, link.URL)
+		}
+		for _, comment := range detailed.Comments {
+			add("%s %q comment %q at %s", prefix, card.Title, comment.Content, stamp(&comment.CreatedAt))
+		}
+		for id, value := range detailed.FieldValues {
+			add("%s %q field %s=%s", prefix, card.Title, fieldNames[id], value)
+		}
+	}
+
+	for _, column := range board.Columns {
+		columnNames[column.ID] = column.Name
+		add("column %s done=%v resolution=%q", column.Name, column.IsDoneColumn, column.Resolution)
+		for _, card := range column.Cards {
+			describe("card in "+column.Name, &card)
+		}
+	}
+	for _, card := range archived {
+		describe("archived in "+columnNames[card.ColumnID], &card)
+	}
+
+	for _, tpl := range board.Templates {
+		add("template %s %q %q %v", tpl.Name, tpl.Title, tpl.Description, tpl.Checklist)
+		for _, id := range tpl.AssigneeIDs {
+			add("template %s assignee %s", tpl.Name, personNames[id])
+		}
+		for id, value := range tpl.FieldValues {
+			add("template %s field %s=%s", tpl.Name, fieldNames[id], value)
+		}
+	}
+
+	// Field values and assignees come from maps or ID order
+	slices.Sort(lines)
+	return lines
+}
+
+func TestCloneBoardCopiesEverything(t *testing.T) {
+	bm, _ := newTestBoardManager(t)
+	source, err := bm.CreateBoard("Launch", "local", nil, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc, err := bm.GetServiceForBoard(source.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	columns, err := svc.ColumnRepo.GetByBoardID(source.ID)
+	must(err)
+	todo, done := columns[0], columns[len(columns)-1]
+	done.Resolution = "Shipped"
+	must(svc.UpdateColumn(&done))
+
+	lane := &models.Swimlane{BoardID: source.ID, Name: "Backend", Collapsed: true}
+	must(svc.SwimlaneRepo.Create(lane))
+	ann := &models.Person{BoardID: source.ID, Name: "Ann", Color: "#ff0000"}
+	must(svc.PersonRepo.Create(ann))
+	bob := &models.Person{BoardID: source.ID, Name: "Bob"}
+	must(svc.PersonRepo.Create(bob))
+	labels := &models.CustomField{BoardID: source.ID, Name: "Labels", Type: models.FieldTypeMultiSelect, Options: []string{"Bug", "Feature"}}
+	must(svc.FieldRepo.Create(labels))
+	points := &models.CustomField{BoardID: source.ID, Name: "Points", Type: models.FieldTypeNumber}
+	must(svc.FieldRepo.Create(points))
+
+	commentedAt := time.Date(2026, 2, 3, 10, 0, 0, 0, time.UTC)
+	newCard := func(title string, swimlaneID *int64) *models.Card {
+		t.Helper()
+		card := &models.Card{ColumnID: todo.ID, SwimlaneID: swimlaneID, Title: title, Description: title + " details"}
+		must(svc.CardRepo.Create(card))
+		must(svc.ChecklistRepo.Create(&models.ChecklistItem{CardID: card.ID, Checklist: "Steps", Content: "First", IsCompleted: true}))
+		must(svc.ChecklistRepo.Create(&models.ChecklistItem{CardID: card.ID, Content: "Second"}))
+		must(svc.LinkRepo.Create(&models.CardLink{CardID: card.ID, Title: "Spec", URL: "https://example.com/" + strings.ToLower(title)}))
+		must(svc.CommentRepo.Create(&models.Comment{CardID: card.ID, Content: "On " + title, CreatedAt: commentedAt}))
+		must(svc.PersonRepo.SetCardAssignees(card.ID, []int64{ann.ID, bob.ID}))
+		must(svc.FieldRepo.SetValue(card.ID, labels.ID, `["Bug","Feature"]`))
+		must(svc.FieldRepo.SetValue(card.ID, points.ID, "3"))
+		return card
+	}
+	newCard("Plan", nil)
+	newCard("API", &lane.ID)
+	shipped := newCard("Release", nil)
+	must(svc.MoveCard(shipped.ID, done.ID, nil, 0))
+	shelved := newCard("Shelved", &lane.ID)
+	must(svc.CardRepo.Archive(shelved.ID, time.Date(2026, 2, 4, 8, 0, 0, 0, time.UTC)))
+
+	must(svc.TemplateRepo.Create(&models.CardTemplate{
+		BoardID:     source.ID,
+		Name:        "Bug report",
+		Title:       "Bug: {{date}}",
+		Description: "Steps to reproduce",
+		Checklist:   []string{"Reproduce", "Fix"},
+		AssigneeIDs: []int64{bob.ID},
+		FieldValues: map[int64]string{labels.ID: `["Bug"]`},
+	}))
+
+	want := boardSnapshot(t, svc, source.ID)
+
+	// The local database and a board file are different backends
+	for _, dbType := range []string{"local", "file"} {
+		t.Run(dbType, func(t *testing.T) {
+			clone, err := bm.CloneBoard(source.ID, "Copy", dbType, nil, "", "", true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cloneSvc, err := bm.GetServiceForBoard(clone.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := boardSnapshot(t, cloneSvc, clone.ID); !reflect.DeepEqual(got, want) {
+				t.Errorf("copy differs:\ngot:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+			}
+		})
+	}
+
+	// Without comments the rest is the same
+	clone, err := bm.CloneBoard(source.ID, "No comments", "local", nil, "", "", false)
+	must(err)
+	cloneSvc, err := bm.GetServiceForBoard(clone.ID)
+	must(err)
+	var withoutComments []string
+	for _, line := range want {
+		if !strings.Contains(line, " comment ") {
+			withoutComments = append(withoutComments, line)
+		}
+	}
+	if got := boardSnapshot(t, cloneSvc, clone.ID); !reflect.DeepEqual(got, withoutComments) {
+		t.Errorf("copy without comments differs:\ngot:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(withoutComments, "\n"))
+	}
+}
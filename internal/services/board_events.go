@@ -5,6 +5,10 @@ import (
 	"time"
 )
 
+// BoardEvent is a change to a board as sent to its SSE subscribers. Card and
+// column events may carry the element as rendered on the board in HTML, with
+// where it sits, so clients can apply the change without fetching anything.
+// Without HTML clients fetch what changed.
 type BoardEvent struct {
 	Type         string `json:"type"`
 	BoardID      int64  `json:"board_id"`
@@ -12,22 +16,46 @@ type BoardEvent struct {
 	ColumnID     int64  `json:"column_id,omitempty"`
 	FromColumnID int64  `json:"from_column_id,omitempty"`
 	ToColumnID   int64  `json:"to_column_id,omitempty"`
-	ClientID     string `json:"client_id,omitempty"`
-	OccurredAt   int64  `json:"occurred_at"`
+	// SwimlaneID is the card's lane, 0 for the unlaned row
+	SwimlaneID int64   `json:"swimlane_id,omitempty"`
+	Position   int     `json:"position,omitempty"`
+	HTML       string  `json:"html,omitempty"`
+	ColumnIDs  []int64 `json:"column_ids,omitempty"`
+	ClientID   string  `json:"client_id,omitempty"`
+	OccurredAt int64   `json:"occurred_at"`
+	// Version counts the events delivered for the board. A subscriber that
+	// sees it skip a number missed an event and reloads the board.
+	Version int64 `json:"version"`
 }
 
-type BoardEventHub struct {
-	mu          sync.RWMutex
+// BoardEventHub fans board events out to the SSE subscribers of a board.
+// MemoryEventHub serves a single instance; PgEventHub also shares events
+// with other instances through Postgres. Subscribe returns the board's
+// current version, which the next event delivered to the channel follows.
+type BoardEventHub interface {
+	Subscribe(boardID int64) (chan BoardEvent, int64)
+	Unsubscribe(boardID int64, ch chan BoardEvent)
+	Publish(event BoardEvent)
+	Close() error
+}
+
+// MemoryEventHub delivers events to subscribers in this process only.
+// Versions are kept per process, so they only mean something to the
+// subscribers of the instance that numbered them.
+type MemoryEventHub struct {
+	mu          sync.Mutex
 	subscribers map[int64]map[chan BoardEvent]struct{}
+	versions    map[int64]int64
 }
 
-func NewBoardEventHub() *BoardEventHub {
-	return &BoardEventHub{
+func NewMemoryEventHub() *MemoryEventHub {
+	return &MemoryEventHub{
 		subscribers: make(map[int64]map[chan BoardEvent]struct{}),
+		versions:    make(map[int64]int64),
 	}
 }
 
-func (h *BoardEventHub) Subscribe(boardID int64) chan BoardEvent {
+func (h *MemoryEventHub) Subscribe(boardID int64) (chan BoardEvent, int64) {
 	ch := make(chan BoardEvent, 16)
 
 	h.mu.Lock()
@@ -38,10 +66,10 @@ func (h *BoardEventHub) Subscribe(boardID int64) chan BoardEvent {
 	}
 	h.subscribers[boardID][ch] = struct{}{}
 
-	return ch
+	return ch, h.versions[boardID]
 }
 
-func (h *BoardEventHub) Unsubscribe(boardID int64, ch chan BoardEvent) {
+func (h *MemoryEventHub) Unsubscribe(boardID int64, ch chan BoardEvent) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
@@ -61,12 +89,20 @@ func (h *BoardEventHub) Unsubscribe(boardID int64, ch chan BoardEvent) {
 	}
 }
 
-func (h *BoardEventHub) Publish(event BoardEvent) {
+func (h *MemoryEventHub) Publish(event BoardEvent) {
 	event.OccurredAt = time.Now().UnixMilli()
+	h.deliver(event)
+}
 
-	h.mu.RLock()
-	defer h.mu.RUnlock()
+// deliver numbers an event and hands it to the board's subscribers. Slow
+// subscribers miss events rather than block the publisher; the version
+// still moves on, so they notice.
+func (h *MemoryEventHub) deliver(event BoardEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 
+	h.versions[event.BoardID]++
+	event.Version = h.versions[event.BoardID]
 	for ch := range h.subscribers[event.BoardID] {
 		select {
 		case ch <- event:
@@ -74,3 +110,19 @@ func (h *BoardEventHub) Publish(event BoardEvent) {
 		}
 	}
 }
+
+// skipVersions moves the version of every watched board on without an
+// event, for when events may have been lost on the way to this hub.
+// Subscribers see the gap with their next event and reload their board.
+func (h *MemoryEventHub) skipVersions() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for boardID := range h.subscribers {
+		h.versions[boardID]++
+	}
+}
+
+func (h *MemoryEventHub) Close() error {
+	return nil
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// boardEventsChannel is the NOTIFY channel instances share board events on
+const boardEventsChannel = "krizzy_board_events"
+
+const (
+	listenerMinReconnect = 10 * time.Second
+	listenerMaxReconnect = time.Minute
+	// notifyTimeout bounds how long a publishing request waits on Postgres
+	notifyTimeout = 2 * time.Second
+	// maxNotifyPayload is the most NOTIFY accepts in one payload
+	maxNotifyPayload = 8000
+)
+
+// pgBoardEvent is a board event as sent to other instances. Origin names
+// the instance that published it, so that instance can skip its own copy.
+type pgBoardEvent struct {
+	Origin string     `json:"origin"`
+	Event  BoardEvent `json:"event"`
+}
+
+// PgEventHub delivers events to this instance's subscribers right away and
+// shares them with every other instance listening on the same Postgres
+// server. Events received from other instances are delivered as they were
+// published.
+type PgEventHub struct {
+	local     *MemoryEventHub
+	db        *sql.DB
+	listener  *pq.Listener
+	origin    string
+	closeOnce sync.Once
+}
+
+// NewPgEventHub connects to Postgres with connString and starts listening
+// for events from other instances
+func NewPgEventHub(connString string) (*PgEventHub, error) {
+	origin, err := newInstanceID()
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("postgres", connString)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event hub connection: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping event hub database: %w", err)
+	}
+
+	listener := pq.NewListener(connString, listenerMinReconnect, listenerMaxReconnect, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("event hub: listener: %v", err)
+		}
+	})
+	if err := listener.Listen(boardEventsChannel); err != nil {
+		listener.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to listen for board events: %w", err)
+	}
+
+	h := &PgEventHub{
+		local:    NewMemoryEventHub(),
+		db:       db,
+		listener: listener,
+		origin:   origin,
+	}
+	go h.receive()
+	return h, nil
+}
+
+func (h *PgEventHub) Subscribe(boardID int64) (chan BoardEvent, int64) {
+	return h.local.Subscribe(boardID)
+}
+
+func (h *PgEventHub) Unsubscribe(boardID int64, ch chan BoardEvent) {
+	h.local.Unsubscribe(boardID, ch)
+}
+
+// Publish delivers an event locally, then notifies other instances. A failed
+// notification is logged; local subscribers have the event either way. An
+// event too large for NOTIFY goes out without its HTML, and clients of the
+// other instances fetch what changed instead.
+func (h *PgEventHub) Publish(event BoardEvent) {
+	event.OccurredAt = time.Now().UnixMilli()
+	h.local.deliver(event)
+
+	payload, err := json.Marshal(pgBoardEvent{Origin: h.origin, Event: event})
+	if err == nil && len(payload) > maxNotifyPayload {
+		event.HTML = ""
+		payload, err = json.Marshal(pgBoardEvent{Origin: h.origin, Event: event})
+	}
+	if err != nil {
+		log.Printf("event hub: failed to encode event: %v", err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+	if _, err := h.db.ExecContext(ctx, "SELECT pg_notify($1, $2)", boardEventsChannel, string(payload)); err != nil {
+		log.Printf("event hub: failed to notify other instances: %v", err)
+	}
+}
+
+// receive delivers events from other instances until the listener closes
+func (h *PgEventHub) receive() {
+	for notification := range h.listener.Notify {
+		// A nil notification follows a reconnect; events sent while the
+		// connection was down are lost, so subscribers are told to reload
+		if notification == nil {
+			h.local.skipVersions()
+			continue
+		}
+
+		var received pgBoardEvent
+		if err := json.Unmarshal([]byte(notification.Extra), &received); err != nil {
+			log.Printf("event hub: ignoring malformed event: %v", err)
+			continue
+		}
+		if received.Origin == h.origin {
+			continue
+		}
+		h.local.deliver(received.Event)
+	}
+}
+
+// Close stops listening and closes the hub's connections
+func (h *PgEventHub) Close() error {
+	var err error
+	h.closeOnce.Do(func() {
+		err = h.listener.Close()
+		if dbErr := h.db.Close(); err == nil {
+			err = dbErr
+		}
+	})
+	return err
+}
+
+// newInstanceID names this process in the events it publishes
+func newInstanceID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to create instance ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,42 @@
+package services
+
+import "testing"
+
+func TestMemoryEventHubVersions(t *testing.T) {
+	hub := NewMemoryEventHub()
+
+	hub.Publish(BoardEvent{Type: "card.created", BoardID: 1})
+	ch, version := hub.Subscribe(1)
+	defer hub.Unsubscribe(1, ch)
+	if version != 1 {
+		t.Fatalf("subscribed at version %d, want 1", version)
+	}
+
+	// Other boards count on their own
+	hub.Publish(BoardEvent{Type: "card.created", BoardID: 2})
+	hub.Publish(BoardEvent{Type: "card.updated", BoardID: 1})
+	if event := <-ch; event.Version != 2 {
+		t.Errorf("next event has version %d, want 2", event.Version)
+	}
+
+	// A subscriber that falls behind misses events, and the gap shows
+	for range cap(ch) + 1 {
+		hub.Publish(BoardEvent{Type: "card.updated", BoardID: 1})
+	}
+	for range cap(ch) {
+		<-ch
+	}
+	hub.Publish(BoardEvent{Type: "card.updated", BoardID: 1})
+	want := int64(2 + cap(ch) + 2)
+	if event := <-ch; event.Version != want {
+		t.Errorf("event after the dropped one has version %d, want %d", event.Version, want)
+	}
+
+	// Lost events are signalled the same way
+	hub.skipVersions()
+	hub.Publish(BoardEvent{Type: "card.updated", BoardID: 1})
+	want += 2
+	if event := <-ch; event.Version != want {
+		t.Errorf("event after skipping has version %d, want %d", event.Version, want)
+	}
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"krizzy/internal/models"
+)
+
+// CardFilter matches cards the way the board's filter bar does. Query
+// searches each card's SearchText. FieldID keeps cards with
+// that custom field set, and Value narrows them to values containing it.
+type CardFilter struct {
+	Query   string
+	FieldID int64
+	Value   string
+}
+
+// Matches reports whether card passes the filter
+func (f CardFilter) Matches(card *models.Card, fields []models.CustomField) bool {
+	if f.Query != "" {
+		if !strings.Contains(strings.ToLower(card.SearchText()), strings.ToLower(f.Query)) {
+			return false
+		}
+	}
+	if f.FieldID == 0 {
+		return true
+	}
+
+	raw, ok := card.FieldValues[f.FieldID]
+	if !ok {
+		return false
+	}
+	if f.Value == "" {
+		return true
+	}
+
+	fieldType := models.FieldTypeText
+	for _, field := range fields {
+		if field.ID == f.FieldID {
+			fieldType = field.Type
+		}
+	}
+	needle := strings.ToLower(f.Value)
+	for _, value := range models.FieldValueList(fieldType, raw) {
+		if strings.Contains(strings.ToLower(value), needle) {
+			return true
+		}
+	}
+	return false
+}
+
+// exportColumn is a column with only the cards an export keeps
+type exportColumn struct {
+	column *models.Column
+	cards  []models.Card
+}
+
+// exportBoard loads a board for export and applies filter to its cards
+func (s *KanbanService) exportBoard(boardID int64, filter CardFilter) (*models.Board, []exportColumn, error) {
+	board, err := s.GetBoardWithData(boardID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	columns := make([]exportColumn, 0, len(board.Columns))
+	for i := range board.Columns {
+		column := exportColumn{column: &board.Columns[i]}
+		for _, card := range board.Columns[i].Cards {
+			if filter.Matches(&card, board.Fields) {
+				column.cards = append(column.cards, card)
+			}
+		}
+		columns = append(columns, column)
+	}
+	return board, columns, nil
+}
+
+// ExportCSV writes one row per card: its column, lane, assignees, checklist
+// progress, timestamps, comment count and custom field values
+func (s *KanbanService) ExportCSV(w io.Writer, boardID int64, filter CardFilter) error {
+	board, columns, err := s.exportBoard(boardID, filter)
+	if err != nil {
+		return err
+	}
+
+	laneNames := make(map[int64]string, len(board.Swimlanes))
+	for _, lane := range board.Swimlanes {
+		laneNames[lane.ID] = lane.Name
+	}
+
+	out := csv.NewWriter(w)
+	header := []string{"ID", "Title", "Column", "Swimlane", "Assignees", "Checklist", "Created", "Completed", "Resolution", "Comments", "Description"}
+	for _, field := range board.Fields {
+		header = append(header, field.Name)
+	}
+	if err := out.Write(csvRow(header)); err != nil {
+		return err
+	}
+
+	for _, column := range columns {
+		for _, card := range column.cards {
+			comments, err := s.CommentRepo.GetByCardID(card.ID)
+			if err != nil {
+				return err
+			}
+
+			lane := ""
+			if card.SwimlaneID != nil {
+				lane = laneNames[*card.SwimlaneID]
+			}
+			completed := ""
+			if card.CompletedAt != nil {
+				completed = card.CompletedAt.Format(time.RFC3339)
+			}
+
+			row := []string{
+				fmt.Sprintf("%d", card.ID),
+				card.Title,
+				column.column.Name,
+				lane,
+				strings.Join(assigneeNames(card.Assignees), ", "),
+				checklistProgress(card.Checklist),
+				card.CreatedAt.Format(time.RFC3339),
+				completed,
+				card.Resolution,
+				fmt.Sprintf("%d", len(comments)),
+				card.Description,
+			}
+			for _, field := range board.Fields {
+				row = append(row, strings.Join(models.FieldValueList(field.Type, card.FieldValues[field.ID]), ", "))
+			}
+			if err := out.Write(csvRow(row)); err != nil {
+				return err
+			}
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+// ExportMarkdown writes a heading per column and per card, with checklists
+// as task lists
+func (s *KanbanService) ExportMarkdown(w io.Writer, boardID int64, filter CardFilter) error {
+	board, columns, err := s.exportBoard(boardID, filter)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", markdownLine(board.Name))
+	for _, column := range columns {
+		fmt.Fprintf(&b, "\n## %s\n", markdownLine(column.column.Name))
+		if len(column.cards) == 0 {
+			b.WriteString("\n_No cards_\n")
+			continue
+		}
+
+		for _, card := range column.cards {
+			fmt.Fprintf(&b, "\n### %s\n\n", markdownLine(card.Title))
+			if len(card.Assignees) > 0 {
+				fmt.Fprintf(&b, "Assignees: %s\n\n", markdownLine(strings.Join(assigneeNames(card.Assignees), ", ")))
+			}
+			if card.CompletedAt != nil {
+				fmt.Fprintf(&b, "%s %s\n\n", markdownLine(completedLabel(card.Resolution)), card.CompletedAt.Format("2006-01-02"))
+			}
+			if description := strings.TrimSpace(card.Description); description != "" {
+				b.WriteString(description + "\n\n")
+			}
+
+			for _, group := range exportChecklists(card.Checklist) {
+				if name := group[0].Checklist; name != "" {
+					fmt.Fprintf(&b, "**%s**\n\n", markdownLine(name))
+				}
+				for _, item := range group {
+					mark := " "
+					if item.IsCompleted {
+						mark = "x"
+					}
+					fmt.Fprintf(&b, "- [%s] %s\n", mark, markdownLine(item.Content))
+				}
+				b.WriteString("\n")
+			}
+		}
+	}
+
+	// Blocks each end with a blank line; drop the doubled ones where they meet
+	text := b.String()
+	for strings.Contains(text, "\n\n\n") {
+		text = strings.ReplaceAll(text, "\n\n\n", "\n\n")
+	}
+	_, err = io.WriteString(w, strings.TrimRight(text, "\n")+"\n")
+	return err
+}
+
+func assigneeNames(people []models.Person) []string {
+	names := make([]string, 0, len(people))
+	for _, person := range people {
+		names = append(names, person.Name)
+	}
+	return names
+}
+
+// exportChecklists splits a card's checklist into its named lists, in the
+// order each list first appears
+func exportChecklists(items []models.ChecklistItem) [][]models.ChecklistItem {
+	var groups [][]models.ChecklistItem
+	index := make(map[string]int)
+	for _, item := range items {
+		i, ok := index[item.Checklist]
+		if !ok {
+			i = len(groups)
+			index[item.Checklist] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], item)
+	}
+	return groups
+}
+
+// checklistProgress formats completed and total items, e.g. "2/5"
+func checklistProgress(items []models.ChecklistItem) string {
+	if len(items) == 0 {
+		return ""
+	}
+	completed := 0
+	for _, item := range items {
+		if item.IsCompleted {
+			completed++
+		}
+	}
+	return fmt.Sprintf("%d/%d", completed, len(items))
+}
+
+func completedLabel(resolution string) string {
+	if resolution == "" || resolution == models.DefaultResolution {
+		return "Completed"
+	}
+	return resolution
+}
+
+// csvRow guards cells a spreadsheet would run as a formula. Numbers are
+// left alone.
+func csvRow(cells []string) []string {
+	for i, cell := range cells {
+		if cell == "" || !strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+			continue
+		}
+		if _, err := strconv.ParseFloat(cell, 64); err == nil {
+			continue
+		}
+		cells[i] = "'" + cell
+	}
+	return cells
+}
+
+// markdownLine keeps text on one line so it cannot break a heading or list
+// item
+func markdownLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
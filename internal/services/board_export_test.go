@@ -0,0 +1,48 @@
+package services
+
+import (
+	"testing"
+
+	"krizzy/internal/models"
+)
+
+func TestCardFilterMatches(t *testing.T) {
+	fields := []models.CustomField{
+		{ID: 1, Name: "Labels", Type: models.FieldTypeMultiSelect, Options: []string{"Bug", "Feature"}},
+		{ID: 2, Name: "Customer", Type: models.FieldTypeText},
+	}
+	card := &models.Card{
+		Title:       "Fix login",
+		Description: "Sessions expire early",
+		Assignees:   []models.Person{{Name: "Ann Lee"}},
+		Checklist:   []models.ChecklistItem{{Content: "Repro", IsCompleted: true}, {Content: "Patch"}},
+		FieldValues: map[int64]string{1: `["Bug"]`, 2: `["Acme"]`},
+	}
+
+	tests := []struct {
+		name   string
+		filter CardFilter
+		want   bool
+	}{
+		{"title", CardFilter{Query: "LOGIN"}, true},
+		{"description", CardFilter{Query: "expire"}, true},
+		{"only an assignee's full name", CardFilter{Query: "ann lee"}, true},
+		// What the card shows besides its text is not searched
+		{"assignee initials", CardFilter{Query: "AL"}, false},
+		{"checklist progress", CardFilter{Query: "1/2"}, false},
+		{"field values are not text", CardFilter{Query: "bug"}, false},
+
+		{"field set", CardFilter{FieldID: 1}, true},
+		{"field not set", CardFilter{FieldID: 3}, false},
+		{"only a multi-select value", CardFilter{FieldID: 1, Value: "bu"}, true},
+		{"multi-select value missing", CardFilter{FieldID: 1, Value: "feature"}, false},
+		{"text value is not a list", CardFilter{FieldID: 2, Value: `["acme"]`}, true},
+		{"query and field together", CardFilter{Query: "login", FieldID: 1, Value: "bug"}, true},
+		{"query fails with field", CardFilter{Query: "logout", FieldID: 1, Value: "bug"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.Matches(card, fields); got != tt.want {
+			t.Errorf("%s: Matches(%+v) = %v, want %v", tt.name, tt.filter, got, tt.want)
+		}
+	}
+}
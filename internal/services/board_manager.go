@@ -1,10 +1,16 @@
 package services
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"log"
+	"os"
+	"path/filepath"
 	"regexp"
 	"sync"
+	"time"
 
 	"krizzy/internal/database"
 	"krizzy/internal/models"
@@ -15,25 +21,66 @@ import (
 
 var pgDbNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]{0,62}$`)
 
+// ErrBoardUnavailable is returned when a board exists but its storage cannot
+// be reached. Callers should treat it as temporary rather than as a missing board.
+var ErrBoardUnavailable = errors.New("board storage unavailable")
+
+// ErrDemoMode is returned for storage a demo instance does not offer
+var ErrDemoMode = errors.New("not available in demo mode")
+
+const (
+	// healthRetryAfter is how long requests fail fast for an unreachable board
+	// before GetServiceForBoard tries to connect again on its own.
+	healthRetryAfter  = 10 * time.Second
+	healthPingTimeout = 5 * time.Second
+	// pgConnectTimeout bounds connecting to a board's Postgres server, so an
+	// unreachable host fails in seconds rather than at the TCP timeout
+	pgConnectTimeout = 5 * time.Second
+	// pgSchemaMaxConns caps the pool of a board kept in a schema. Many such
+	// boards share one database, and so one server's max_connections.
+	pgSchemaMaxConns = 4
+)
+
 type BoardManager struct {
-	localDB    database.Database
-	boardRepo  repository.BoardRepository
-	pgConnRepo repository.PgConnectionRepository
-	mu         sync.RWMutex
-	services   map[int64]*KanbanService
-	pgDBs      map[int64]database.Database
+	localDB       *database.SQLiteDB
+	boardRepo     repository.BoardRepository
+	pgConnRepo    repository.PgConnectionRepository
+	boardFilesDir string
+	mu            sync.RWMutex
+	services      map[int64]*KanbanService
+	pgDBs         map[int64]database.Database
+	fileDBs       map[int64]*database.SQLiteDB
+	memory        *repository.MemoryStore
+	health        map[int64]*models.BoardHealth
+	stop          chan struct{}
+	stopOnce      sync.Once
 }
 
-func NewBoardManager(localDB database.Database, boardRepo repository.BoardRepository, pgConnRepo repository.PgConnectionRepository) *BoardManager {
+// NewBoardManager keeps the board list in localDB. Boards of type "file" get
+// their own SQLite database in boardFilesDir, opened like localDB.
+func NewBoardManager(localDB *database.SQLiteDB, boardRepo repository.BoardRepository, pgConnRepo repository.PgConnectionRepository, boardFilesDir string) *BoardManager {
 	return &BoardManager{
-		localDB:    localDB,
-		boardRepo:  boardRepo,
-		pgConnRepo: pgConnRepo,
-		services:   make(map[int64]*KanbanService),
-		pgDBs:      make(map[int64]database.Database),
+		localDB:       localDB,
+		boardRepo:     boardRepo,
+		pgConnRepo:    pgConnRepo,
+		boardFilesDir: boardFilesDir,
+		services:      make(map[int64]*KanbanService),
+		pgDBs:         make(map[int64]database.Database),
+		fileDBs:       make(map[int64]*database.SQLiteDB),
+		health:        make(map[int64]*models.BoardHealth),
+		stop:          make(chan struct{}),
 	}
 }
 
+// NewMemoryBoardManager keeps the board list and every board in store, for
+// demo mode. Boards can only be of type "memory" and Postgres connections
+// cannot be used.
+func NewMemoryBoardManager(store *repository.MemoryStore) *BoardManager {
+	bm := NewBoardManager(nil, store.Boards(), store.PgConnections(), "")
+	bm.memory = store
+	return bm
+}
+
 func (bm *BoardManager) BoardRepo() repository.BoardRepository {
 	return bm.boardRepo
 }
@@ -43,7 +90,14 @@ func (bm *BoardManager) PgConnRepo() repository.PgConnectionRepository {
 }
 
 func (bm *BoardManager) GetAllBoards() ([]models.Board, error) {
-	return bm.boardRepo.GetAll()
+	boards, err := bm.boardRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for i := range boards {
+		boards[i].Health = bm.BoardHealth(boards[i].ID)
+	}
+	return boards, nil
 }
 
 func (bm *BoardManager) GetBoard(id int64) (*models.Board, error) {
@@ -59,50 +113,133 @@ func (bm *BoardManager) GetServiceForBoard(boardID int64) (*KanbanService, error
 	bm.mu.RUnlock()
 
 	bm.mu.Lock()
-	defer bm.mu.Unlock()
 
 	// Double-check after acquiring write lock
 	if svc, ok := bm.services[boardID]; ok {
+		bm.mu.Unlock()
 		return svc, nil
 	}
 
 	board, err := bm.boardRepo.GetByID(boardID)
 	if err != nil {
+		bm.mu.Unlock()
 		return nil, fmt.Errorf("board not found: %w", err)
 	}
 
-	var svc *KanbanService
-	if board.DbType == "postgres" {
-		svc, err = bm.createPostgresService(board)
-	} else {
-		svc, err = bm.createLocalService(board)
+	if board.DbType != "postgres" {
+		defer bm.mu.Unlock()
+		var svc *KanbanService
+		switch board.DbType {
+		case "file":
+			svc, err = bm.createFileService(board)
+		case "memory":
+			svc, err = bm.createMemoryService(board)
+		default:
+			svc, err = bm.createLocalService(board)
+		}
+		if err != nil {
+			return nil, err
+		}
+		bm.services[boardID] = svc
+		return svc, nil
+	}
+
+	// Fail fast while a recent attempt is still fresh; the health checker or
+	// an explicit ProbeBoard will reconnect once the server is back.
+	if h, ok := bm.health[boardID]; ok && !h.Available && time.Since(h.CheckedAt) < healthRetryAfter {
+		bm.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrBoardUnavailable, h.LastError)
+	}
+	bm.mu.Unlock()
+
+	// Connect without the lock, so a slow or unreachable server only holds
+	// up requests for its own boards
+	ctx, cancel := context.WithTimeout(context.Background(), pgConnectTimeout)
+	pgDB, err := bm.openPostgres(ctx, board)
+	cancel()
+
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	// Another request may have connected the board in the meantime
+	if svc, ok := bm.services[boardID]; ok {
+		if pgDB != nil {
+			pgDB.Close()
+		}
+		return svc, nil
 	}
 	if err != nil {
-		return nil, err
+		bm.markUnavailable(boardID, err)
+		return nil, fmt.Errorf("%w: %v", ErrBoardUnavailable, err)
 	}
+	bm.markAvailable(boardID)
 
+	bm.pgDBs[boardID] = pgDB
+	svc := newPostgresService(pgDB.DB(), boardID, bm.boardRepo)
 	bm.services[boardID] = svc
 	return svc, nil
 }
 
 func (bm *BoardManager) createLocalService(board *models.Board) (*KanbanService, error) {
-	db := bm.localDB.DB()
-	return NewKanbanService(
-		bm.boardRepo,
-		repository.NewSQLiteColumnRepository(db),
-		repository.NewSQLiteCardRepository(db),
-		repository.NewSQLitePersonRepository(db),
-		repository.NewSQLiteCommentRepository(db),
-		repository.NewSQLiteChecklistRepository(db),
-	), nil
+	return newLocalService(bm.localDB.ReadWrite(), bm.boardRepo), nil
+}
+
+func (bm *BoardManager) createMemoryService(board *models.Board) (*KanbanService, error) {
+	if bm.memory == nil {
+		return nil, fmt.Errorf("board %d is kept in memory, which only demo mode supports", board.ID)
+	}
+	return NewKanbanService(bm.boardRepo, bm.memory.Repositories()), nil
+}
+
+// BoardFilePath is where a board of type "file" keeps its SQLite database
+func (bm *BoardManager) BoardFilePath(boardID int64) string {
+	return filepath.Join(bm.boardFilesDir, fmt.Sprintf("board-%d.db", boardID))
+}
+
+func (bm *BoardManager) createFileService(board *models.Board) (*KanbanService, error) {
+	if err := os.MkdirAll(bm.boardFilesDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create board files directory: %w", err)
+	}
+
+	fileDB, err := database.NewSQLite(bm.BoardFilePath(board.ID), bm.localDB.Options())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database file for board %d: %w", board.ID, err)
+	}
+
+	if err := fileDB.Migrate(); err != nil {
+		fileDB.Close()
+		return nil, fmt.Errorf("failed to migrate database file for board %d: %w", board.ID, err)
+	}
+
+	// Columns, people and the rest reference the board by ID, so the file
+	// keeps its own copy of the board row. As a local board it also opens on
+	// its own, for example as another instance's DATABASE_PATH.
+	if _, err := fileDB.DB().Exec(
+		`INSERT INTO boards (id, name, db_type) VALUES (?, ?, 'local')
+		 ON CONFLICT(id) DO UPDATE SET name = excluded.name`,
+		board.ID, board.Name,
+	); err != nil {
+		fileDB.Close()
+		return nil, fmt.Errorf("failed to register board %d in its database file: %w", board.ID, err)
+	}
+
+	bm.fileDBs[board.ID] = fileDB
+
+	return newLocalService(fileDB.ReadWrite(), bm.boardRepo), nil
+}
+
+// newLocalService builds a service on a SQLite database, the shared one or a
+// board's own file, or on a transaction open on it
+func newLocalService(db repository.DBTX, boardRepo repository.BoardRepository) *KanbanService {
+	return NewKanbanService(boardRepo, repository.NewSQLiteRepositories(db))
 }
 
 func (bm *BoardManager) buildConnString(conn *models.PgConnection, dbName string) string {
-	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		conn.Host, conn.Port, conn.User, conn.Password, dbName, conn.SSLMode)
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s connect_timeout=%d",
+		conn.Host, conn.Port, conn.User, conn.Password, dbName, conn.SSLMode, int(pgConnectTimeout.Seconds()))
 }
 
-func (bm *BoardManager) ensureDatabase(conn *models.PgConnection, dbName string) error {
+func (bm *BoardManager) ensureDatabase(ctx context.Context, conn *models.PgConnection, dbName string) error {
 	// Connect to the "postgres" database to create the target database
 	adminConn := bm.buildConnString(conn, "postgres")
 	adminDB, err := sql.Open("postgres", adminConn)
@@ -113,14 +250,14 @@ func (bm *BoardManager) ensureDatabase(conn *models.PgConnection, dbName string)
 
 	// Check if database exists
 	var exists bool
-	err = adminDB.QueryRow("SELECT EXISTS(SELECT 1 FROM pg_database WHERE datname = $1)", dbName).Scan(&exists)
+	err = adminDB.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM pg_database WHERE datname = $1)", dbName).Scan(&exists)
 	if err != nil {
 		return fmt.Errorf("failed to check database existence: %w", err)
 	}
 
 	if !exists {
 		// Database names can't be parameterised, but we've validated the name is alphanumeric+underscores
-		_, err = adminDB.Exec(fmt.Sprintf("CREATE DATABASE %q", dbName))
+		_, err = adminDB.ExecContext(ctx, fmt.Sprintf("CREATE DATABASE %q", dbName))
 		if err != nil {
 			return fmt.Errorf("failed to create database %s: %w", dbName, err)
 		}
@@ -129,7 +266,9 @@ func (bm *BoardManager) ensureDatabase(conn *models.PgConnection, dbName string)
 	return nil
 }
 
-func (bm *BoardManager) createPostgresService(board *models.Board) (*KanbanService, error) {
+// openPostgres connects to a board's Postgres storage and migrates it. It
+// does not touch the manager's state, so it runs without bm.mu held.
+func (bm *BoardManager) openPostgres(ctx context.Context, board *models.Board) (*database.PostgresDB, error) {
 	if board.PgConnectionID == nil {
 		return nil, fmt.Errorf("board %d has no postgres connection configured", board.ID)
 	}
@@ -139,50 +278,76 @@ func (bm *BoardManager) createPostgresService(board *models.Board) (*KanbanServi
 		return nil, fmt.Errorf("failed to load postgres connection: %w", err)
 	}
 
-	// Ensure the database exists
-	if err := bm.ensureDatabase(conn, board.PgDatabaseName); err != nil {
+	// A board in a schema shares a database that must already exist, so it
+	// needs no CREATEDB privilege. Every connection of its pool starts with
+	// the schema on its search_path, so the repositories and migrations work
+	// on the board's tables unchanged. That setting belongs to the
+	// connection, and reads outside a transaction have nowhere to set it per
+	// statement, so boards in one database can't share a pool; each gets a
+	// small one instead.
+	connString := bm.buildConnString(conn, board.PgDatabaseName)
+	if board.PgSchemaName != "" {
+		connString += fmt.Sprintf(" search_path='%q'", board.PgSchemaName)
+	} else if err := bm.ensureDatabase(ctx, conn, board.PgDatabaseName); err != nil {
 		return nil, fmt.Errorf("failed to ensure database for board %d: %w", board.ID, err)
 	}
 
-	connString := bm.buildConnString(conn, board.PgDatabaseName)
-	pgDB, err := database.NewPostgres(connString)
+	pgDB, err := database.NewPostgres(ctx, connString)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to postgres for board %d: %w", board.ID, err)
 	}
 
+	if board.PgSchemaName != "" {
+		pgDB.DB().SetMaxOpenConns(pgSchemaMaxConns)
+		pgDB.DB().SetMaxIdleConns(1)
+
+		// Schema names can't be parameterised either; they are validated like database names
+		if _, err := pgDB.DB().ExecContext(ctx, fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %q", board.PgSchemaName)); err != nil {
+			pgDB.Close()
+			return nil, fmt.Errorf("failed to create schema %s for board %d: %w", board.PgSchemaName, board.ID, err)
+		}
+	}
+
 	if err := pgDB.Migrate(); err != nil {
 		pgDB.Close()
 		return nil, fmt.Errorf("failed to migrate postgres for board %d: %w", board.ID, err)
 	}
 
-	bm.pgDBs[board.ID] = pgDB
+	return pgDB, nil
+}
 
-	db := pgDB.DB()
-	return NewKanbanService(
-		bm.boardRepo,
-		repository.NewPgColumnRepository(db, board.ID),
-		repository.NewPgCardRepository(db),
-		repository.NewPgPersonRepository(db, board.ID),
-		repository.NewPgCommentRepository(db),
-		repository.NewPgChecklistRepository(db),
-	), nil
+// newPostgresService builds a service on a board's Postgres database, or on
+// a transaction open on it
+func newPostgresService(db repository.DBTX, boardID int64, boardRepo repository.BoardRepository) *KanbanService {
+	return NewKanbanService(boardRepo, repository.NewPgRepositories(db, boardID))
 }
 
-func (bm *BoardManager) CreateBoard(name, dbType string, pgConnectionID *int64, pgDatabaseName string) (*models.Board, error) {
-	return bm.createBoard(name, dbType, pgConnectionID, pgDatabaseName, true)
+// CreateBoard creates a board with the default columns. A Postgres board gets
+// a database of its own, or lives in pgSchemaName of the shared database
+// pgDatabaseName when that is set.
+func (bm *BoardManager) CreateBoard(name, dbType string, pgConnectionID *int64, pgDatabaseName, pgSchemaName string) (*models.Board, error) {
+	return bm.createBoard(name, dbType, pgConnectionID, pgDatabaseName, pgSchemaName, true)
 }
 
-func (bm *BoardManager) CreateBoardWithoutDefaults(name, dbType string, pgConnectionID *int64, pgDatabaseName string) (*models.Board, error) {
-	return bm.createBoard(name, dbType, pgConnectionID, pgDatabaseName, false)
+func (bm *BoardManager) CreateBoardWithoutDefaults(name, dbType string, pgConnectionID *int64, pgDatabaseName, pgSchemaName string) (*models.Board, error) {
+	return bm.createBoard(name, dbType, pgConnectionID, pgDatabaseName, pgSchemaName, false)
 }
 
-func (bm *BoardManager) createBoard(name, dbType string, pgConnectionID *int64, pgDatabaseName string, createDefaultColumns bool) (*models.Board, error) {
+func (bm *BoardManager) createBoard(name, dbType string, pgConnectionID *int64, pgDatabaseName, pgSchemaName string, createDefaultColumns bool) (*models.Board, error) {
+	dbType, err := bm.boardType(dbType)
+	if err != nil {
+		return nil, err
+	}
+
 	board := &models.Board{
 		Name:           name,
 		DbType:         dbType,
 		PgConnectionID: pgConnectionID,
 		PgDatabaseName: pgDatabaseName,
 	}
+	if dbType == "postgres" {
+		board.PgSchemaName = pgSchemaName
+	}
 
 	if dbType == "postgres" {
 		if pgConnectionID == nil {
@@ -191,10 +356,18 @@ func (bm *BoardManager) createBoard(name, dbType string, pgConnectionID *int64,
 		if !pgDbNameRegex.MatchString(pgDatabaseName) {
 			return nil, fmt.Errorf("invalid database name: must be alphanumeric with underscores, max 63 chars")
 		}
+		if pgSchemaName != "" && !pgDbNameRegex.MatchString(pgSchemaName) {
+			return nil, fmt.Errorf("invalid schema name: must be alphanumeric with underscores, max 63 chars")
+		}
 		// Verify connection exists
 		if _, err := bm.pgConnRepo.GetByID(*pgConnectionID); err != nil {
 			return nil, fmt.Errorf("postgres connection not found")
 		}
+		if pgSchemaName != "" {
+			if err := bm.checkSchemaFree(*pgConnectionID, pgDatabaseName, pgSchemaName); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	if err := bm.boardRepo.Create(board); err != nil {
@@ -218,6 +391,135 @@ func (bm *BoardManager) createBoard(name, dbType string, pgConnectionID *int64,
 	return board, nil
 }
 
+// boardType returns the storage a new board gets. A demo instance keeps
+// local boards in memory and refuses other storage.
+func (bm *BoardManager) boardType(dbType string) (string, error) {
+	if bm.memory == nil {
+		if dbType == "memory" {
+			return "", fmt.Errorf("memory boards are only available in demo mode")
+		}
+		return dbType, nil
+	}
+	if dbType != "" && dbType != "local" && dbType != "memory" {
+		return "", fmt.Errorf("%w: boards are kept in memory", ErrDemoMode)
+	}
+	return "memory", nil
+}
+
+// checkSchemaFree rejects a schema another board already lives in, since
+// two boards in one schema would share their tables
+func (bm *BoardManager) checkSchemaFree(pgConnectionID int64, pgDatabaseName, pgSchemaName string) error {
+	boards, err := bm.boardRepo.GetAll()
+	if err != nil {
+		return err
+	}
+	for _, b := range boards {
+		if b.PgConnectionID != nil && *b.PgConnectionID == pgConnectionID &&
+			b.PgDatabaseName == pgDatabaseName && b.PgSchemaName == pgSchemaName {
+			return fmt.Errorf("schema %s of database %s is already used by board %q", pgSchemaName, pgDatabaseName, b.Name)
+		}
+	}
+	return nil
+}
+
+// CreateBoardInTx creates an empty board and fills it inside one transaction
+// on the board's storage. A local board's row is part of the transaction, so
+// a failure leaves nothing behind. File and Postgres boards need their entry
+// and database first; a failure rolls their data back and removes the entry.
+// Cancelling ctx rolls the transaction back.
+func (bm *BoardManager) CreateBoardInTx(ctx context.Context, name, dbType string, pgConnectionID *int64, pgDatabaseName, pgSchemaName string, fill func(board *models.Board, svc *KanbanService) error) (*models.Board, error) {
+	dbType, err := bm.boardType(dbType)
+	if err != nil {
+		return nil, err
+	}
+	if dbType == "memory" {
+		return bm.createMemoryBoardInTx(ctx, name, fill)
+	}
+
+	if dbType != "postgres" && dbType != "file" {
+		tx, err := bm.localDB.DB().BeginTx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Rollback()
+
+		boardRepo := repository.NewSQLiteBoardRepository(tx)
+		board := &models.Board{Name: name, DbType: "local"}
+		if err := boardRepo.Create(board); err != nil {
+			return nil, err
+		}
+		if err := fill(board, newLocalService(tx, boardRepo)); err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+		return board, nil
+	}
+
+	board, err := bm.CreateBoardWithoutDefaults(name, dbType, pgConnectionID, pgDatabaseName, pgSchemaName)
+	if err != nil {
+		return nil, err
+	}
+	if err := bm.fillBoard(ctx, board, fill); err != nil {
+		bm.DeleteBoard(board.ID)
+		return nil, err
+	}
+	return board, nil
+}
+
+// createMemoryBoardInTx creates and fills a board in one transaction on the
+// memory store, like a local board in the SQLite database
+func (bm *BoardManager) createMemoryBoardInTx(ctx context.Context, name string, fill func(board *models.Board, svc *KanbanService) error) (*models.Board, error) {
+	board := &models.Board{Name: name, DbType: "memory"}
+	err := bm.memory.Transaction(func(boardRepo repository.BoardRepository, repos repository.Repositories) error {
+		if err := boardRepo.Create(board); err != nil {
+			return err
+		}
+		if err := fill(board, NewKanbanService(boardRepo, repos)); err != nil {
+			return err
+		}
+		return ctx.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return board, nil
+}
+
+// fillBoard runs fill in a transaction on the database a file or Postgres
+// board was opened with
+func (bm *BoardManager) fillBoard(ctx context.Context, board *models.Board, fill func(board *models.Board, svc *KanbanService) error) error {
+	bm.mu.RLock()
+	var db *sql.DB
+	if board.DbType == "file" {
+		if fileDB, ok := bm.fileDBs[board.ID]; ok {
+			db = fileDB.DB()
+		}
+	} else if pgDB, ok := bm.pgDBs[board.ID]; ok {
+		db = pgDB.DB()
+	}
+	bm.mu.RUnlock()
+	if db == nil {
+		return fmt.Errorf("%w: board %d is not connected", ErrBoardUnavailable, board.ID)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	svc := newPostgresService(tx, board.ID, bm.boardRepo)
+	if board.DbType == "file" {
+		svc = newLocalService(tx, bm.boardRepo)
+	}
+	if err := fill(board, svc); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
 func (bm *BoardManager) RenameBoard(id int64, name string) error {
 	board, err := bm.boardRepo.GetByID(id)
 	if err != nil {
@@ -227,17 +529,51 @@ func (bm *BoardManager) RenameBoard(id int64, name string) error {
 	return bm.boardRepo.Update(board)
 }
 
+// SetLaneGrouping switches a board between its stored swimlanes and a
+// dynamic grouping such as models.LaneGroupingAssignee
+func (bm *BoardManager) SetLaneGrouping(id int64, grouping string) error {
+	board, err := bm.boardRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	board.LaneGrouping = grouping
+	return bm.boardRepo.Update(board)
+}
+
+// DeleteBoard removes a board. A file board's database file goes with it; a
+// Postgres board's database is left on the server.
 func (bm *BoardManager) DeleteBoard(id int64) error {
 	bm.mu.Lock()
 	defer bm.mu.Unlock()
 
+	// Deleting a board that is already gone is not an error
+	board, err := bm.boardRepo.GetByID(id)
+	removeFile := err == nil && board.DbType == "file"
+
 	if pgDB, ok := bm.pgDBs[id]; ok {
 		pgDB.Close()
 		delete(bm.pgDBs, id)
 	}
+	if fileDB, ok := bm.fileDBs[id]; ok {
+		fileDB.Close()
+		delete(bm.fileDBs, id)
+	}
 	delete(bm.services, id)
+	delete(bm.health, id)
+
+	if err := bm.boardRepo.Delete(id); err != nil {
+		return err
+	}
 
-	return bm.boardRepo.Delete(id)
+	if removeFile {
+		path := bm.BoardFilePath(id)
+		for _, name := range []string{path, path + "-journal", path + "-wal", path + "-shm"} {
+			if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+				log.Printf("delete board %d: failed to remove %s: %v", id, name, err)
+			}
+		}
+	}
+	return nil
 }
 
 // InvalidateCache removes the cached service for a board
@@ -247,17 +583,128 @@ func (bm *BoardManager) InvalidateCache(boardID int64) {
 	delete(bm.services, boardID)
 }
 
-// Close cleans up all Postgres connections
+// Close stops the health checker and closes all Postgres connections and
+// board files
 func (bm *BoardManager) Close() {
+	bm.stopOnce.Do(func() { close(bm.stop) })
+
 	bm.mu.Lock()
 	defer bm.mu.Unlock()
 	for _, pgDB := range bm.pgDBs {
 		pgDB.Close()
 	}
+	for _, fileDB := range bm.fileDBs {
+		fileDB.Close()
+	}
+}
+
+// BoardHealth returns a copy of the last known health of a board, or nil for
+// local boards and Postgres boards that have not been opened yet.
+func (bm *BoardManager) BoardHealth(boardID int64) *models.BoardHealth {
+	bm.mu.RLock()
+	defer bm.mu.RUnlock()
+	h, ok := bm.health[boardID]
+	if !ok {
+		return nil
+	}
+	copied := *h
+	return &copied
+}
+
+// StartHealthChecks probes every Postgres board in the background until Close
+// is called. An interval of zero disables background probing.
+func (bm *BoardManager) StartHealthChecks(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		bm.probePostgresBoards()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-bm.stop:
+				return
+			case <-ticker.C:
+				bm.probePostgresBoards()
+			}
+		}
+	}()
+}
+
+func (bm *BoardManager) probePostgresBoards() {
+	boards, err := bm.boardRepo.GetAll()
+	if err != nil {
+		log.Printf("health check: failed to list boards: %v", err)
+		return
+	}
+	for _, board := range boards {
+		if board.DbType != "postgres" {
+			continue
+		}
+		if err := bm.ProbeBoard(board.ID); err != nil {
+			log.Printf("health check: board %d unavailable: %v", board.ID, err)
+		}
+	}
+}
+
+// ProbeBoard checks a board's storage right away. A cached pool that no longer
+// answers is dropped, and an unreachable board is reconnected if possible.
+func (bm *BoardManager) ProbeBoard(boardID int64) error {
+	bm.mu.Lock()
+	pgDB, cached := bm.pgDBs[boardID]
+	bm.mu.Unlock()
+
+	if cached {
+		ctx, cancel := context.WithTimeout(context.Background(), healthPingTimeout)
+		err := pgDB.DB().PingContext(ctx)
+		cancel()
+
+		bm.mu.Lock()
+		defer bm.mu.Unlock()
+		if err == nil {
+			bm.markAvailable(boardID)
+			return nil
+		}
+		// Only drop the pool if nobody replaced it while we were pinging
+		if bm.pgDBs[boardID] == pgDB {
+			pgDB.Close()
+			delete(bm.pgDBs, boardID)
+			delete(bm.services, boardID)
+		}
+		bm.markUnavailable(boardID, err)
+		return fmt.Errorf("%w: %v", ErrBoardUnavailable, err)
+	}
+
+	// Clear the fail-fast window so GetServiceForBoard attempts a fresh connection
+	bm.mu.Lock()
+	if h, ok := bm.health[boardID]; ok {
+		h.CheckedAt = time.Time{}
+	}
+	bm.mu.Unlock()
+
+	_, err := bm.GetServiceForBoard(boardID)
+	return err
+}
+
+// markAvailable and markUnavailable must be called with bm.mu held for writing
+func (bm *BoardManager) markAvailable(boardID int64) {
+	bm.health[boardID] = &models.BoardHealth{Available: true, CheckedAt: time.Now()}
+}
+
+func (bm *BoardManager) markUnavailable(boardID int64, err error) {
+	bm.health[boardID] = &models.BoardHealth{Available: false, LastError: err.Error(), CheckedAt: time.Now()}
 }
 
 // TestConnection tests connectivity to a PG server
 func (bm *BoardManager) TestConnection(conn *models.PgConnection) error {
+	// A public demo must not open connections to hosts its visitors pick
+	if bm.memory != nil {
+		return fmt.Errorf("%w: Postgres connections are disabled", ErrDemoMode)
+	}
+
 	connString := bm.buildConnString(conn, "postgres")
 	db, err := sql.Open("postgres", connString)
 	if err != nil {
@@ -272,6 +719,38 @@ func (bm *BoardManager) TestConnection(conn *models.PgConnection) error {
 	return nil
 }
 
+// UpdateConnection saves new settings for a Postgres connection after checking
+// they work, then drops the cached pools of every board using it so the next
+// request reconnects with the new credentials.
+func (bm *BoardManager) UpdateConnection(conn *models.PgConnection) error {
+	if err := bm.TestConnection(conn); err != nil {
+		return err
+	}
+	if err := bm.pgConnRepo.Update(conn); err != nil {
+		return fmt.Errorf("failed to save connection: %w", err)
+	}
+
+	boards, err := bm.boardRepo.GetAll()
+	if err != nil {
+		return err
+	}
+
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+	for _, b := range boards {
+		if b.PgConnectionID == nil || *b.PgConnectionID != conn.ID {
+			continue
+		}
+		if pgDB, ok := bm.pgDBs[b.ID]; ok {
+			pgDB.Close()
+			delete(bm.pgDBs, b.ID)
+		}
+		delete(bm.services, b.ID)
+		delete(bm.health, b.ID)
+	}
+	return nil
+}
+
 // HasBoardsUsingConnection checks if any boards reference the given connection
 func (bm *BoardManager) HasBoardsUsingConnection(connID int64) (bool, error) {
 	boards, err := bm.boardRepo.GetAll()
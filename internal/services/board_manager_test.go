@@ -0,0 +1,185 @@
+package services
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"krizzy/internal/database"
+	"krizzy/internal/models"
+	"krizzy/internal/repository"
+)
+
+// newTestBoardManager opens a board manager on a fresh SQLite database
+func newTestBoardManager(t *testing.T) (*BoardManager, *database.SQLiteDB) {
+	t.Helper()
+
+	dir := t.TempDir()
+	db, err := database.NewSQLite(filepath.Join(dir, "krizzy.db"), database.DefaultSQLiteOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+
+	bm := NewBoardManager(db, repository.NewSQLiteBoardRepository(db.ReadWrite()), repository.NewSQLitePgConnectionRepository(db.DB()), filepath.Join(dir, "boards"))
+	t.Cleanup(bm.Close)
+	return bm, db
+}
+
+// newMemoryBoard creates a board with the default columns in a memory store
+func newMemoryBoard(t *testing.T, name string) (*KanbanService, *models.Board) {
+	t.Helper()
+
+	bm := NewMemoryBoardManager(repository.NewMemoryStore())
+	t.Cleanup(bm.Close)
+	board, err := bm.CreateBoard(name, "", nil, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc, err := bm.GetServiceForBoard(board.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svc, board
+}
+
+// TestSlowPostgresDoesNotBlockOtherBoards connects a board to a server that
+// accepts connections and never answers. Other boards must stay usable while
+// that connection attempt runs, and the attempt must give up on its own.
+func TestSlowPostgresDoesNotBlockOtherBoards(t *testing.T) {
+	if testing.Short() {
+		t.Skip("waits for the Postgres connect timeout")
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	bm, db := newTestBoardManager(t)
+	local, err := bm.CreateBoard("Local", "local", nil, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn := &models.PgConnection{Name: "Silent", Host: "127.0.0.1", Port: listener.Addr().(*net.TCPAddr).Port, User: "krizzy", SSLMode: "disable"}
+	if err := repository.NewSQLitePgConnectionRepository(db.DB()).Create(conn); err != nil {
+		t.Fatal(err)
+	}
+	// The board row is written directly; CreateBoard would connect first
+	silent := &models.Board{Name: "Silent", DbType: "postgres", PgConnectionID: &conn.ID, PgDatabaseName: "krizzy", PgSchemaName: "silent"}
+	if err := repository.NewSQLiteBoardRepository(db.ReadWrite()).Create(silent); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	started := time.Now()
+	go func() {
+		_, err := bm.GetServiceForBoard(silent.ID)
+		done <- err
+	}()
+
+	// Give the connection attempt time to start
+	time.Sleep(200 * time.Millisecond)
+	localStarted := time.Now()
+	if _, err := bm.GetServiceForBoard(local.ID); err != nil {
+		t.Fatal(err)
+	}
+	if waited := time.Since(localStarted); waited > time.Second {
+		t.Errorf("local board waited %v for the Postgres connection attempt", waited)
+	}
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("connected to a server that never answers")
+		}
+		if took := time.Since(started); took > 2*pgConnectTimeout {
+			t.Errorf("connection attempt took %v", took)
+		}
+	case <-time.After(4 * pgConnectTimeout):
+		t.Fatal("connection attempt did not time out")
+	}
+}
+
+// TestMoveCardToBoardInOneTransaction moves a card between two boards of the
+// local database while deleting the original fails. The copy must be rolled
+// back with it rather than leave the card on both boards.
+func TestMoveCardToBoardInOneTransaction(t *testing.T) {
+	bm, db := newTestBoardManager(t)
+
+	var boards [2]*models.Board
+	var columns [2]models.Column
+	for i, name := range []string{"From", "To"} {
+		board, err := bm.CreateBoard(name, "local", nil, "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		svc, err := bm.GetServiceForBoard(board.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		boardColumns, err := svc.ColumnRepo.GetByBoardID(board.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		boards[i], columns[i] = board, boardColumns[0]
+	}
+
+	src, err := bm.GetServiceForBoard(boards[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	card := &models.Card{ColumnID: columns[0].ID, Title: "Travelling"}
+	if err := src.CardRepo.Create(card); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.DB().Exec(`CREATE TRIGGER keep_cards BEFORE DELETE ON cards
+		BEGIN SELECT RAISE(ABORT, 'cards are kept'); END`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bm.MoveCardToBoard(boards[0].ID, card.ID, boards[1].ID, columns[1].ID); err == nil {
+		t.Fatal("move succeeded although the original could not be deleted")
+	}
+
+	dst, err := bm.GetServiceForBoard(boards[1].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	copies, err := dst.CardRepo.GetByColumnID(columns[1].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(copies) != 0 {
+		t.Errorf("destination has %d cards after the failed move, want 0", len(copies))
+	}
+
+	if _, err := db.DB().Exec("DROP TRIGGER keep_cards"); err != nil {
+		t.Fatal(err)
+	}
+	moved, err := bm.MoveCardToBoard(boards[0].ID, card.ID, boards[1].ID, columns[1].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved.ColumnID != columns[1].ID {
+		t.Errorf("card moved to column %d, want %d", moved.ColumnID, columns[1].ID)
+	}
+	if _, err := src.CardRepo.GetByID(card.ID); err == nil {
+		t.Error("original card is still on the source board")
+	}
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"strconv"
+	"time"
+
+	"krizzy/internal/models"
+)
+
+// CardJSON is a card as the JSON API returns it
+type CardJSON struct {
+	ID          int64            `json:"id"`
+	Title       string           `json:"title"`
+	Description string           `json:"description"`
+	Column      string           `json:"column"`
+	Swimlane    string           `json:"swimlane,omitempty"`
+	Position    int              `json:"position"`
+	Assignees   []string         `json:"assignees"`
+	CreatedAt   time.Time        `json:"created_at"`
+	CompletedAt *time.Time       `json:"completed_at,omitempty"`
+	Resolution  string           `json:"resolution,omitempty"`
+	Fields      []FieldValueJSON `json:"fields"`
+}
+
+// FieldValueJSON is one custom field value set on a card. Value is a number
+// for number fields, true for checkboxes, a list of options for multi-selects
+// and text otherwise.
+type FieldValueJSON struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value any    `json:"value"`
+}
+
+// ListCardsJSON returns the board's cards that pass filter, column by column
+func (s *KanbanService) ListCardsJSON(boardID int64, filter CardFilter) ([]CardJSON, error) {
+	board, columns, err := s.exportBoard(boardID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	cards := []CardJSON{}
+	for _, column := range columns {
+		for _, card := range column.cards {
+			cards = append(cards, cardJSON(board, column.column, &card))
+		}
+	}
+	return cards, nil
+}
+
+// GetCardJSON returns one card of the board
+func (s *KanbanService) GetCardJSON(boardID, cardID int64) (*CardJSON, error) {
+	if _, err := s.CardInBoard(boardID, cardID); err != nil {
+		return nil, err
+	}
+	board, err := s.GetBoardWithData(boardID)
+	if err != nil {
+		return nil, err
+	}
+	card, err := s.GetCardWithDetails(cardID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range board.Columns {
+		if board.Columns[i].ID == card.ColumnID {
+			result := cardJSON(board, &board.Columns[i], card)
+			return &result, nil
+		}
+	}
+	return nil, ErrNotInBoard
+}
+
+func cardJSON(board *models.Board, column *models.Column, card *models.Card) CardJSON {
+	result := CardJSON{
+		ID:          card.ID,
+		Title:       card.Title,
+		Description: card.Description,
+		Column:      column.Name,
+		Position:    card.Position,
+		Assignees:   assigneeNames(card.Assignees),
+		CreatedAt:   card.CreatedAt,
+		CompletedAt: card.CompletedAt,
+		Resolution:  card.Resolution,
+		Fields:      []FieldValueJSON{},
+	}
+	if card.SwimlaneID != nil {
+		for _, lane := range board.Swimlanes {
+			if lane.ID == *card.SwimlaneID {
+				result.Swimlane = lane.Name
+			}
+		}
+	}
+
+	for i := range board.Fields {
+		field := &board.Fields[i]
+		if value, ok := fieldValueJSON(field, card.FieldValues[field.ID]); ok {
+			result.Fields = append(result.Fields, FieldValueJSON{ID: field.ID, Name: field.Name, Type: field.Type, Value: value})
+		}
+	}
+	return result
+}
+
+// fieldValueJSON runs a stored value back through NormalizeFieldValue, so the
+// API reports what the field accepts today. Values that no longer fit, such as
+// a select option that was since removed, are left out like unset ones.
+func fieldValueJSON(field *models.CustomField, raw string) (any, bool) {
+	value, err := NormalizeFieldValue(field, models.FieldValueList(field.Type, raw))
+	if err != nil || value == "" {
+		return nil, false
+	}
+
+	switch field.Type {
+	case models.FieldTypeNumber:
+		n, err := strconv.ParseFloat(value, 64)
+		return n, err == nil
+	case models.FieldTypeCheckbox:
+		return true, true
+	case models.FieldTypeMultiSelect:
+		return models.FieldValueList(field.Type, value), true
+	}
+	return value, true
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"krizzy/internal/models"
+)
+
+func TestCardJSONFieldValues(t *testing.T) {
+	svc, board := newMemoryBoard(t, "API")
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	columns, err := svc.ColumnRepo.GetByBoardID(board.ID)
+	must(err)
+	labels := &models.CustomField{BoardID: board.ID, Name: "Labels", Type: models.FieldTypeMultiSelect, Options: []string{"Bug", "Feature", "Chore"}}
+	must(svc.FieldRepo.Create(labels))
+	points := &models.CustomField{BoardID: board.ID, Name: "Points", Type: models.FieldTypeNumber}
+	must(svc.FieldRepo.Create(points))
+	blocked := &models.CustomField{BoardID: board.ID, Name: "Blocked", Type: models.FieldTypeCheckbox}
+	must(svc.FieldRepo.Create(blocked))
+	size := &models.CustomField{BoardID: board.ID, Name: "Size", Type: models.FieldTypeSingleSelect, Options: []string{"S", "M"}}
+	must(svc.FieldRepo.Create(size))
+
+	card := &models.Card{ColumnID: columns[0].ID, Title: "Fix login"}
+	must(svc.CardRepo.Create(card))
+	// Stored values from older versions or direct edits come back normalised
+	must(svc.FieldRepo.SetValue(card.ID, labels.ID, `["Feature","Bug"]`))
+	must(svc.FieldRepo.SetValue(card.ID, points.ID, "3.50"))
+	must(svc.FieldRepo.SetValue(card.ID, blocked.ID, "true"))
+	// An option that was since removed from the field
+	must(svc.FieldRepo.SetValue(card.ID, size.ID, "XL"))
+	other := &models.Card{ColumnID: columns[0].ID, Title: "Write docs"}
+	must(svc.CardRepo.Create(other))
+
+	got, err := svc.GetCardJSON(board.ID, card.ID)
+	must(err)
+	data, err := json.Marshal(got.Fields)
+	must(err)
+	want := fmt.Sprintf(`[{"id":%d,"name":"Labels","type":"multi_select","value":["Bug","Feature"]},`+
+		`{"id":%d,"name":"Points","type":"number","value":3.5},`+
+		`{"id":%d,"name":"Blocked","type":"checkbox","value":true}]`, labels.ID, points.ID, blocked.ID)
+	if string(data) != want {
+		t.Errorf("fields = %s, want %s", data, want)
+	}
+	if got.Column != columns[0].Name || len(got.Assignees) != 0 {
+		t.Errorf("card = %+v", got)
+	}
+
+	cards, err := svc.ListCardsJSON(board.ID, CardFilter{FieldID: labels.ID, Value: "bug"})
+	must(err)
+	if len(cards) != 1 || cards[0].ID != card.ID {
+		t.Errorf("filtered cards = %+v, want only %q", cards, card.Title)
+	}
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"krizzy/internal/models"
+)
+
+// ExpandPlaceholders substitutes the template placeholders in text with values
+// for now. Unknown placeholders are left untouched.
+func ExpandPlaceholders(text string, now time.Time) string {
+	year, week := now.ISOWeek()
+	return strings.NewReplacer(
+		"{{date}}", now.Format("2006-01-02"),
+		"{{time}}", now.Format("15:04"),
+		"{{datetime}}", now.Format("2006-01-02 15:04"),
+		"{{weekday}}", now.Format("Monday"),
+		"{{week}}", fmt.Sprintf("%d-W%02d", year, week),
+		"{{month}}", now.Format("January"),
+		"{{year}}", now.Format("2006"),
+	).Replace(text)
+}
+
+// TemplateFromCard builds a template from a card's current title,
+// description, checklist, assignees and custom field values. Checklist items
+// are saved unticked.
+func (s *KanbanService) TemplateFromCard(cardID, boardID int64, name string) (*models.CardTemplate, error) {
+	card, err := s.GetCardWithDetails(cardID)
+	if err != nil {
+		return nil, err
+	}
+
+	tpl := &models.CardTemplate{
+		BoardID:     boardID,
+		Name:        name,
+		Title:       card.Title,
+		Description: card.Description,
+		FieldValues: card.FieldValues,
+	}
+	for _, item := range card.Checklist {
+		tpl.Checklist = append(tpl.Checklist, item.Content)
+	}
+	for _, person := range card.Assignees {
+		tpl.AssigneeIDs = append(tpl.AssigneeIDs, person.ID)
+	}
+
+	if err := s.TemplateRepo.Create(tpl); err != nil {
+		return nil, err
+	}
+	return tpl, nil
+}
+
+// CreateCardFromTemplate adds a card built from tpl to the end of the given
+// column and swimlane. Assignees and field values that no longer exist on the
+// board are skipped, so older templates keep working after people or fields
+// are removed.
+func (s *KanbanService) CreateCardFromTemplate(tpl *models.CardTemplate, columnID int64, swimlaneID *int64, now time.Time) (*models.Card, error) {
+	card := &models.Card{
+		ColumnID:    columnID,
+		SwimlaneID:  swimlaneID,
+		Title:       ExpandPlaceholders(tpl.Title, now),
+		Description: ExpandPlaceholders(tpl.Description, now),
+	}
+	if strings.TrimSpace(card.Title) == "" {
+		card.Title = tpl.Name
+	}
+
+	err := s.inTx(func(tx *KanbanService) error {
+		if err := tx.CardRepo.Create(card); err != nil {
+			return err
+		}
+
+		for _, content := range tpl.Checklist {
+			item := &models.ChecklistItem{
+				CardID:  card.ID,
+				Content: ExpandPlaceholders(content, now),
+			}
+			if err := tx.ChecklistRepo.Create(item); err != nil {
+				return err
+			}
+		}
+
+		var assigneeIDs []int64
+		for _, id := range tpl.AssigneeIDs {
+			if _, err := tx.PersonInBoard(tpl.BoardID, id); err == nil {
+				assigneeIDs = append(assigneeIDs, id)
+			}
+		}
+		if len(assigneeIDs) > 0 {
+			if err := tx.PersonRepo.SetCardAssignees(card.ID, assigneeIDs); err != nil {
+				return err
+			}
+		}
+
+		for fieldID, stored := range tpl.FieldValues {
+			field, err := tx.FieldInBoard(tpl.BoardID, fieldID)
+			if err != nil {
+				continue
+			}
+			// Select options may have changed since the template was saved
+			value, ok := AdaptFieldValue(field, stored)
+			if !ok {
+				continue
+			}
+			if err := tx.FieldRepo.SetValue(card.ID, fieldID, value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return card, nil
+}
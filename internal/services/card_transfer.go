@@ -0,0 +1,269 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"krizzy/internal/models"
+)
+
+// ErrSameBoard is returned when a card is sent to the board it is already on
+var ErrSameBoard = errors.New("card is already on this board")
+
+// DuplicateCard copies a card with its description, checklist, comments,
+// assignees and custom field values to the end of its own cell
+func (s *KanbanService) DuplicateCard(boardID, cardID int64) (*models.Card, error) {
+	card, err := s.GetCardWithDetails(cardID)
+	if err != nil {
+		return nil, err
+	}
+	return copyCard(card, s, boardID, s, boardID, card.ColumnID, card.SwimlaneID, card.Title+" (copy)")
+}
+
+// MoveCardToBoard moves a card with its details into a column on another
+// board, which may use a different storage backend. Assignees are matched to
+// the destination board's people by name, and people it lacks are added.
+// Custom field values carry over to fields with the same name and type.
+//
+// Boards in the same database move the card in one transaction. Otherwise
+// the card is copied before the original is deleted, so a failure part way
+// leaves the card where it was rather than losing it.
+func (bm *BoardManager) MoveCardToBoard(srcBoardID, cardID, dstBoardID, dstColumnID int64) (*models.Card, error) {
+	if srcBoardID == dstBoardID {
+		return nil, ErrSameBoard
+	}
+
+	src, err := bm.GetServiceForBoard(srcBoardID)
+	if err != nil {
+		return nil, err
+	}
+	dst, err := bm.GetServiceForBoard(dstBoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := src.CardInBoard(srcBoardID, cardID); err != nil {
+		return nil, err
+	}
+	if _, err := dst.ColumnInBoard(dstBoardID, dstColumnID); err != nil {
+		return nil, err
+	}
+
+	shared, err := bm.shareDatabase(srcBoardID, dstBoardID)
+	if err != nil {
+		return nil, err
+	}
+	if shared {
+		var moved *models.Card
+		err := src.inTx(func(tx *KanbanService) error {
+			card, err := tx.GetCardWithDetails(cardID)
+			if err != nil {
+				return err
+			}
+			if moved, err = copyCard(card, tx, srcBoardID, tx, dstBoardID, dstColumnID, nil, card.Title); err != nil {
+				return err
+			}
+			return tx.CardRepo.Delete(cardID)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return moved, nil
+	}
+
+	card, err := src.GetCardWithDetails(cardID)
+	if err != nil {
+		return nil, err
+	}
+
+	moved, err := copyCard(card, src, srcBoardID, dst, dstBoardID, dstColumnID, nil, card.Title)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := src.CardRepo.Delete(cardID); err != nil {
+		return nil, fmt.Errorf("card copied to board %d but not removed from board %d: %w", dstBoardID, srcBoardID, err)
+	}
+	return moved, nil
+}
+
+// shareDatabase reports whether two boards are stored in the same database:
+// both in the local SQLite database, or both in memory. Postgres boards and
+// board files each have a database of their own.
+func (bm *BoardManager) shareDatabase(boardID, otherID int64) (bool, error) {
+	board, err := bm.boardRepo.GetByID(boardID)
+	if err != nil {
+		return false, err
+	}
+	other, err := bm.boardRepo.GetByID(otherID)
+	if err != nil {
+		return false, err
+	}
+	switch board.DbType {
+	case "local", "memory":
+		return board.DbType == other.DbType, nil
+	}
+	return false, nil
+}
+
+// copyCard creates a copy of a fully loaded card in a cell on the destination
+// board, in one transaction there. Completion follows the destination
+// column's done setting.
+func copyCard(card *models.Card, src *KanbanService, srcBoardID int64, dst *KanbanService, dstBoardID, columnID int64, swimlaneID *int64, title string) (*models.Card, error) {
+	column, err := dst.ColumnRepo.GetByID(columnID)
+	if err != nil {
+		return nil, err
+	}
+
+	copied := &models.Card{
+		ColumnID:    columnID,
+		SwimlaneID:  swimlaneID,
+		Title:       title,
+		Description: card.Description,
+	}
+	err = dst.inTx(func(tx *KanbanService) error {
+		if err := tx.CardRepo.Create(copied); err != nil {
+			return err
+		}
+
+		if column.IsDoneColumn {
+			completedAt := time.Now()
+			if card.CompletedAt != nil {
+				completedAt = *card.CompletedAt
+			}
+			copied.CompletedAt = &completedAt
+			copied.Resolution = column.Resolution
+			if err := tx.CardRepo.Update(copied); err != nil {
+				return err
+			}
+		}
+
+		for _, item := range card.Checklist {
+			if err := tx.ChecklistRepo.Create(&models.ChecklistItem{
+				CardID:      copied.ID,
+				Checklist:   item.Checklist,
+				Content:     item.Content,
+				IsCompleted: item.IsCompleted,
+			}); err != nil {
+				return err
+			}
+		}
+
+		for _, link := range card.Links {
+			if err := tx.LinkRepo.Create(&models.CardLink{
+				CardID: copied.ID,
+				Title:  link.Title,
+				URL:    link.URL,
+			}); err != nil {
+				return err
+			}
+		}
+
+		for _, comment := range card.Comments {
+			if err := tx.CommentRepo.Create(&models.Comment{
+				CardID:    copied.ID,
+				Content:   comment.Content,
+				CreatedAt: comment.CreatedAt,
+			}); err != nil {
+				return err
+			}
+		}
+
+		assigneeIDs, err := mapAssignees(card.Assignees, tx, dstBoardID, srcBoardID == dstBoardID)
+		if err != nil {
+			return err
+		}
+		if len(assigneeIDs) > 0 {
+			if err := tx.PersonRepo.SetCardAssignees(copied.ID, assigneeIDs); err != nil {
+				return err
+			}
+		}
+
+		return copyFieldValues(card, src, srcBoardID, tx, dstBoardID, copied.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return copied, nil
+}
+
+// mapAssignees returns the destination board's IDs for a card's assignees.
+// People are matched by name, ignoring case, and added when missing.
+func mapAssignees(assignees []models.Person, dst *KanbanService, dstBoardID int64, sameBoard bool) ([]int64, error) {
+	var ids []int64
+	if sameBoard {
+		for _, person := range assignees {
+			ids = append(ids, person.ID)
+		}
+		return ids, nil
+	}
+	if len(assignees) == 0 {
+		return nil, nil
+	}
+
+	people, err := dst.PersonRepo.GetByBoardID(dstBoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, person := range assignees {
+		var id int64
+		for _, existing := range people {
+			if strings.EqualFold(existing.Name, person.Name) {
+				id = existing.ID
+				break
+			}
+		}
+		if id == 0 {
+			added := &models.Person{BoardID: dstBoardID, Name: person.Name, Color: person.Color}
+			if err := dst.PersonRepo.Create(added); err != nil {
+				return nil, err
+			}
+			people = append(people, *added)
+			id = added.ID
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// copyFieldValues stores a card's custom field values on its copy. On another
+// board a value goes to the field with the same name and type, if any.
+func copyFieldValues(card *models.Card, src *KanbanService, srcBoardID int64, dst *KanbanService, dstBoardID, copiedID int64) error {
+	if len(card.FieldValues) == 0 {
+		return nil
+	}
+
+	srcFields, err := src.FieldRepo.GetByBoardID(srcBoardID)
+	if err != nil {
+		return err
+	}
+	dstFields := srcFields
+	if dst != src || dstBoardID != srcBoardID {
+		if dstFields, err = dst.FieldRepo.GetByBoardID(dstBoardID); err != nil {
+			return err
+		}
+	}
+
+	for _, srcField := range srcFields {
+		stored, ok := card.FieldValues[srcField.ID]
+		if !ok {
+			continue
+		}
+		for i := range dstFields {
+			dstField := &dstFields[i]
+			if dstField.Type != srcField.Type || !strings.EqualFold(dstField.Name, srcField.Name) {
+				continue
+			}
+			if value, ok := AdaptFieldValue(dstField, stored); ok {
+				if err := dst.FieldRepo.SetValue(copiedID, dstField.ID, value); err != nil {
+					return err
+				}
+			}
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"testing"
+
+	"krizzy/internal/models"
+	"krizzy/internal/repository"
+)
+
+// TestConcurrentCardMoves has many clients drag cards around one board at
+// once, each working from the board as it last loaded it, the way browsers
+// do. Every move must go through and the board must stay consistent. The
+// handlers package runs it again with half the moves over HTTP.
+func TestConcurrentCardMoves(t *testing.T) {
+	if testing.Short() {
+		t.Skip("stress test")
+	}
+
+	bm, db := newTestBoardManager(t)
+
+	board, err := bm.CreateBoard("Stress", "local", nil, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc, err := bm.GetServiceForBoard(board.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	columns, err := svc.ColumnRepo.GetByBoardID(board.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const cards = 40
+	for i := range cards {
+		card := &models.Card{ColumnID: columns[i%len(columns)].ID, Title: fmt.Sprintf("Card %d", i)}
+		if err := svc.CardRepo.Create(card); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	const clients = 32
+	const moves = 25
+	var wg sync.WaitGroup
+	errs := make(chan error, clients*moves)
+	for client := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rng := rand.New(rand.NewPCG(uint64(client), 0))
+			for range moves {
+				loaded, err := svc.GetBoardWithData(board.ID)
+				if err != nil {
+					errs <- fmt.Errorf("client %d: load board: %w", client, err)
+					return
+				}
+
+				from := loaded.Columns[rng.IntN(len(loaded.Columns))]
+				if len(from.Cards) == 0 {
+					continue
+				}
+				card := from.Cards[rng.IntN(len(from.Cards))]
+				to := loaded.Columns[rng.IntN(len(loaded.Columns))]
+
+				// The top of a column is a valid drop target whatever the
+				// other clients moved since this one loaded the board
+				if err := svc.MoveCard(card.ID, to.ID, nil, 0); err != nil {
+					errs <- fmt.Errorf("client %d: move card %d: %w", client, card.ID, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	loaded, err := svc.GetBoardWithData(board.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := 0
+	for _, column := range loaded.Columns {
+		total += len(column.Cards)
+	}
+	if total != cards {
+		t.Errorf("board has %d cards after the moves, want %d", total, cards)
+	}
+
+	issues, err := repository.NewSQLiteIntegrityRepository(db.ReadWrite()).Check(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, issue := range issues {
+		t.Errorf("integrity: [%s] %s: %s", issue.Check, issue.Table, issue.Detail)
+	}
+}
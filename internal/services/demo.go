@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"krizzy/internal/models"
+	"krizzy/internal/repository"
+)
+
+// DemoService runs a public demo: every board lives in a memory store, which
+// starts with sample boards and is put back to them on an interval so
+// visitors' changes do not pile up.
+type DemoService struct {
+	bm       *BoardManager
+	store    *repository.MemoryStore
+	hub      BoardEventHub
+	interval time.Duration
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func NewDemoService(bm *BoardManager, store *repository.MemoryStore, hub BoardEventHub, interval time.Duration) *DemoService {
+	return &DemoService{
+		bm:       bm,
+		store:    store,
+		hub:      hub,
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Start seeds the sample boards and then resets them on the configured
+// interval until Close is called. An interval of zero seeds once and never
+// resets.
+func (s *DemoService) Start() error {
+	if err := s.Reset(); err != nil {
+		return err
+	}
+	if s.interval <= 0 {
+		return nil
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				if err := s.Reset(); err != nil {
+					log.Printf("demo: reset failed: %v", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+func (s *DemoService) Close() {
+	s.stopOnce.Do(func() { close(s.stop) })
+}
+
+// Reset drops all boards and seeds the sample boards again. Open boards are
+// told to reload, since the cards they show are gone.
+func (s *DemoService) Reset() error {
+	boards, err := s.bm.GetAllBoards()
+	if err != nil {
+		return err
+	}
+
+	s.store.Reset()
+	for _, board := range boards {
+		s.bm.InvalidateCache(board.ID)
+	}
+	if err := seedDemo(s.bm); err != nil {
+		return err
+	}
+
+	for _, board := range boards {
+		s.hub.Publish(BoardEvent{Type: "board.reset", BoardID: board.ID})
+	}
+	return nil
+}
+
+// seedDemo creates the sample boards
+func seedDemo(bm *BoardManager) error {
+	now := time.Now()
+
+	_, err := bm.CreateBoardInTx(context.Background(), "Product launch", "memory", nil, "", "", func(board *models.Board, svc *KanbanService) error {
+		return seedLaunchBoard(board, svc, now)
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = bm.CreateBoard("Personal", "memory", nil, "", "")
+	return err
+}
+
+func seedLaunchBoard(board *models.Board, svc *KanbanService, now time.Time) error {
+	columns := []*models.Column{
+		{BoardID: board.ID, Name: "Backlog"},
+		{BoardID: board.ID, Name: "In Progress"},
+		{BoardID: board.ID, Name: "Review"},
+		{BoardID: board.ID, Name: "Done", IsDoneColumn: true, Resolution: models.DefaultResolution},
+	}
+	for _, column := range columns {
+		if err := svc.ColumnRepo.Create(column); err != nil {
+			return err
+		}
+	}
+
+	lanes := []*models.Swimlane{
+		{BoardID: board.ID, Name: "Website"},
+		{BoardID: board.ID, Name: "Marketing"},
+	}
+	for _, lane := range lanes {
+		if err := svc.SwimlaneRepo.Create(lane); err != nil {
+			return err
+		}
+	}
+
+	people := []*models.Person{
+		{BoardID: board.ID, Name: "Ada", Color: "#E11D48"},
+		{BoardID: board.ID, Name: "Grace", Color: "#7C3AED"},
+		{BoardID: board.ID, Name: "Linus", Color: "#059669"},
+	}
+	for _, person := range people {
+		if err := svc.PersonRepo.Create(person); err != nil {
+			return err
+		}
+	}
+
+	points := &models.CustomField{BoardID: board.ID, Name: "Points", Type: models.FieldTypeNumber}
+	priority := &models.CustomField{BoardID: board.ID, Name: "Priority", Type: models.FieldTypeSingleSelect, Options: []string{"Low", "Medium", "High"}}
+	for _, field := range []*models.CustomField{points, priority} {
+		if err := svc.FieldRepo.Create(field); err != nil {
+			return err
+		}
+	}
+
+	type demoCard struct {
+		column    int
+		lane      int
+		title     string
+		desc      string
+		assignees []int
+		checklist []string
+		done      int
+		points    string
+		priority  string
+		comment   string
+		link      string
+	}
+	cards := []demoCard{
+		{column: 0, lane: 0, title: "Pricing page", desc: "Plans, FAQ and a comparison table.", assignees: []int{0}, points: "5", priority: "Medium"},
+		{column: 0, lane: 1, title: "Launch email", desc: "Announcement for the mailing list.", assignees: []int{1}, checklist: []string{"Draft", "Review", "Schedule"}, points: "2", priority: "Low"},
+		{column: 1, lane: 0, title: "New landing page", desc: "Hero, screenshots and sign-up form.", assignees: []int{0, 2}, checklist: []string{"Copy", "Design", "Build", "Analytics"}, done: 2, points: "8", priority: "High", comment: "Screenshots are in the shared folder.", link: "https://example.com/landing-mockup"},
+		{column: 1, lane: 1, title: "Press kit", desc: "Logos, screenshots and a one-page summary.", assignees: []int{1}, points: "3", priority: "Medium"},
+		{column: 2, lane: 0, title: "Docs: getting started", assignees: []int{2}, checklist: []string{"Install", "First board", "Invite people"}, done: 3, points: "3", priority: "Medium", comment: "Ready for a last read."},
+		{column: 3, lane: 1, title: "Pick a launch date", assignees: []int{0, 1, 2}, points: "1", priority: "High"},
+		{column: 0, lane: -1, title: "Collect beta feedback", desc: "Drag cards between columns and lanes, or open one to edit it."},
+	}
+	for _, c := range cards {
+		card := &models.Card{ColumnID: columns[c.column].ID, Title: c.title, Description: c.desc}
+		if c.lane >= 0 {
+			card.SwimlaneID = &lanes[c.lane].ID
+		}
+		if err := svc.CardRepo.Create(card); err != nil {
+			return err
+		}
+		if columns[c.column].IsDoneColumn {
+			completedAt := now
+			card.CompletedAt = &completedAt
+			card.Resolution = columns[c.column].Resolution
+			if err := svc.CardRepo.Update(card); err != nil {
+				return err
+			}
+		}
+
+		var assigneeIDs []int64
+		for _, i := range c.assignees {
+			assigneeIDs = append(assigneeIDs, people[i].ID)
+		}
+		if err := svc.PersonRepo.SetCardAssignees(card.ID, assigneeIDs); err != nil {
+			return err
+		}
+
+		for i, content := range c.checklist {
+			item := &models.ChecklistItem{CardID: card.ID, Content: content, IsCompleted: i < c.done}
+			if err := svc.ChecklistRepo.Create(item); err != nil {
+				return err
+			}
+		}
+
+		if err := svc.FieldRepo.SetValue(card.ID, points.ID, c.points); err != nil {
+			return err
+		}
+		if err := svc.FieldRepo.SetValue(card.ID, priority.ID, c.priority); err != nil {
+			return err
+		}
+
+		if c.comment != "" {
+			if err := svc.CommentRepo.Create(&models.Comment{CardID: card.ID, Content: c.comment}); err != nil {
+				return err
+			}
+		}
+		if c.link != "" {
+			if err := svc.LinkRepo.Create(&models.CardLink{CardID: card.ID, Title: "Mockup", URL: c.link}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return svc.TemplateRepo.Create(&models.CardTemplate{
+		BoardID:     board.ID,
+		Name:        "Bug report",
+		Title:       "Bug: ",
+		Description: "Reported {{date}}.\n\nSteps to reproduce:",
+		Checklist:   []string{"Reproduce", "Fix", "Add a test"},
+	})
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"krizzy/internal/models"
+)
+
+// ErrInvalidFieldValue is returned when a submitted value does not fit the
+// custom field's type or options
+var ErrInvalidFieldValue = errors.New("invalid field value")
+
+const maxFieldTextLength = 500
+
+// NormalizeFieldValue validates the values submitted for a field and returns
+// the text to store. Blank input clears the value.
+func NormalizeFieldValue(field *models.CustomField, values []string) (string, error) {
+	var submitted []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			submitted = append(submitted, v)
+		}
+	}
+	if len(submitted) == 0 {
+		return "", nil
+	}
+	value := submitted[0]
+
+	switch field.Type {
+	case models.FieldTypeText:
+		if utf8.RuneCountInString(value) > maxFieldTextLength {
+			return "", fmt.Errorf("%w: longer than %d characters", ErrInvalidFieldValue, maxFieldTextLength)
+		}
+		return value, nil
+	case models.FieldTypeNumber:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+			return "", fmt.Errorf("%w: %q is not a number", ErrInvalidFieldValue, value)
+		}
+		return strconv.FormatFloat(n, 'f', -1, 64), nil
+	case models.FieldTypeDate:
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return "", fmt.Errorf("%w: %q is not a date", ErrInvalidFieldValue, value)
+		}
+		return value, nil
+	case models.FieldTypeSingleSelect:
+		if !slices.Contains(field.Options, value) {
+			return "", fmt.Errorf("%w: %q is not an option", ErrInvalidFieldValue, value)
+		}
+		return value, nil
+	case models.FieldTypeMultiSelect:
+		// Keep the field's option order so equal selections store equal text
+		var selected []string
+		for _, option := range field.Options {
+			if slices.Contains(submitted, option) {
+				selected = append(selected, option)
+			}
+		}
+		if len(selected) != len(uniqueStrings(submitted)) {
+			return "", fmt.Errorf("%w: unknown option", ErrInvalidFieldValue)
+		}
+		data, err := json.Marshal(selected)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	case models.FieldTypeCheckbox:
+		if value == "true" || value == "on" {
+			return "true", nil
+		}
+		return "", nil
+	}
+
+	return "", fmt.Errorf("%w: unknown field type %q", ErrInvalidFieldValue, field.Type)
+}
+
+// IsFieldType reports whether t is one of the supported custom field types
+func IsFieldType(t string) bool {
+	return slices.Contains(models.FieldTypes, t)
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var unique []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
+
+// AdaptFieldValue fits a value stored for one field to another field, such as
+// the same field after its options changed or a like-named field on another
+// board. Select choices the field no longer offers are dropped. It reports
+// false when nothing usable is left.
+func AdaptFieldValue(field *models.CustomField, stored string) (string, bool) {
+	values := models.FieldValueList(field.Type, stored)
+	if field.HasOptions() {
+		values = slices.DeleteFunc(values, func(v string) bool {
+			return !slices.Contains(field.Options, v)
+		})
+	}
+	value, err := NormalizeFieldValue(field, values)
+	if err != nil || value == "" {
+		return "", false
+	}
+	return value, true
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Import job states
+const (
+	ImportRunning   = "running"
+	ImportDone      = "done"
+	ImportFailed    = "failed"
+	ImportCancelled = "cancelled"
+)
+
+// importJobRetention is how long a finished job stays around for clients
+// that reconnect to its progress stream
+const importJobRetention = 15 * time.Minute
+
+// ImportJob is a snapshot of a background import
+type ImportJob struct {
+	ID        string `json:"id"`
+	Status    string `json:"status"`
+	Progress  int    `json:"progress"`
+	BoardName string `json:"board_name"`
+	BoardID   int64  `json:"board_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Finished reports whether the job has stopped, whatever the outcome
+func (j ImportJob) Finished() bool {
+	return j.Status != ImportRunning
+}
+
+type importJob struct {
+	ImportJob
+	cancel      context.CancelFunc
+	finishedAt  time.Time
+	subscribers map[chan ImportJob]struct{}
+}
+
+// StartImport parses an export and starts writing its board in the
+// background. Parse errors are returned straight away; later failures end
+// up in the job.
+func (s *ImportService) StartImport(req ImportRequest) (ImportJob, error) {
+	plan, name, err := s.prepare(req)
+	if err != nil {
+		return ImportJob{}, err
+	}
+
+	id, err := newImportJobID()
+	if err != nil {
+		return ImportJob{}, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &importJob{
+		ImportJob:   ImportJob{ID: id, Status: ImportRunning, BoardName: name},
+		cancel:      cancel,
+		subscribers: make(map[chan ImportJob]struct{}),
+	}
+
+	s.mu.Lock()
+	s.pruneJobs(time.Now())
+	s.jobs[id] = job
+	snapshot := job.ImportJob
+	s.mu.Unlock()
+
+	go s.runImport(ctx, job, plan, name, req)
+	return snapshot, nil
+}
+
+func (s *ImportService) runImport(ctx context.Context, job *importJob, plan *ImportPlan, name string, req ImportRequest) {
+	defer job.cancel()
+
+	steps := importSteps(plan)
+	board, err := s.write(ctx, plan, name, req, func(done int) {
+		percent := 100
+		if steps > 0 {
+			// Hold back the last percent until the transaction commits
+			percent = min(done*100/steps, 99)
+		}
+		s.updateJob(job, func(j *ImportJob) bool {
+			if percent == j.Progress {
+				return false
+			}
+			j.Progress = percent
+			return true
+		})
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case err == nil:
+		job.Status = ImportDone
+		job.Progress = 100
+		job.BoardID = board.ID
+	case ctx.Err() != nil:
+		job.Status = ImportCancelled
+	default:
+		log.Printf("import %s failed: %v", job.ID, err)
+		job.Status = ImportFailed
+		job.Error = err.Error()
+	}
+	job.finishedAt = time.Now()
+	for ch := range job.subscribers {
+		select {
+		case ch <- job.ImportJob:
+		default:
+		}
+		close(ch)
+	}
+	job.subscribers = nil
+}
+
+// updateJob applies change to a running job and tells subscribers when it
+// reports a change. Slow subscribers miss intermediate updates.
+func (s *ImportService) updateJob(job *importJob, change func(j *ImportJob) bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !change(&job.ImportJob) {
+		return
+	}
+	for ch := range job.subscribers {
+		select {
+		case ch <- job.ImportJob:
+		default:
+		}
+	}
+}
+
+// Job returns a snapshot of an import job
+func (s *ImportService) Job(id string) (ImportJob, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return ImportJob{}, false
+	}
+	return job.ImportJob, true
+}
+
+// SubscribeJob returns a job's current state and a channel of its later
+// updates. The channel is closed when the job finishes, after which Job has
+// the final state; it is nil for jobs that already have.
+func (s *ImportService) SubscribeJob(id string) (ImportJob, chan ImportJob, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return ImportJob{}, nil, false
+	}
+	if job.Finished() {
+		return job.ImportJob, nil, true
+	}
+	ch := make(chan ImportJob, 16)
+	job.subscribers[ch] = struct{}{}
+	return job.ImportJob, ch, true
+}
+
+// UnsubscribeJob stops updates on ch. It is safe to call after the job has
+// finished and closed the channel.
+func (s *ImportService) UnsubscribeJob(id string, ch chan ImportJob) {
+	if ch == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok || job.subscribers == nil {
+		return
+	}
+	if _, ok := job.subscribers[ch]; ok {
+		delete(job.subscribers, ch)
+		close(ch)
+	}
+}
+
+// CancelImport stops a running import and rolls back what it wrote. It
+// reports false when the job is unknown or already finished.
+func (s *ImportService) CancelImport(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok || job.Finished() {
+		return false
+	}
+	job.cancel()
+	return true
+}
+
+// pruneJobs forgets jobs that finished more than importJobRetention ago.
+// The caller holds s.mu.
+func (s *ImportService) pruneJobs(now time.Time) {
+	for id, job := range s.jobs {
+		if job.Finished() && now.Sub(job.finishedAt) > importJobRetention {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+func newImportJobID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to create import job ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,403 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"krizzy/internal/models"
+)
+
+// Importer reads a board export from another tool. Importers only parse;
+// ImportService writes the resulting plan, so every format gets the same dry
+// run and the same board creation rules.
+type Importer interface {
+	// Format is the short name used in forms and routes, e.g. "trello"
+	Format() string
+	// Label is shown to users, e.g. "Trello JSON"
+	Label() string
+	// Detect reports whether data looks like this importer's format
+	Detect(data []byte) bool
+	// Plan parses data into the board it would create
+	Plan(data []byte) (*ImportPlan, error)
+}
+
+// ImportPlan is a parsed export in krizzy's terms. Warnings describe source
+// data the import drops or changes.
+type ImportPlan struct {
+	BoardName string
+	Columns   []ImportColumn
+	People    []string
+	// Fields are custom fields to create, such as Trello labels
+	Fields   []models.CustomField
+	Warnings []string
+}
+
+type ImportColumn struct {
+	Source string
+	Name   string
+	IsDone bool
+	// Resolution labels a done column's cards, models.DefaultResolution when
+	// empty
+	Resolution string
+	Cards      []ImportCard
+}
+
+type ImportCard struct {
+	Title       string
+	Description string
+	// CompletedAt and Resolution only apply in done columns. Cards without a
+	// completion date are completed at import time.
+	CompletedAt *time.Time
+	Resolution  string
+	Assignees   []string
+	Checklist   []models.ChecklistItem
+	Comments    []models.Comment
+	Links       []models.CardLink
+	// FieldValues are values keyed by plan field name, checked like a form
+	// submission once the field exists
+	FieldValues map[string][]string
+	// Archived cards are imported and then archived
+	Archived bool
+}
+
+// ImportReport is what a dry run shows before anything is written
+type ImportReport struct {
+	Format         string
+	BoardName      string
+	Columns        []ImportColumnReport
+	Cards          int
+	People         []string
+	Comments       int
+	ChecklistItems int
+	Links          int
+	Archived       int
+	Fields         []string
+	Warnings       []string
+}
+
+// ImportColumnReport is one source list or status and the column it becomes
+type ImportColumnReport struct {
+	Source string
+	Name   string
+	IsDone bool
+	Cards  int
+}
+
+// ImporterInfo describes a registered format for pickers
+type ImporterInfo struct {
+	Format string
+	Label  string
+}
+
+// MaxImportSize caps uploaded exports
+const MaxImportSize = 25 << 20
+
+// ImportService holds the registered importers and runs imports as
+// background jobs
+type ImportService struct {
+	bm        *BoardManager
+	importers []Importer
+	mu        sync.Mutex
+	jobs      map[string]*importJob
+}
+
+// NewImportService returns a service with the built-in Trello and Jira
+// importers registered
+func NewImportService(bm *BoardManager) *ImportService {
+	s := &ImportService{bm: bm, jobs: make(map[string]*importJob)}
+	s.Register(trelloImporter{})
+	s.Register(jiraImporter{})
+	return s
+}
+
+// Register adds an importer. Detection tries importers in registration order.
+func (s *ImportService) Register(importer Importer) {
+	s.importers = append(s.importers, importer)
+}
+
+// Formats lists the registered importers in registration order
+func (s *ImportService) Formats() []ImporterInfo {
+	formats := make([]ImporterInfo, 0, len(s.importers))
+	for _, importer := range s.importers {
+		formats = append(formats, ImporterInfo{Format: importer.Format(), Label: importer.Label()})
+	}
+	return formats
+}
+
+// ReadExport reads an uploaded export, rejecting files over MaxImportSize
+func ReadExport(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxImportSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export: %w", err)
+	}
+	if len(data) > MaxImportSize {
+		return nil, fmt.Errorf("export is larger than %d MB", MaxImportSize>>20)
+	}
+	return data, nil
+}
+
+// importerFor returns the importer for format, or detects one when format is
+// empty or "auto"
+func (s *ImportService) importerFor(data []byte, format string) (Importer, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" || format == "auto" {
+		for _, importer := range s.importers {
+			if importer.Detect(data) {
+				return importer, nil
+			}
+		}
+		return nil, fmt.Errorf("unrecognised export format")
+	}
+
+	for _, importer := range s.importers {
+		if importer.Format() == format {
+			return importer, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown import format %q", format)
+}
+
+func (s *ImportService) plan(data []byte, format string) (Importer, *ImportPlan, error) {
+	importer, err := s.importerFor(data, format)
+	if err != nil {
+		return nil, nil, err
+	}
+	plan, err := importer.Plan(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(plan.Columns) == 0 {
+		return nil, nil, fmt.Errorf("no columns found in %s export", importer.Label())
+	}
+	return importer, plan, nil
+}
+
+// DryRun parses an export and reports what importing it would create,
+// without writing anything
+func (s *ImportService) DryRun(data []byte, format string) (*ImportReport, error) {
+	importer, plan, err := s.plan(data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{
+		Format:    importer.Label(),
+		BoardName: plan.BoardName,
+		People:    plan.People,
+		Warnings:  plan.Warnings,
+	}
+	for _, column := range plan.Columns {
+		report.Columns = append(report.Columns, ImportColumnReport{
+			Source: column.Source,
+			Name:   column.Name,
+			IsDone: column.IsDone,
+			Cards:  len(column.Cards),
+		})
+		report.Cards += len(column.Cards)
+		for _, card := range column.Cards {
+			report.Comments += len(card.Comments)
+			report.ChecklistItems += len(card.Checklist)
+			report.Links += len(card.Links)
+			if card.Archived {
+				report.Archived++
+			}
+		}
+	}
+	for _, field := range plan.Fields {
+		report.Fields = append(report.Fields, field.Name)
+	}
+	return report, nil
+}
+
+// ImportRequest is an uploaded export and where to create its board
+type ImportRequest struct {
+	Data           []byte
+	Format         string
+	BoardName      string
+	DbType         string
+	PgConnectionID *int64
+	PgDatabaseName string
+	PgSchemaName   string
+}
+
+// prepare parses an import request and settles the board name, so bad
+// uploads are rejected before any job starts
+func (s *ImportService) prepare(req ImportRequest) (*ImportPlan, string, error) {
+	_, plan, err := s.plan(req.Data, req.Format)
+	if err != nil {
+		return nil, "", err
+	}
+
+	importName := strings.TrimSpace(req.BoardName)
+	if importName == "" {
+		importName = strings.TrimSpace(plan.BoardName)
+	}
+	if importName == "" {
+		return nil, "", fmt.Errorf("board name is required")
+	}
+	return plan, importName, nil
+}
+
+// importSteps is the number of progress steps writing plan takes
+func importSteps(plan *ImportPlan) int {
+	steps := len(plan.People) + len(plan.Fields) + len(plan.Columns)
+	for _, column := range plan.Columns {
+		steps += len(column.Cards)
+	}
+	return steps
+}
+
+// write creates the planned board in one transaction on its storage.
+// progress is called after each person, field, column and card with the
+// number of steps done. Cancelling ctx stops the import and rolls it back.
+func (s *ImportService) write(ctx context.Context, plan *ImportPlan, name string, req ImportRequest, progress func(done int)) (*models.Board, error) {
+	return s.bm.CreateBoardInTx(ctx, name, req.DbType, req.PgConnectionID, req.PgDatabaseName, req.PgSchemaName, func(board *models.Board, svc *KanbanService) error {
+		done := 0
+		step := func() error {
+			done++
+			progress(done)
+			return ctx.Err()
+		}
+
+		personIDs := make(map[string]int64, len(plan.People))
+		for _, personName := range plan.People {
+			person := &models.Person{BoardID: board.ID, Name: personName}
+			if err := svc.PersonRepo.Create(person); err != nil {
+				return fmt.Errorf("failed to import person %q: %w", personName, err)
+			}
+			personIDs[strings.ToLower(personName)] = person.ID
+			if err := step(); err != nil {
+				return err
+			}
+		}
+
+		fields := make(map[string]*models.CustomField, len(plan.Fields))
+		for _, planned := range plan.Fields {
+			field := planned
+			field.BoardID = board.ID
+			if err := svc.FieldRepo.Create(&field); err != nil {
+				return fmt.Errorf("failed to import field %q: %w", planned.Name, err)
+			}
+			fields[field.Name] = &field
+			if err := step(); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		for _, planned := range plan.Columns {
+			column := &models.Column{
+				BoardID:      board.ID,
+				Name:         planned.Name,
+				IsDoneColumn: planned.IsDone,
+			}
+			if column.IsDoneColumn {
+				column.Resolution = planned.Resolution
+				if column.Resolution == "" {
+					column.Resolution = models.DefaultResolution
+				}
+			}
+			if err := svc.ColumnRepo.Create(column); err != nil {
+				return fmt.Errorf("failed to import column %q: %w", planned.Name, err)
+			}
+			if err := step(); err != nil {
+				return err
+			}
+
+			for _, card := range planned.Cards {
+				if err := importCard(svc, column, card, personIDs, fields, now); err != nil {
+					return fmt.Errorf("failed to import card %q: %w", card.Title, err)
+				}
+				if err := step(); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func importCard(svc *KanbanService, column *models.Column, planned ImportCard, personIDs map[string]int64, fields map[string]*models.CustomField, now time.Time) error {
+	card := &models.Card{
+		ColumnID:    column.ID,
+		Title:       planned.Title,
+		Description: planned.Description,
+	}
+	if err := svc.CardRepo.Create(card); err != nil {
+		return err
+	}
+
+	if column.IsDoneColumn {
+		completedAt := now
+		if planned.CompletedAt != nil {
+			completedAt = *planned.CompletedAt
+		}
+		card.CompletedAt = &completedAt
+		card.Resolution = column.Resolution
+		if planned.Resolution != "" {
+			card.Resolution = planned.Resolution
+		}
+		if err := svc.CardRepo.Update(card); err != nil {
+			return err
+		}
+	}
+
+	for _, item := range planned.Checklist {
+		item.CardID = card.ID
+		if err := svc.ChecklistRepo.Create(&item); err != nil {
+			return err
+		}
+	}
+
+	assigneeIDs := make([]int64, 0, len(planned.Assignees))
+	for _, name := range planned.Assignees {
+		if personID, ok := personIDs[strings.ToLower(name)]; ok {
+			assigneeIDs = append(assigneeIDs, personID)
+		}
+	}
+	if len(assigneeIDs) > 0 {
+		if err := svc.PersonRepo.SetCardAssignees(card.ID, assigneeIDs); err != nil {
+			return err
+		}
+	}
+
+	for _, comment := range planned.Comments {
+		comment.CardID = card.ID
+		if err := svc.CommentRepo.Create(&comment); err != nil {
+			return err
+		}
+	}
+
+	for _, link := range planned.Links {
+		link.CardID = card.ID
+		if err := svc.LinkRepo.Create(&link); err != nil {
+			return err
+		}
+	}
+
+	for name, values := range planned.FieldValues {
+		field, ok := fields[name]
+		if !ok {
+			continue
+		}
+		value, err := NormalizeFieldValue(field, values)
+		if err != nil || value == "" {
+			continue
+		}
+		if err := svc.FieldRepo.SetValue(card.ID, field.ID, value); err != nil {
+			return err
+		}
+	}
+
+	if planned.Archived {
+		if err := svc.CardRepo.Archive(card.ID, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
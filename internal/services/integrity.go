@@ -0,0 +1,100 @@
+package services
+
+import (
+	"fmt"
+	"io"
+
+	"krizzy/internal/models"
+	"krizzy/internal/repository"
+)
+
+// DatabaseIntegrity is what an integrity check found in one database
+type DatabaseIntegrity struct {
+	Name   string
+	Issues []models.IntegrityIssue
+	Err    error // the database could not be opened or checked
+}
+
+// IntegrityReport covers the local database and every board kept elsewhere
+type IntegrityReport struct {
+	Repaired  bool
+	Databases []DatabaseIntegrity
+}
+
+// WriteText writes the report as plain text, one line per issue
+func (r *IntegrityReport) WriteText(w io.Writer) {
+	for _, db := range r.Databases {
+		switch {
+		case db.Err != nil:
+			fmt.Fprintf(w, "%s: check failed: %v\n", db.Name, db.Err)
+			continue
+		case len(db.Issues) == 0:
+			fmt.Fprintf(w, "%s: ok\n", db.Name)
+			continue
+		}
+
+		verb := "found"
+		if r.Repaired {
+			verb = "repaired"
+		}
+		fmt.Fprintf(w, "%s: %d issues %s\n", db.Name, len(db.Issues), verb)
+		for _, issue := range db.Issues {
+			fmt.Fprintf(w, "  [%s] %s: %s\n", issue.Check, issue.Table, issue.Detail)
+		}
+	}
+}
+
+// CheckIntegrity checks the local database and the database of every file
+// and Postgres board, opening boards that are not open yet. With repair set
+// each database is fixed in a transaction of its own; one that fails is
+// rolled back and reported without stopping the others. Demo boards are
+// skipped; they are reset to the sample data anyway.
+func (bm *BoardManager) CheckIntegrity(repair bool) *IntegrityReport {
+	report := &IntegrityReport{Repaired: repair}
+
+	if bm.localDB != nil {
+		issues, err := repository.NewSQLiteIntegrityRepository(bm.localDB.ReadWrite()).Check(repair)
+		report.Databases = append(report.Databases, DatabaseIntegrity{Name: "local database", Issues: issues, Err: err})
+	}
+
+	boards, err := bm.GetAllBoards()
+	if err != nil {
+		report.Databases = append(report.Databases, DatabaseIntegrity{Name: "board list", Err: err})
+		return report
+	}
+
+	for _, board := range boards {
+		if board.DbType != "file" && board.DbType != "postgres" {
+			continue
+		}
+		name := fmt.Sprintf("board %d (%s, %s)", board.ID, board.Name, board.DbType)
+		checker, err := bm.integrityRepo(&board)
+		if err != nil {
+			report.Databases = append(report.Databases, DatabaseIntegrity{Name: name, Err: err})
+			continue
+		}
+		issues, err := checker.Check(repair)
+		report.Databases = append(report.Databases, DatabaseIntegrity{Name: name, Issues: issues, Err: err})
+	}
+
+	return report
+}
+
+// integrityRepo opens a file or Postgres board and returns a checker on its
+// database
+func (bm *BoardManager) integrityRepo(board *models.Board) (repository.IntegrityRepository, error) {
+	if _, err := bm.GetServiceForBoard(board.ID); err != nil {
+		return nil, err
+	}
+
+	bm.mu.RLock()
+	defer bm.mu.RUnlock()
+	if board.DbType == "file" {
+		if db, ok := bm.fileDBs[board.ID]; ok {
+			return repository.NewSQLiteIntegrityRepository(db.ReadWrite()), nil
+		}
+	} else if db, ok := bm.pgDBs[board.ID]; ok {
+		return repository.NewPgIntegrityRepository(db.DB()), nil
+	}
+	return nil, fmt.Errorf("%w: board %d is not connected", ErrBoardUnavailable, board.ID)
+}
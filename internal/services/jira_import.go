@@ -0,0 +1,422 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"time"
+
+	"krizzy/internal/models"
+)
+
+// jiraImporter reads Jira's CSV issue export
+type jiraImporter struct{}
+
+func (jiraImporter) Format() string { return "jira" }
+
+func (jiraImporter) Label() string { return "Jira CSV" }
+
+type jiraIssue struct {
+	ID          string
+	Key         string
+	Summary     string
+	Description string
+	Type        string
+	Status      string
+	Category    string
+	Assignee    string
+	Reporter    string
+	Resolution  string
+	ResolvedAt  *time.Time
+	ParentID    string
+	DueDate     string
+	Priority    string
+	Labels      []string
+	Attachments []string
+	Comments    []models.Comment
+	Subtasks    []*jiraIssue
+}
+
+func (i *jiraIssue) isSubtask() bool {
+	normalized := strings.ToLower(strings.ReplaceAll(i.Type, "-", ""))
+	return normalized == "subtask" && i.ParentID != ""
+}
+
+func (i *jiraIssue) isResolved() bool {
+	return i.ResolvedAt != nil || i.Resolution != "" && !strings.EqualFold(i.Resolution, "unresolved")
+}
+
+type jiraStatus struct {
+	Name   string
+	IsDone bool
+	Issues []*jiraIssue
+}
+
+// jiraPlan is a parsed export: top-level issues grouped by status in column
+// order, with sub-tasks attached to their parents
+type jiraPlan struct {
+	ProjectName string
+	Statuses    []*jiraStatus
+	People      []string
+	Subtasks    int
+	Comments    int
+	Skipped     int
+	Orphans     int
+}
+
+// jiraDateLayouts covers Jira's default export format and the ISO formats
+// used when a site changes its date settings
+var jiraDateLayouts = []string{
+	"02/Jan/06 3:04 PM",
+	"2/Jan/06 3:04 PM",
+	"02/Jan/2006 3:04 PM",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04:05.000-0700",
+	time.RFC3339,
+	"2006-01-02",
+}
+
+func parseJiraDate(value string) *time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	for _, layout := range jiraDateLayouts {
+		if parsed, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return &parsed
+		}
+	}
+	return nil
+}
+
+// parseJiraComment reads Jira's "date;author;body" comment cells. Author
+// account IDs are dropped, as in the Trello importer.
+func parseJiraComment(value string) (models.Comment, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return models.Comment{}, false
+	}
+	parts := strings.SplitN(value, ";", 3)
+	if len(parts) == 3 {
+		if createdAt := parseJiraDate(parts[0]); createdAt != nil {
+			body := strings.TrimSpace(parts[2])
+			if body == "" {
+				return models.Comment{}, false
+			}
+			return models.Comment{Content: body, CreatedAt: *createdAt}, true
+		}
+	}
+	return models.Comment{Content: value}, true
+}
+
+// jiraColumns maps header names to every column index using them. Jira
+// repeats headers such as "Comment" once per value.
+type jiraColumns map[string][]int
+
+func (c jiraColumns) get(record []string, name string) string {
+	for _, i := range c[strings.ToLower(name)] {
+		if i < len(record) && strings.TrimSpace(record[i]) != "" {
+			return strings.TrimSpace(record[i])
+		}
+	}
+	return ""
+}
+
+func (c jiraColumns) all(record []string, name string) []string {
+	var values []string
+	for _, i := range c[strings.ToLower(name)] {
+		if i < len(record) {
+			values = append(values, record[i])
+		}
+	}
+	return values
+}
+
+func jiraValues(values []string) []string {
+	var result []string
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			result = append(result, strings.TrimSpace(value))
+		}
+	}
+	return result
+}
+
+// Detect looks for a CSV header with Jira's Summary, Status and issue key
+// columns
+func (jiraImporter) Detect(data []byte) bool {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	header, err := reader.Read()
+	if err != nil {
+		return false
+	}
+	found := make(map[string]bool, len(header))
+	for _, name := range header {
+		found[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+	return found["summary"] && found["status"] && (found["issue key"] || found["issue id"])
+}
+
+func parseJiraCSV(data []byte) (*jiraPlan, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Jira CSV: %w", err)
+	}
+	if len(records) < 2 {
+		return nil, fmt.Errorf("no issues found in Jira export")
+	}
+
+	columns := make(jiraColumns)
+	for i, name := range records[0] {
+		key := strings.ToLower(strings.TrimSpace(name))
+		columns[key] = append(columns[key], i)
+	}
+	if columns["summary"] == nil || columns["status"] == nil {
+		return nil, fmt.Errorf("Jira export needs Summary and Status columns")
+	}
+
+	plan := &jiraPlan{}
+	var issues []*jiraIssue
+	byID := make(map[string]*jiraIssue)
+	for _, record := range records[1:] {
+		issue := &jiraIssue{
+			ID:          columns.get(record, "Issue id"),
+			Key:         columns.get(record, "Issue key"),
+			Summary:     columns.get(record, "Summary"),
+			Description: columns.get(record, "Description"),
+			Type:        columns.get(record, "Issue Type"),
+			Status:      columns.get(record, "Status"),
+			Category:    columns.get(record, "Status Category"),
+			Assignee:    columns.get(record, "Assignee"),
+			Reporter:    columns.get(record, "Reporter"),
+			Resolution:  columns.get(record, "Resolution"),
+			ResolvedAt:  parseJiraDate(columns.get(record, "Resolved")),
+			ParentID:    columns.get(record, "Parent id"),
+			DueDate:     columns.get(record, "Due Date"),
+			Priority:    columns.get(record, "Priority"),
+			Labels:      jiraValues(columns.all(record, "Labels")),
+			Attachments: jiraValues(columns.all(record, "Attachment")),
+		}
+		if issue.ParentID == "" {
+			issue.ParentID = columns.get(record, "Parent")
+		}
+		if issue.Summary == "" && issue.Key == "" {
+			plan.Skipped++
+			continue
+		}
+		if issue.Summary == "" {
+			issue.Summary = issue.Key
+		}
+		if issue.Status == "" {
+			issue.Status = "No status"
+		}
+		for _, value := range columns.all(record, "Comment") {
+			if comment, ok := parseJiraComment(value); ok {
+				issue.Comments = append(issue.Comments, comment)
+			}
+		}
+		if plan.ProjectName == "" {
+			plan.ProjectName = columns.get(record, "Project name")
+		}
+
+		issues = append(issues, issue)
+		if issue.ID != "" {
+			byID[issue.ID] = issue
+		}
+		if issue.Key != "" {
+			byID[issue.Key] = issue
+		}
+	}
+
+	// Sub-tasks whose parent is in the export become checklist items; the
+	// rest are imported as cards of their own
+	var topLevel []*jiraIssue
+	for _, issue := range issues {
+		if issue.isSubtask() {
+			if parent, ok := byID[issue.ParentID]; ok && !parent.isSubtask() {
+				parent.Subtasks = append(parent.Subtasks, issue)
+				plan.Subtasks++
+				continue
+			}
+			plan.Orphans++
+		}
+		topLevel = append(topLevel, issue)
+	}
+
+	// Columns follow Jira's status categories, then first appearance
+	statusByName := make(map[string]*jiraStatus)
+	categoryOrder := []string{"to do", "in progress", "done"}
+	var ordered [4][]*jiraStatus
+	seenPeople := make(map[string]bool)
+	addPerson := func(name string) {
+		if name == "" || seenPeople[strings.ToLower(name)] {
+			return
+		}
+		seenPeople[strings.ToLower(name)] = true
+		plan.People = append(plan.People, name)
+	}
+
+	for _, issue := range topLevel {
+		key := strings.ToLower(issue.Status)
+		status, ok := statusByName[key]
+		if !ok {
+			status = &jiraStatus{Name: issue.Status}
+			statusByName[key] = status
+			bucket := len(categoryOrder)
+			for i, category := range categoryOrder {
+				if strings.EqualFold(issue.Category, category) {
+					bucket = i
+				}
+			}
+			ordered[bucket] = append(ordered[bucket], status)
+		}
+		status.Issues = append(status.Issues, issue)
+		addPerson(issue.Assignee)
+		addPerson(issue.Reporter)
+		plan.Comments += len(issue.Comments)
+	}
+
+	for _, bucket := range ordered {
+		plan.Statuses = append(plan.Statuses, bucket...)
+	}
+	for _, status := range plan.Statuses {
+		status.IsDone = jiraStatusIsDone(status)
+	}
+	if len(plan.Statuses) == 0 {
+		return nil, fmt.Errorf("no issues found in Jira export")
+	}
+	return plan, nil
+}
+
+// jiraStatusIsDone treats statuses in Jira's Done category as done columns.
+// Exports without status categories fall back to statuses whose issues are
+// all resolved.
+func jiraStatusIsDone(status *jiraStatus) bool {
+	hasCategory := false
+	for _, issue := range status.Issues {
+		if issue.Category != "" {
+			hasCategory = true
+			if strings.EqualFold(issue.Category, "done") {
+				return true
+			}
+		}
+	}
+	if hasCategory {
+		return false
+	}
+	for _, issue := range status.Issues {
+		if !issue.isResolved() {
+			return false
+		}
+	}
+	return true
+}
+
+// Plan maps statuses to columns and issues to cards. Assignees and reporters
+// become people, sub-tasks become checklist items on their parent and
+// resolved issues in done columns keep their resolution date and resolution.
+func (jiraImporter) Plan(data []byte) (*ImportPlan, error) {
+	parsed, err := parseJiraCSV(data)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &ImportPlan{
+		BoardName: parsed.ProjectName,
+		People:    parsed.People,
+	}
+	warn := func(format string, args ...any) {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf(format, args...))
+	}
+
+	attachments, labelled, prioritised, due, reopened := 0, 0, 0, 0, 0
+	for _, status := range parsed.Statuses {
+		column := ImportColumn{
+			Source: status.Name,
+			Name:   status.Name,
+			IsDone: status.IsDone,
+		}
+		for _, issue := range status.Issues {
+			column.Cards = append(column.Cards, jiraCard(issue))
+			// Cards are only completed in done columns
+			if !status.IsDone && issue.isResolved() {
+				reopened++
+			}
+			for _, item := range append([]*jiraIssue{issue}, issue.Subtasks...) {
+				attachments += len(item.Attachments)
+				if len(item.Labels) > 0 {
+					labelled++
+				}
+				if item.Priority != "" {
+					prioritised++
+				}
+				if item.DueDate != "" {
+					due++
+				}
+			}
+		}
+		plan.Columns = append(plan.Columns, column)
+	}
+
+	if parsed.Skipped > 0 {
+		warn("%d rows without a summary or key will be skipped", parsed.Skipped)
+	}
+	if parsed.Orphans > 0 {
+		warn("%d sub-tasks without their parent in the export will become cards", parsed.Orphans)
+	}
+	if attachments > 0 {
+		warn("%d attachments will be dropped", attachments)
+	}
+	if labelled > 0 {
+		warn("labels on %d issues will be dropped", labelled)
+	}
+	if prioritised > 0 {
+		warn("priorities on %d issues will be dropped", prioritised)
+	}
+	if due > 0 {
+		warn("due dates on %d issues will be dropped", due)
+	}
+	if parsed.Comments > 0 {
+		warn("comment authors will not be kept")
+	}
+	if reopened > 0 {
+		warn("%d resolved issues are in statuses outside the Done category and will lose their resolution and resolution date", reopened)
+	}
+	return plan, nil
+}
+
+func jiraCard(issue *jiraIssue) ImportCard {
+	card := ImportCard{
+		Title:       issue.Summary,
+		Description: issue.Description,
+		CompletedAt: issue.ResolvedAt,
+		Comments:    issue.Comments,
+	}
+	if issue.Resolution != "" && !strings.EqualFold(issue.Resolution, "unresolved") {
+		card.Resolution = issue.Resolution
+	}
+	if issue.Assignee != "" {
+		card.Assignees = []string{issue.Assignee}
+	}
+
+	for _, subtask := range issue.Subtasks {
+		content := subtask.Summary
+		if subtask.Key != "" {
+			content = subtask.Key + " " + content
+		}
+		card.Checklist = append(card.Checklist, models.ChecklistItem{
+			Content:     content,
+			IsCompleted: subtask.isResolved() || strings.EqualFold(subtask.Category, "done"),
+		})
+	}
+	return card
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJiraPlanWarnsAboutResolutionsOutsideDone(t *testing.T) {
+	data := []byte(`Issue key,Summary,Status,Status Category,Resolution,Resolved
+KR-1,Shipped,Done,Done,Fixed,2024-03-06 17:45
+KR-2,Closed early,In Review,In Progress,Won't Do,2024-03-07 09:00
+KR-3,Still open,In Review,In Progress,Unresolved,
+`)
+
+	plan, err := jiraImporter{}.Plan(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var warned bool
+	for _, warning := range plan.Warnings {
+		if strings.HasPrefix(warning, "1 resolved issues are in statuses outside the Done category") {
+			warned = true
+		}
+	}
+	if !warned {
+		t.Errorf("no warning about the resolved issue in review, got %q", plan.Warnings)
+	}
+
+	for _, column := range plan.Columns {
+		if column.Name == "Done" && (!column.IsDone || column.Cards[0].Resolution != "Fixed" || column.Cards[0].CompletedAt == nil) {
+			t.Errorf("resolved issue in Done lost its resolution: %+v", column.Cards[0])
+		}
+	}
+}
@@ -6,34 +6,31 @@ import (
 	"time"
 )
 
+// KanbanService works on one board's storage. BoardRepo is the board list,
+// which for file and Postgres boards lives outside the board's own database.
 type KanbanService struct {
-	BoardRepo     repository.BoardRepository
-	ColumnRepo    repository.ColumnRepository
-	CardRepo      repository.CardRepository
-	PersonRepo    repository.PersonRepository
-	CommentRepo   repository.CommentRepository
-	ChecklistRepo repository.ChecklistRepository
+	BoardRepo repository.BoardRepository
+	repository.Repositories
 }
 
-func NewKanbanService(
-	boardRepo repository.BoardRepository,
-	columnRepo repository.ColumnRepository,
-	cardRepo repository.CardRepository,
-	personRepo repository.PersonRepository,
-	commentRepo repository.CommentRepository,
-	checklistRepo repository.ChecklistRepository,
-) *KanbanService {
+func NewKanbanService(boardRepo repository.BoardRepository, repos repository.Repositories) *KanbanService {
 	return &KanbanService{
-		BoardRepo:     boardRepo,
-		ColumnRepo:    columnRepo,
-		CardRepo:      cardRepo,
-		PersonRepo:    personRepo,
-		CommentRepo:   commentRepo,
-		ChecklistRepo: checklistRepo,
+		BoardRepo:    boardRepo,
+		Repositories: repos,
 	}
 }
 
-// GetBoardWithData returns a board with all its columns and cards
+// inTx runs fn on a copy of the service whose repositories share one
+// transaction, so a failure part way leaves nothing half applied. Inside
+// another inTx the work joins the outer transaction.
+func (s *KanbanService) inTx(fn func(tx *KanbanService) error) error {
+	return s.UnitOfWork.Do(func(repos repository.Repositories) error {
+		return fn(NewKanbanService(s.BoardRepo, repos))
+	})
+}
+
+// GetBoardWithData returns a board with all its columns, cards, swimlanes,
+// custom fields and card templates
 func (s *KanbanService) GetBoardWithData(boardID int64) (*models.Board, error) {
 	board, err := s.BoardRepo.GetByID(boardID)
 	if err != nil {
@@ -63,42 +60,139 @@ func (s *KanbanService) GetBoardWithData(boardID int64) (*models.Board, error) {
 				return nil, err
 			}
 			cards[j].Checklist = checklist
+
+			values, err := s.FieldRepo.GetValuesByCardID(cards[j].ID)
+			if err != nil {
+				return nil, err
+			}
+			cards[j].FieldValues = values
 		}
 		columns[i].Cards = cards
 	}
 
-	board.Columns = columns
-	return board, nil
-}
-
-// MoveCard moves a card to a new column/position and handles Done column automation
-func (s *KanbanService) MoveCard(cardID int64, newColumnID int64, newPosition int) error {
-	column, err := s.ColumnRepo.GetByID(newColumnID)
+	swimlanes, err := s.SwimlaneRepo.GetByBoardID(boardID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	card, err := s.CardRepo.GetByID(cardID)
+	fields, err := s.FieldRepo.GetByBoardID(boardID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Handle Done column automation
-	if column.IsDoneColumn {
-		now := time.Now()
-		card.CompletedAt = &now
+	templates, err := s.TemplateRepo.GetByBoardID(boardID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Update the card's completed_at
-	if err := s.CardRepo.Update(card); err != nil {
-		return err
-	}
+	board.Columns = columns
+	board.Swimlanes = swimlanes
+	board.Fields = fields
+	board.Templates = templates
+	return board, nil
+}
+
+// MoveCard moves a card to a new column/swimlane/position and handles Done
+// column automation. A nil swimlane is the board's unlaned row.
+func (s *KanbanService) MoveCard(cardID int64, newColumnID int64, newSwimlaneID *int64, newPosition int) error {
+	// The column and card are read in the transaction, so a concurrent edit
+	// of the card is not written back over with an older copy.
+	return s.inTx(func(tx *KanbanService) error {
+		column, err := tx.ColumnRepo.GetByID(newColumnID)
+		if err != nil {
+			return err
+		}
+
+		card, err := tx.CardRepo.GetByID(cardID)
+		if err != nil {
+			return err
+		}
 
-	// Move the card to new position
-	return s.CardRepo.Move(cardID, newColumnID, newPosition)
+		// Handle Done column automation. Reordering inside the same done column
+		// keeps the original completion time; leaving done columns clears it.
+		if column.IsDoneColumn {
+			if card.CompletedAt == nil || card.ColumnID != newColumnID {
+				now := time.Now()
+				card.CompletedAt = &now
+			}
+			card.Resolution = column.Resolution
+		} else {
+			card.CompletedAt = nil
+			card.Resolution = ""
+		}
+
+		// Update the card's completed_at
+		if err := tx.CardRepo.Update(card); err != nil {
+			return err
+		}
+
+		// Move the card to new position
+		return tx.CardRepo.Move(cardID, newColumnID, newSwimlaneID, newPosition)
+	})
+}
+
+// DeletePerson removes a person and their card assignments in one
+// transaction.
+func (s *KanbanService) DeletePerson(id int64) error {
+	return s.inTx(func(tx *KanbanService) error {
+		return tx.PersonRepo.Delete(id)
+	})
 }
 
-// GetCardWithDetails returns a card with all its details (assignees, comments, checklist)
+// UpdateColumn saves a column's name and done settings. A column that
+// becomes a done column, or changes its resolution, completes its cards,
+// archived ones included, with the new resolution; one that stops being a
+// done column reopens them. The cards change in the same transaction as the
+// column.
+func (s *KanbanService) UpdateColumn(column *models.Column) error {
+	return s.inTx(func(tx *KanbanService) error {
+		previous, err := tx.ColumnRepo.GetByID(column.ID)
+		if err != nil {
+			return err
+		}
+		if err := tx.ColumnRepo.Update(column); err != nil {
+			return err
+		}
+		if previous.IsDoneColumn == column.IsDoneColumn && previous.Resolution == column.Resolution {
+			return nil
+		}
+
+		cards, err := tx.CardRepo.GetByColumnID(column.ID)
+		if err != nil {
+			return err
+		}
+		archived, err := tx.CardRepo.GetArchivedByBoardID(column.BoardID)
+		if err != nil {
+			return err
+		}
+		for _, card := range archived {
+			if card.ColumnID == column.ID {
+				cards = append(cards, card)
+			}
+		}
+
+		now := time.Now()
+		for i := range cards {
+			card := &cards[i]
+			if column.IsDoneColumn {
+				if card.CompletedAt == nil {
+					card.CompletedAt = &now
+				}
+				card.Resolution = column.Resolution
+			} else {
+				card.CompletedAt = nil
+				card.Resolution = ""
+			}
+			if err := tx.CardRepo.Update(card); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetCardWithDetails returns a card with all its details (assignees, comments,
+// checklist, links, custom field values)
 func (s *KanbanService) GetCardWithDetails(cardID int64) (*models.Card, error) {
 	card, err := s.CardRepo.GetByID(cardID)
 	if err != nil {
@@ -123,6 +217,18 @@ func (s *KanbanService) GetCardWithDetails(cardID int64) (*models.Card, error) {
 	}
 	card.Checklist = checklist
 
+	links, err := s.LinkRepo.GetByCardID(cardID)
+	if err != nil {
+		return nil, err
+	}
+	card.Links = links
+
+	values, err := s.FieldRepo.GetValuesByCardID(cardID)
+	if err != nil {
+		return nil, err
+	}
+	card.FieldValues = values
+
 	return card, nil
 }
 
@@ -137,16 +243,20 @@ func (s *KanbanService) CreateDefaultColumns(boardID int64) error {
 		{"Done", true},
 	}
 
-	for _, col := range columns {
-		column := &models.Column{
-			BoardID:      boardID,
-			Name:         col.name,
-			IsDoneColumn: col.isDoneCol,
-		}
-		if err := s.ColumnRepo.Create(column); err != nil {
-			return err
+	return s.inTx(func(tx *KanbanService) error {
+		for _, col := range columns {
+			column := &models.Column{
+				BoardID:      boardID,
+				Name:         col.name,
+				IsDoneColumn: col.isDoneCol,
+			}
+			if col.isDoneCol {
+				column.Resolution = models.DefaultResolution
+			}
+			if err := tx.ColumnRepo.Create(column); err != nil {
+				return err
+			}
 		}
-	}
-
-	return nil
+		return nil
+	})
 }
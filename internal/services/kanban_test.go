@@ -0,0 +1,110 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"krizzy/internal/models"
+)
+
+func TestUpdateColumnCompletesAndReopensCards(t *testing.T) {
+	svc, board := newMemoryBoard(t, "Columns")
+	columns, err := svc.ColumnRepo.GetByBoardID(board.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	column := columns[0]
+	if column.IsDoneColumn {
+		t.Fatalf("first default column %q is a done column", column.Name)
+	}
+
+	open := &models.Card{ColumnID: column.ID, Title: "Open"}
+	archived := &models.Card{ColumnID: column.ID, Title: "Archived"}
+	for _, card := range []*models.Card{open, archived} {
+		if err := svc.CardRepo.Create(card); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := svc.CardRepo.Archive(archived.ID, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	check := func(want string) {
+		t.Helper()
+		for _, id := range []int64{open.ID, archived.ID} {
+			card, err := svc.CardRepo.GetByID(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (card.CompletedAt != nil) != (want != "") || card.Resolution != want {
+				t.Errorf("card %q: completed at %v with resolution %q, want resolution %q", card.Title, card.CompletedAt, card.Resolution, want)
+			}
+		}
+	}
+
+	column.IsDoneColumn = true
+	column.Resolution = "Shipped"
+	if err := svc.UpdateColumn(&column); err != nil {
+		t.Fatal(err)
+	}
+	check("Shipped")
+
+	// A new resolution relabels the cards and keeps when they were completed
+	before, err := svc.CardRepo.GetByID(open.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	column.Resolution = "Won't do"
+	if err := svc.UpdateColumn(&column); err != nil {
+		t.Fatal(err)
+	}
+	check("Won't do")
+	after, err := svc.CardRepo.GetByID(open.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !after.CompletedAt.Equal(*before.CompletedAt) {
+		t.Errorf("completion time changed from %v to %v", before.CompletedAt, after.CompletedAt)
+	}
+
+	column.IsDoneColumn = false
+	column.Resolution = ""
+	if err := svc.UpdateColumn(&column); err != nil {
+		t.Fatal(err)
+	}
+	check("")
+}
+
+func TestDeletePersonRemovesAssignments(t *testing.T) {
+	svc, board := newMemoryBoard(t, "People")
+	columns, err := svc.ColumnRepo.GetByBoardID(board.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	person := &models.Person{BoardID: board.ID, Name: "Ann"}
+	if err := svc.PersonRepo.Create(person); err != nil {
+		t.Fatal(err)
+	}
+	card := &models.Card{ColumnID: columns[0].ID, Title: "Assigned"}
+	if err := svc.CardRepo.Create(card); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.PersonRepo.SetCardAssignees(card.ID, []int64{person.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.DeletePerson(person.ID); err != nil {
+		t.Fatal(err)
+	}
+	assignees, err := svc.PersonRepo.GetByCardID(card.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(assignees) != 0 {
+		t.Errorf("card still has assignees %+v", assignees)
+	}
+	if _, err := svc.PersonRepo.GetByID(person.ID); err == nil {
+		t.Error("person still exists")
+	}
+}
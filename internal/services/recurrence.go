@@ -0,0 +1,159 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"krizzy/internal/models"
+)
+
+// NextOccurrence returns when a schedule next fires after t, or nil when it
+// never fires again
+func NextOccurrence(schedule string, t time.Time) (*time.Time, error) {
+	sched, err := ParseSchedule(schedule, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	next := sched.Next(t)
+	if next.IsZero() {
+		return nil, nil
+	}
+	return &next, nil
+}
+
+// RunRecurrence creates the card for a due recurrence. Advancing the
+// recurrence and creating its card happen in one transaction, and advancing
+// only succeeds for the run that still sees the old next run time, so a
+// second scheduler can never create the same occurrence twice and a failure
+// never skips one. Occurrences missed while the server was down collapse into
+// one card, and the schedule resumes from now. It returns nil when the
+// recurrence was not due or another run claimed it.
+func (s *KanbanService) RunRecurrence(rec *models.Recurrence, now time.Time) (*models.Card, error) {
+	if !rec.Enabled || rec.NextRunAt == nil || rec.NextRunAt.After(now) {
+		return nil, nil
+	}
+
+	next, err := NextOccurrence(rec.Schedule, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var card *models.Card
+	err = s.inTx(func(tx *KanbanService) error {
+		claimed, err := tx.RecurrenceRepo.Advance(rec.ID, *rec.NextRunAt, next, now)
+		if err != nil || !claimed {
+			return err
+		}
+
+		tpl, err := tx.TemplateInBoard(rec.BoardID, rec.TemplateID)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ColumnInBoard(rec.BoardID, rec.ColumnID); err != nil {
+			return err
+		}
+		card, err = tx.CreateCardFromTemplate(tpl, rec.ColumnID, nil, now)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+// RecurrenceScheduler periodically creates the cards of due recurrences on
+// every board and publishes them to open boards. Schedule state lives in each
+// board's storage, so it survives restarts.
+type RecurrenceScheduler struct {
+	bm       *BoardManager
+	hub      BoardEventHub
+	interval time.Duration
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func NewRecurrenceScheduler(bm *BoardManager, hub BoardEventHub, interval time.Duration) *RecurrenceScheduler {
+	return &RecurrenceScheduler{
+		bm:       bm,
+		hub:      hub,
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Start checks for due recurrences right away and then on the configured
+// interval until Close is called. An interval of zero disables the scheduler.
+func (s *RecurrenceScheduler) Start() {
+	if s.interval <= 0 {
+		return
+	}
+
+	go func() {
+		s.RunDue(time.Now())
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				s.RunDue(time.Now())
+			}
+		}
+	}()
+}
+
+func (s *RecurrenceScheduler) Close() {
+	s.stopOnce.Do(func() { close(s.stop) })
+}
+
+// RunDue creates every card that is due at now and returns how many were
+// created. Boards whose storage is unavailable are skipped and caught up on a
+// later run.
+func (s *RecurrenceScheduler) RunDue(now time.Time) int {
+	boards, err := s.bm.GetAllBoards()
+	if err != nil {
+		log.Printf("recurrences: failed to list boards: %v", err)
+		return 0
+	}
+
+	created := 0
+	for _, board := range boards {
+		svc, err := s.bm.GetServiceForBoard(board.ID)
+		if err != nil {
+			// The health checks already report unreachable boards
+			if !errors.Is(err, ErrBoardUnavailable) {
+				log.Printf("recurrences: failed to open board %d: %v", board.ID, err)
+			}
+			continue
+		}
+
+		recurrences, err := svc.RecurrenceRepo.GetByBoardID(board.ID)
+		if err != nil {
+			log.Printf("recurrences: failed to load board %d: %v", board.ID, err)
+			continue
+		}
+
+		for i := range recurrences {
+			card, err := svc.RunRecurrence(&recurrences[i], now)
+			if err != nil {
+				log.Printf("recurrences: recurrence %d on board %d failed: %v", recurrences[i].ID, board.ID, err)
+				continue
+			}
+			if card == nil {
+				continue
+			}
+			created++
+			s.hub.Publish(BoardEvent{
+				Type:     "card.created",
+				BoardID:  board.ID,
+				CardID:   card.ID,
+				ColumnID: card.ColumnID,
+			})
+		}
+	}
+	return created
+}
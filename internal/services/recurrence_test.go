@@ -0,0 +1,73 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"krizzy/internal/models"
+)
+
+// TestRunRecurrenceCreatesOneCard misses several hourly occurrences, then
+// runs the recurrence from two schedulers holding the same copy of it. Only
+// one card may come out, and the schedule resumes after now.
+func TestRunRecurrenceCreatesOneCard(t *testing.T) {
+	svc, board := newMemoryBoard(t, "Chores")
+	columns, err := svc.ColumnRepo.GetByBoardID(board.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	column := columns[0]
+
+	tpl := &models.CardTemplate{BoardID: board.ID, Name: "Inbox", Title: "Triage the inbox"}
+	if err := svc.TemplateRepo.Create(tpl); err != nil {
+		t.Fatal(err)
+	}
+	due := time.Date(2026, 1, 5, 9, 0, 0, 0, time.Local)
+	rec := &models.Recurrence{
+		BoardID:    board.ID,
+		TemplateID: tpl.ID,
+		ColumnID:   column.ID,
+		Schedule:   "@hourly",
+		Enabled:    true,
+		NextRunAt:  &due,
+	}
+	if err := svc.RecurrenceRepo.Create(rec); err != nil {
+		t.Fatal(err)
+	}
+
+	// 09:00 to 13:00 were all missed
+	now := time.Date(2026, 1, 5, 13, 30, 0, 0, time.Local)
+	card, err := svc.RunRecurrence(rec, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card == nil || card.Title != "Triage the inbox" {
+		t.Fatalf("first run created %+v, want the template's card", card)
+	}
+
+	// A second scheduler still holding the old next run time loses the claim
+	stale := *rec
+	if card, err := svc.RunRecurrence(&stale, now.Add(time.Second)); err != nil || card != nil {
+		t.Fatalf("second run created %+v, %v; want nothing", card, err)
+	}
+
+	stored, err := svc.RecurrenceRepo.GetByID(rec.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2026, 1, 5, 14, 0, 0, 0, time.Local); stored.NextRunAt == nil || !stored.NextRunAt.Equal(want) {
+		t.Errorf("next run at %v, want %s", stored.NextRunAt, want)
+	}
+	// Running the reloaded recurrence again before then is not due
+	if card, err := svc.RunRecurrence(stored, now.Add(time.Minute)); err != nil || card != nil {
+		t.Fatalf("run before the next occurrence created %+v, %v; want nothing", card, err)
+	}
+
+	cards, err := svc.CardRepo.GetByColumnID(column.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 1 {
+		t.Errorf("column has %d cards, want 1", len(cards))
+	}
+}
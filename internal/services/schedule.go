@@ -0,0 +1,415 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSchedule is returned when a recurrence schedule cannot be parsed
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// scheduleHorizon bounds the search for the next occurrence so impossible
+// schedules such as "0 0 31 2 *" stop instead of looping forever
+const scheduleHorizon = 10 * 366 * 24 * time.Hour
+
+// Schedule yields the occurrences of a recurring card
+type Schedule interface {
+	// Next returns the first occurrence strictly after t, or the zero time
+	// if there is none
+	Next(t time.Time) time.Time
+}
+
+var cronMacros = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 1",
+	"@monthly": "0 0 1 * *",
+	"@yearly":  "0 0 1 1 *",
+}
+
+// ParseSchedule reads a five-field cron expression ("0 9 * * MON"), one of
+// the @hourly/@daily/@weekly/@monthly/@yearly shortcuts, or an RRULE such as
+// "RRULE:FREQ=WEEKLY;BYDAY=MO;BYHOUR=9", optionally preceded by a
+// "DTSTART:20260105T090000" line. Times are in loc. Schedules that never
+// fire, such as "0 0 31 2 *", are rejected.
+func ParseSchedule(spec string, loc *time.Location) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, fmt.Errorf("%w: schedule is empty", ErrInvalidSchedule)
+	}
+	if expanded, ok := cronMacros[strings.ToLower(spec)]; ok {
+		spec = expanded
+	}
+
+	var sched Schedule
+	upper := strings.ToUpper(spec)
+	if strings.HasPrefix(upper, "RRULE:") || strings.HasPrefix(upper, "DTSTART:") || strings.HasPrefix(upper, "FREQ=") {
+		rrule, err := parseRRule(upper, loc)
+		if err != nil {
+			return nil, err
+		}
+		sched = rrule
+	} else {
+		cron, err := parseCron(upper, loc)
+		if err != nil {
+			return nil, err
+		}
+		sched = cron
+	}
+
+	// The horizon is longer than any gap between leap days, so a schedule
+	// with no occurrence in it has none at all
+	if sched.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("%w: %q never fires", ErrInvalidSchedule, spec)
+	}
+	return sched, nil
+}
+
+type cronSchedule struct {
+	minute, hour, dom, month, dow []bool
+	domAny, dowAny                bool
+	loc                           *time.Location
+}
+
+var (
+	cronMonthNames = map[string]int{"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6, "JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12}
+	cronDayNames   = map[string]int{"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6}
+)
+
+func parseCron(spec string, loc *time.Location) (*cronSchedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: cron expressions need 5 fields, got %d", ErrInvalidSchedule, len(fields))
+	}
+
+	c := &cronSchedule{loc: loc}
+	var err error
+	if c.minute, _, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, err
+	}
+	if c.hour, _, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, err
+	}
+	if c.dom, c.domAny, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, err
+	}
+	if c.month, _, err = parseCronField(fields[3], 1, 12, cronMonthNames); err != nil {
+		return nil, err
+	}
+	if c.dow, c.dowAny, err = parseCronField(fields[4], 0, 7, cronDayNames); err != nil {
+		return nil, err
+	}
+	// 7 is an alias for Sunday
+	if c.dow[7] {
+		c.dow[0] = true
+	}
+	return c, nil
+}
+
+// parseCronField expands lists, ranges and steps into a lookup table indexed
+// by value. The bool reports whether the field was a bare "*".
+func parseCronField(field string, min, max int, names map[string]int) ([]bool, bool, error) {
+	set := make([]bool, max+1)
+	if field == "*" {
+		for i := min; i <= max; i++ {
+			set[i] = true
+		}
+		return set, true, nil
+	}
+
+	value := func(s string) (int, error) {
+		if n, ok := names[s]; ok {
+			return n, nil
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil || n < min || n > max {
+			return 0, fmt.Errorf("%w: %q is not between %d and %d", ErrInvalidSchedule, s, min, max)
+		}
+		return n, nil
+	}
+
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if before, after, ok := strings.Cut(part, "/"); ok {
+			n, err := strconv.Atoi(after)
+			if err != nil || n < 1 {
+				return nil, false, fmt.Errorf("%w: bad step in %q", ErrInvalidSchedule, part)
+			}
+			rangePart, step = before, n
+		}
+
+		lo, hi := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			a, b, _ := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = value(a); err != nil {
+				return nil, false, err
+			}
+			if hi, err = value(b); err != nil {
+				return nil, false, err
+			}
+			if lo > hi {
+				return nil, false, fmt.Errorf("%w: range %q runs backwards", ErrInvalidSchedule, rangePart)
+			}
+		default:
+			var err error
+			if lo, err = value(rangePart); err != nil {
+				return nil, false, err
+			}
+			// "5/15" means from 5 to the end in steps of 15
+			if step == 1 {
+				hi = lo
+			}
+		}
+
+		for i := lo; i <= hi; i += step {
+			set[i] = true
+		}
+	}
+	return set, false, nil
+}
+
+// dayMatches follows cron's rule that when both day fields are restricted a
+// day matching either of them counts
+func (c *cronSchedule) dayMatches(t time.Time) bool {
+	dom := c.dom[t.Day()]
+	dow := c.dow[int(t.Weekday())]
+	if !c.domAny && !c.dowAny {
+		return dom || dow
+	}
+	return dom && dow
+}
+
+func (c *cronSchedule) Next(t time.Time) time.Time {
+	t = t.In(c.loc)
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, c.loc).Add(time.Minute)
+	limit := t.Add(scheduleHorizon)
+
+	for t.Before(limit) {
+		if !c.month[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, c.loc)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, c.loc)
+			continue
+		}
+		if !c.hour[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, c.loc)
+			continue
+		}
+		if !c.minute[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+type rruleSchedule struct {
+	freq       string
+	interval   int
+	byDay      []time.Weekday
+	byMonthDay []int
+	hours      []int
+	minutes    []int
+	start      time.Time
+}
+
+var rruleDayNames = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// parseRRule supports the subset of RFC 5545 that suits recurring cards:
+// DAILY, WEEKLY and MONTHLY rules with INTERVAL, BYDAY, BYMONTHDAY, BYHOUR
+// and BYMINUTE. Without a DTSTART, intervals count from 5 January 1970, a
+// Monday.
+func parseRRule(spec string, loc *time.Location) (*rruleSchedule, error) {
+	r := &rruleSchedule{
+		interval: 1,
+		start:    time.Date(1970, 1, 5, 0, 0, 0, 0, loc),
+	}
+	hasStart := false
+
+	var rule string
+	for _, line := range strings.Fields(spec) {
+		switch {
+		case strings.HasPrefix(line, "DTSTART:"):
+			value := strings.TrimPrefix(line, "DTSTART:")
+			// A trailing Z marks a UTC start time
+			startLoc := loc
+			if strings.HasSuffix(value, "Z") {
+				value, startLoc = strings.TrimSuffix(value, "Z"), time.UTC
+			}
+			var err error
+			if r.start, err = time.ParseInLocation("20060102T150405", value, startLoc); err != nil {
+				if r.start, err = time.ParseInLocation("20060102", value, startLoc); err != nil {
+					return nil, fmt.Errorf("%w: bad DTSTART %q", ErrInvalidSchedule, value)
+				}
+			}
+			r.start = r.start.In(loc)
+			hasStart = true
+		case strings.HasPrefix(line, "RRULE:"):
+			rule = strings.TrimPrefix(line, "RRULE:")
+		default:
+			rule = line
+		}
+	}
+
+	for _, part := range strings.Split(rule, ";") {
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: bad RRULE part %q", ErrInvalidSchedule, part)
+		}
+		var err error
+		switch key {
+		case "FREQ":
+			if value != "DAILY" && value != "WEEKLY" && value != "MONTHLY" {
+				return nil, fmt.Errorf("%w: FREQ must be DAILY, WEEKLY or MONTHLY", ErrInvalidSchedule)
+			}
+			r.freq = value
+		case "INTERVAL":
+			if r.interval, err = strconv.Atoi(value); err != nil || r.interval < 1 {
+				return nil, fmt.Errorf("%w: bad INTERVAL %q", ErrInvalidSchedule, value)
+			}
+		case "BYDAY":
+			for _, name := range strings.Split(value, ",") {
+				day, ok := rruleDayNames[name]
+				if !ok {
+					return nil, fmt.Errorf("%w: bad BYDAY %q", ErrInvalidSchedule, name)
+				}
+				r.byDay = append(r.byDay, day)
+			}
+		case "BYMONTHDAY":
+			if r.byMonthDay, err = parseRRuleInts(value, -31, 31); err != nil {
+				return nil, err
+			}
+		case "BYHOUR":
+			if r.hours, err = parseRRuleInts(value, 0, 23); err != nil {
+				return nil, err
+			}
+		case "BYMINUTE":
+			if r.minutes, err = parseRRuleInts(value, 0, 59); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("%w: %s is not supported", ErrInvalidSchedule, key)
+		}
+	}
+	if r.freq == "" {
+		return nil, fmt.Errorf("%w: RRULE needs a FREQ", ErrInvalidSchedule)
+	}
+
+	// Unset parts default to DTSTART, as in RFC 5545
+	if r.hours == nil {
+		r.hours = []int{r.start.Hour()}
+	}
+	if r.minutes == nil {
+		r.minutes = []int{r.start.Minute()}
+	}
+	if r.freq == "WEEKLY" && r.byDay == nil {
+		r.byDay = []time.Weekday{r.start.Weekday()}
+	}
+	if r.freq == "MONTHLY" && r.byDay == nil && r.byMonthDay == nil {
+		r.byMonthDay = []int{r.start.Day()}
+	}
+	if !hasStart {
+		r.start = time.Date(r.start.Year(), r.start.Month(), r.start.Day(), 0, 0, 0, 0, loc)
+	}
+	slices.Sort(r.hours)
+	slices.Sort(r.minutes)
+	return r, nil
+}
+
+func parseRRuleInts(value string, min, max int) ([]int, error) {
+	var values []int
+	for _, s := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < min || n > max || n == 0 && min < 0 {
+			return nil, fmt.Errorf("%w: %q is out of range", ErrInvalidSchedule, s)
+		}
+		values = append(values, n)
+	}
+	return values, nil
+}
+
+// dayMatches reports whether any occurrence can fall on the given day
+func (r *rruleSchedule) dayMatches(day time.Time) bool {
+	startDay := time.Date(r.start.Year(), r.start.Month(), r.start.Day(), 0, 0, 0, 0, r.start.Location())
+	if day.Before(startDay) {
+		return false
+	}
+
+	switch r.freq {
+	case "DAILY":
+		days := int(day.Sub(startDay).Hours()+12) / 24
+		if days%r.interval != 0 {
+			return false
+		}
+	case "WEEKLY":
+		weeks := int(weekStart(day).Sub(weekStart(startDay)).Hours()+12) / (24 * 7)
+		if weeks%r.interval != 0 {
+			return false
+		}
+	case "MONTHLY":
+		months := (day.Year()-startDay.Year())*12 + int(day.Month()-startDay.Month())
+		if months%r.interval != 0 {
+			return false
+		}
+	}
+
+	if r.byDay != nil && !slices.Contains(r.byDay, day.Weekday()) {
+		return false
+	}
+	if r.byMonthDay != nil {
+		lastDay := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day()
+		if !slices.ContainsFunc(r.byMonthDay, func(n int) bool {
+			return n == day.Day() || n < 0 && lastDay+n+1 == day.Day()
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// weekStart returns midnight on the Monday of t's week
+func weekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+}
+
+func (r *rruleSchedule) Next(t time.Time) time.Time {
+	loc := r.start.Location()
+	t = t.In(loc)
+	if t.Before(r.start) {
+		t = r.start.Add(-time.Second)
+	}
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	limit := day.Add(scheduleHorizon)
+
+	for ; day.Before(limit); day = day.AddDate(0, 0, 1) {
+		if !r.dayMatches(day) {
+			continue
+		}
+		for _, hour := range r.hours {
+			for _, minute := range r.minutes {
+				candidate := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, loc)
+				if candidate.After(t) {
+					return candidate
+				}
+			}
+		}
+	}
+	return time.Time{}
+}
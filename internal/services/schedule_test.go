@@ -0,0 +1,102 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestScheduleNext(t *testing.T) {
+	at := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	// 1 January 2026 is a Thursday
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{"hourly", "@hourly", at(2026, 1, 1, 10, 30), at(2026, 1, 1, 11, 0)},
+		{"daily", "@daily", at(2026, 1, 1, 10, 30), at(2026, 1, 2, 0, 0)},
+		{"weekly on monday", "@weekly", at(2026, 1, 1, 0, 0), at(2026, 1, 5, 0, 0)},
+		{"monthly", "@monthly", at(2026, 1, 1, 10, 30), at(2026, 2, 1, 0, 0)},
+		{"yearly", "@yearly", at(2026, 1, 1, 10, 30), at(2027, 1, 1, 0, 0)},
+		{"macro case", "@DAILY", at(2026, 1, 1, 10, 30), at(2026, 1, 2, 0, 0)},
+
+		{"step within hour range", "*/15 9-17 * * *", at(2026, 1, 1, 9, 16), at(2026, 1, 1, 9, 30)},
+		{"hour range wraps to next day", "*/15 9-17 * * *", at(2026, 1, 1, 17, 50), at(2026, 1, 2, 9, 0)},
+		{"step from a start value", "5/20 * * * *", at(2026, 1, 1, 10, 26), at(2026, 1, 1, 10, 45)},
+		{"list", "0 8,12,18 * * *", at(2026, 1, 1, 12, 0), at(2026, 1, 1, 18, 0)},
+		{"weekday range skips the weekend", "0 9 * * MON-FRI", at(2026, 1, 2, 10, 0), at(2026, 1, 5, 9, 0)},
+		{"month name", "0 0 1 MAR *", at(2026, 1, 1, 0, 0), at(2026, 3, 1, 0, 0)},
+		{"leap day", "0 0 29 2 *", at(2026, 1, 1, 0, 0), at(2028, 2, 29, 0, 0)},
+
+		{"day of month only", "0 0 13 * *", at(2026, 1, 1, 0, 0), at(2026, 1, 13, 0, 0)},
+		{"both day fields match the weekday", "0 0 13 * FRI", at(2026, 1, 1, 0, 0), at(2026, 1, 2, 0, 0)},
+		{"both day fields match the day of month", "0 0 13 * FRI", at(2026, 1, 9, 0, 0), at(2026, 1, 13, 0, 0)},
+
+		{"sunday as 0", "0 0 * * 0", at(2026, 1, 1, 0, 0), at(2026, 1, 4, 0, 0)},
+		{"sunday as 7", "0 0 * * 7", at(2026, 1, 1, 0, 0), at(2026, 1, 4, 0, 0)},
+		{"sunday by name", "0 0 * * SUN", at(2026, 1, 1, 0, 0), at(2026, 1, 4, 0, 0)},
+		{"range ending on 7", "0 0 * * 6-7", at(2026, 1, 3, 12, 0), at(2026, 1, 4, 0, 0)},
+
+		{"daily interval", "DTSTART:20260101T090000 RRULE:FREQ=DAILY;INTERVAL=3", at(2026, 1, 1, 9, 0), at(2026, 1, 4, 9, 0)},
+		{"weekly by day", "RRULE:FREQ=WEEKLY;BYDAY=FR;BYHOUR=9", at(2026, 1, 1, 0, 0), at(2026, 1, 2, 9, 0)},
+		{"fortnightly within the week", "DTSTART:20260105T080000 RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", at(2026, 1, 5, 8, 0), at(2026, 1, 8, 8, 0)},
+		{"fortnightly skips a week", "DTSTART:20260105T080000 RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", at(2026, 1, 8, 8, 0), at(2026, 1, 19, 8, 0)},
+		{"monthly interval", "DTSTART:20260115T100000 RRULE:FREQ=MONTHLY;INTERVAL=2", at(2026, 1, 15, 10, 0), at(2026, 3, 15, 10, 0)},
+		{"last day of february", "RRULE:FREQ=MONTHLY;BYMONTHDAY=-1;BYHOUR=17", at(2026, 2, 1, 0, 0), at(2026, 2, 28, 17, 0)},
+		{"last day of march", "RRULE:FREQ=MONTHLY;BYMONTHDAY=-1;BYHOUR=17", at(2026, 2, 28, 17, 0), at(2026, 3, 31, 17, 0)},
+		{"nothing before dtstart", "DTSTART:20260301T093000 RRULE:FREQ=DAILY", at(2026, 1, 1, 0, 0), at(2026, 3, 1, 9, 30)},
+		{"dtstart itself", "DTSTART:20260301T093000 RRULE:FREQ=DAILY", at(2026, 3, 1, 9, 29), at(2026, 3, 1, 9, 30)},
+		{"utc dtstart", "DTSTART:20260301T093000Z\nRRULE:FREQ=DAILY", at(2026, 3, 1, 9, 30), at(2026, 3, 2, 9, 30)},
+		{"bare rule", "FREQ=DAILY;BYHOUR=6", at(2026, 1, 1, 7, 0), at(2026, 1, 2, 6, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sched, err := ParseSchedule(tt.spec, time.UTC)
+			if err != nil {
+				t.Fatalf("parse %q: %v", tt.spec, err)
+			}
+			if got := sched.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.from, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseScheduleRejects(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * *",
+		"61 * * * *",
+		"0 24 * * *",
+		"0 0 0 * *",
+		"0 0 * 13 *",
+		"0 0 * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"0 0 * * FUNDAY",
+		"@fortnightly",
+		// Valid fields, but no day they describe exists
+		"0 0 31 2 *",
+		"0 0 30 2 *",
+		"0 0 31 4,6,9,11 *",
+		"RRULE:FREQ=YEARLY",
+		"RRULE:INTERVAL=2",
+		"RRULE:FREQ=DAILY;INTERVAL=0",
+		"RRULE:FREQ=DAILY;COUNT=3",
+		"RRULE:FREQ=WEEKLY;BYDAY=XX",
+		"RRULE:FREQ=MONTHLY;BYMONTHDAY=0",
+		"RRULE:FREQ=MONTHLY;BYMONTHDAY=32",
+		"DTSTART:2026 RRULE:FREQ=DAILY",
+		"DTSTART:20260201 RRULE:FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30",
+	} {
+		if _, err := ParseSchedule(spec, time.UTC); !errors.Is(err, ErrInvalidSchedule) {
+			t.Errorf("ParseSchedule(%q) = %v, want ErrInvalidSchedule", spec, err)
+		}
+	}
+}
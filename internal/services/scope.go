@@ -0,0 +1,163 @@
+package services
+
+import (
+	"errors"
+
+	"krizzy/internal/models"
+)
+
+// ErrNotInBoard is returned when a resource exists but belongs to a different
+// board than the one a request addressed. Local boards share one SQLite
+// database, so IDs alone are not enough to scope a request.
+var ErrNotInBoard = errors.New("resource does not belong to board")
+
+// ColumnInBoard loads a column and checks it belongs to boardID
+func (s *KanbanService) ColumnInBoard(boardID, columnID int64) (*models.Column, error) {
+	column, err := s.ColumnRepo.GetByID(columnID)
+	if err != nil {
+		return nil, err
+	}
+	if column.BoardID != boardID {
+		return nil, ErrNotInBoard
+	}
+	return column, nil
+}
+
+// CardInBoard loads a card and checks its column belongs to boardID
+func (s *KanbanService) CardInBoard(boardID, cardID int64) (*models.Card, error) {
+	card, err := s.CardRepo.GetByID(cardID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.ColumnInBoard(boardID, card.ColumnID); err != nil {
+		return nil, ErrNotInBoard
+	}
+	return card, nil
+}
+
+// PersonInBoard loads a person and checks they belong to boardID
+func (s *KanbanService) PersonInBoard(boardID, personID int64) (*models.Person, error) {
+	person, err := s.PersonRepo.GetByID(personID)
+	if err != nil {
+		return nil, err
+	}
+	if person.BoardID != boardID {
+		return nil, ErrNotInBoard
+	}
+	return person, nil
+}
+
+// CommentInBoard loads a comment and checks its card belongs to boardID
+func (s *KanbanService) CommentInBoard(boardID, commentID int64) (*models.Comment, error) {
+	comment, err := s.CommentRepo.GetByID(commentID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.CardInBoard(boardID, comment.CardID); err != nil {
+		return nil, ErrNotInBoard
+	}
+	return comment, nil
+}
+
+// LinkInBoard loads a card link and checks its card belongs to boardID
+func (s *KanbanService) LinkInBoard(boardID, linkID int64) (*models.CardLink, error) {
+	link, err := s.LinkRepo.GetByID(linkID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.CardInBoard(boardID, link.CardID); err != nil {
+		return nil, ErrNotInBoard
+	}
+	return link, nil
+}
+
+// ChecklistItemInBoard loads a checklist item and checks its card belongs to boardID
+func (s *KanbanService) ChecklistItemInBoard(boardID, itemID int64) (*models.ChecklistItem, error) {
+	item, err := s.ChecklistRepo.GetByID(itemID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.CardInBoard(boardID, item.CardID); err != nil {
+		return nil, ErrNotInBoard
+	}
+	return item, nil
+}
+
+// SwimlaneInBoard loads a swimlane and checks it belongs to boardID
+func (s *KanbanService) SwimlaneInBoard(boardID, swimlaneID int64) (*models.Swimlane, error) {
+	lane, err := s.SwimlaneRepo.GetByID(swimlaneID)
+	if err != nil {
+		return nil, err
+	}
+	if lane.BoardID != boardID {
+		return nil, ErrNotInBoard
+	}
+	return lane, nil
+}
+
+// FieldInBoard loads a custom field and checks it belongs to boardID
+func (s *KanbanService) FieldInBoard(boardID, fieldID int64) (*models.CustomField, error) {
+	field, err := s.FieldRepo.GetByID(fieldID)
+	if err != nil {
+		return nil, err
+	}
+	if field.BoardID != boardID {
+		return nil, ErrNotInBoard
+	}
+	return field, nil
+}
+
+// TemplateInBoard loads a card template and checks it belongs to boardID
+func (s *KanbanService) TemplateInBoard(boardID, templateID int64) (*models.CardTemplate, error) {
+	tpl, err := s.TemplateRepo.GetByID(templateID)
+	if err != nil {
+		return nil, err
+	}
+	if tpl.BoardID != boardID {
+		return nil, ErrNotInBoard
+	}
+	return tpl, nil
+}
+
+// RecurrenceInBoard loads a recurrence and checks it belongs to boardID
+func (s *KanbanService) RecurrenceInBoard(boardID, recurrenceID int64) (*models.Recurrence, error) {
+	rec, err := s.RecurrenceRepo.GetByID(recurrenceID)
+	if err != nil {
+		return nil, err
+	}
+	if rec.BoardID != boardID {
+		return nil, ErrNotInBoard
+	}
+	return rec, nil
+}
+
+// PeopleInBoard checks every ID refers to a person on boardID
+func (s *KanbanService) PeopleInBoard(boardID int64, personIDs []int64) error {
+	for _, id := range personIDs {
+		if _, err := s.PersonInBoard(boardID, id); err != nil {
+			return ErrNotInBoard
+		}
+	}
+	return nil
+}
+
+// ColumnsInBoard checks every ID refers to a column on boardID
+func (s *KanbanService) ColumnsInBoard(boardID int64, columnIDs []int64) error {
+	for _, id := range columnIDs {
+		if _, err := s.ColumnInBoard(boardID, id); err != nil {
+			return ErrNotInBoard
+		}
+	}
+	return nil
+}
+
+// ChecklistItemsInCard checks every ID refers to an item on cardID
+func (s *KanbanService) ChecklistItemsInCard(cardID int64, itemIDs []int64) error {
+	for _, id := range itemIDs {
+		item, err := s.ChecklistRepo.GetByID(id)
+		if err != nil || item.CardID != cardID {
+			return ErrNotInBoard
+		}
+	}
+	return nil
+}
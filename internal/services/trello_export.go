@@ -0,0 +1,203 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"krizzy/internal/models"
+)
+
+// trelloCoverSuffix marks the link a Trello import made from a card's cover
+// image
+const trelloCoverSuffix = " (cover)"
+
+// ExportTrello writes a board as Trello board JSON, in the shape the Trello
+// importer reads. Archived cards are exported as closed cards. Done columns
+// and card completion go in a "krizzy" object that Trello ignores. Swimlanes,
+// card templates, recurrences and custom fields other than the ones a Trello
+// import creates are not part of Trello's format and are left out.
+func (s *KanbanService) ExportTrello(w io.Writer, boardID int64) error {
+	board, err := s.GetBoardWithData(boardID)
+	if err != nil {
+		return err
+	}
+
+	// Columns hold summary cards; exports need comments and links as well
+	for i := range board.Columns {
+		for j := range board.Columns[i].Cards {
+			card, err := s.GetCardWithDetails(board.Columns[i].Cards[j].ID)
+			if err != nil {
+				return err
+			}
+			board.Columns[i].Cards[j] = *card
+		}
+	}
+
+	archived, err := s.CardRepo.GetArchivedByBoardID(boardID)
+	if err != nil {
+		return err
+	}
+	for i := range archived {
+		card, err := s.GetCardWithDetails(archived[i].ID)
+		if err != nil {
+			return err
+		}
+		archived[i] = *card
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(buildTrelloExport(board, archived))
+}
+
+// buildTrelloExport maps a board with card details loaded onto Trello's
+// export structures. archived cards go back into their columns as closed
+// cards.
+func buildTrelloExport(board *models.Board, archived []models.Card) trelloBoardExport {
+	export := trelloBoardExport{
+		ID:   fmt.Sprintf("krizzy-board-%d", board.ID),
+		Name: board.Name,
+	}
+
+	var labelsField, dueField, dueCompleteField, coverField *models.CustomField
+	for i := range board.Fields {
+		field := &board.Fields[i]
+		switch {
+		case field.Name == trelloLabelsField && field.HasOptions():
+			labelsField = field
+		case field.Name == trelloDueField && field.Type == models.FieldTypeDate:
+			dueField = field
+		case field.Name == trelloDueCompleteField && field.Type == models.FieldTypeCheckbox:
+			dueCompleteField = field
+		case field.Name == trelloCoverField && field.Type == models.FieldTypeSingleSelect:
+			coverField = field
+		}
+	}
+
+	labelIDs := make(map[string]string)
+	if labelsField != nil {
+		for i, option := range labelsField.Options {
+			id := fmt.Sprintf("label-%d", i+1)
+			labelIDs[option] = id
+			export.Labels = append(export.Labels, trelloLabel{ID: id, Name: option})
+		}
+	}
+
+	archivedByColumn := make(map[int64][]models.Card)
+	for _, card := range archived {
+		archivedByColumn[card.ColumnID] = append(archivedByColumn[card.ColumnID], card)
+	}
+
+	members := make(map[int64]bool)
+	for i, column := range board.Columns {
+		listID := fmt.Sprintf("list-%d", column.ID)
+		export.Lists = append(export.Lists, trelloList{
+			ID:     listID,
+			Name:   column.Name,
+			Pos:    float64(i + 1),
+			Krizzy: &trelloKrizzyList{Done: column.IsDoneColumn, Resolution: column.Resolution},
+		})
+
+		cards := append(append([]models.Card(nil), column.Cards...), archivedByColumn[column.ID]...)
+		for j, card := range cards {
+			cardID := fmt.Sprintf("card-%d", card.ID)
+			exported := trelloCard{
+				ID:     cardID,
+				IDList: listID,
+				Name:   card.Title,
+				Desc:   card.Description,
+				Pos:    float64(j + 1),
+				Closed: card.ArchivedAt != nil,
+			}
+			if card.CompletedAt != nil {
+				exported.Krizzy = &trelloKrizzyCard{
+					CompletedAt: card.CompletedAt.UTC().Format(time.RFC3339Nano),
+					Resolution:  card.Resolution,
+				}
+			}
+
+			for _, person := range card.Assignees {
+				memberID := fmt.Sprintf("member-%d", person.ID)
+				exported.IDMembers = append(exported.IDMembers, memberID)
+				if !members[person.ID] {
+					members[person.ID] = true
+					export.Members = append(export.Members, trelloMember{ID: memberID, FullName: person.Name})
+				}
+			}
+
+			if labelsField != nil {
+				for _, label := range models.FieldValueList(labelsField.Type, card.FieldValues[labelsField.ID]) {
+					if id, ok := labelIDs[label]; ok {
+						exported.IDLabels = append(exported.IDLabels, id)
+					}
+				}
+			}
+			if dueField != nil {
+				if day, err := time.Parse("2006-01-02", card.FieldValues[dueField.ID]); err == nil {
+					due := day.UTC().Format(time.RFC3339Nano)
+					exported.Due = &due
+				}
+			}
+			if dueCompleteField != nil {
+				exported.DueComplete = card.FieldValues[dueCompleteField.ID] == "true"
+			}
+			if coverField != nil {
+				exported.Cover.Color = card.FieldValues[coverField.ID]
+			}
+
+			for _, link := range card.Links {
+				attachment := trelloAttachment{
+					ID:   fmt.Sprintf("attachment-%d", link.ID),
+					Name: link.Title,
+					URL:  link.URL,
+				}
+				if name, ok := strings.CutSuffix(link.Title, trelloCoverSuffix); ok && exported.Cover.IDAttachment == "" {
+					attachment.Name = name
+					exported.Cover.IDAttachment = attachment.ID
+				}
+				exported.Attachments = append(exported.Attachments, attachment)
+			}
+
+			for _, group := range exportChecklists(card.Checklist) {
+				checklistID := fmt.Sprintf("checklist-%d-%d", card.ID, len(exported.IDChecklists)+1)
+				checklist := trelloChecklist{
+					ID:     checklistID,
+					IDCard: cardID,
+					Name:   group[0].Checklist,
+					Pos:    float64(len(exported.IDChecklists) + 1),
+				}
+				for k, item := range group {
+					state := "incomplete"
+					if item.IsCompleted {
+						state = "complete"
+					}
+					checklist.CheckItems = append(checklist.CheckItems, trelloCheckItem{
+						Name:  item.Content,
+						Pos:   float64(k + 1),
+						State: state,
+					})
+				}
+				exported.IDChecklists = append(exported.IDChecklists, checklistID)
+				export.Checklists = append(export.Checklists, checklist)
+			}
+
+			for _, comment := range card.Comments {
+				export.Actions = append(export.Actions, trelloAction{
+					Type: "commentCard",
+					Date: comment.CreatedAt.UTC().Format(time.RFC3339Nano),
+					Data: trelloActionData{
+						Text: comment.Content,
+						Card: trelloActionCard{ID: cardID},
+					},
+				})
+			}
+
+			export.Cards = append(export.Cards, exported)
+		}
+	}
+
+	return export
+}
@@ -0,0 +1,280 @@
+package services
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"krizzy/internal/models"
+)
+
+func roundTripBoard() (*models.Board, []models.Card) {
+	commentedAt := time.Date(2024, 3, 4, 9, 30, 15, 123000000, time.UTC)
+	archivedAt := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	completedAt := time.Date(2024, 3, 6, 17, 45, 0, 0, time.UTC)
+	ann := models.Person{ID: 1, Name: "Ann"}
+	bob := models.Person{ID: 2, Name: "Bob"}
+
+	board := &models.Board{
+		ID:   7,
+		Name: "Launch",
+		Fields: []models.CustomField{
+			{ID: 1, Name: trelloLabelsField, Type: models.FieldTypeMultiSelect, Options: []string{"Bug", "Feature"}},
+			{ID: 2, Name: trelloDueField, Type: models.FieldTypeDate},
+			{ID: 3, Name: trelloDueCompleteField, Type: models.FieldTypeCheckbox},
+			{ID: 4, Name: trelloCoverField, Type: models.FieldTypeSingleSelect, Options: []string{"green"}},
+		},
+		Columns: []models.Column{
+			{ID: 10, Name: "To Do", Cards: []models.Card{
+				{
+					ID:          100,
+					ColumnID:    10,
+					Title:       "Write launch post",
+					Description: "Draft, then review.\nKeep it short.",
+					Assignees:   []models.Person{ann, bob},
+					Checklist: []models.ChecklistItem{
+						{Checklist: "Draft", Content: "Outline", IsCompleted: true},
+						{Checklist: "Draft", Content: "First pass"},
+						{Checklist: "Review", Content: "Legal"},
+					},
+					Comments: []models.Comment{
+						{Content: "Started", CreatedAt: commentedAt},
+						{Content: "Halfway", CreatedAt: commentedAt.Add(time.Hour)},
+					},
+					Links: []models.CardLink{
+						{ID: 1, Title: "Mockup (cover)", URL: "https://example.com/mockup.png"},
+						{ID: 2, Title: "Spec", URL: "https://example.com/spec"},
+					},
+					FieldValues: map[int64]string{
+						1: `["Bug","Feature"]`,
+						2: "2024-03-08",
+						3: "true",
+					},
+				},
+				{
+					ID:          101,
+					ColumnID:    10,
+					Title:       "Plain card",
+					Checklist:   []models.ChecklistItem{{Content: "Unnamed list item"}},
+					FieldValues: map[int64]string{4: "green"},
+				},
+			}},
+			// Only the export says this is a done column; its name does not
+			{ID: 11, Name: "Shipped", IsDoneColumn: true, Resolution: "Released", Cards: []models.Card{
+				{ID: 103, ColumnID: 11, Title: "Pick a date", Assignees: []models.Person{ann}, CompletedAt: &completedAt, Resolution: "Released"},
+			}},
+		},
+	}
+	archived := []models.Card{
+		{ID: 102, ColumnID: 10, Title: "Old idea", ArchivedAt: &archivedAt},
+	}
+	return board, archived
+}
+
+func TestTrelloExportRoundTrip(t *testing.T) {
+	board, archived := roundTripBoard()
+
+	data, err := json.Marshal(buildTrelloExport(board, archived))
+	if err != nil {
+		t.Fatalf("marshal export: %v", err)
+	}
+
+	importer := trelloImporter{}
+	if !importer.Detect(data) {
+		t.Fatal("Trello importer does not detect the export")
+	}
+	plan, err := importer.Plan(data)
+	if err != nil {
+		t.Fatalf("plan export: %v", err)
+	}
+
+	if plan.BoardName != board.Name {
+		t.Errorf("board name = %q, want %q", plan.BoardName, board.Name)
+	}
+	if want := []string{"Ann", "Bob"}; !reflect.DeepEqual(plan.People, want) {
+		t.Errorf("people = %v, want %v", plan.People, want)
+	}
+
+	fields := make(map[string]models.CustomField)
+	for _, field := range plan.Fields {
+		fields[field.Name] = field
+	}
+	for _, want := range board.Fields {
+		got, ok := fields[want.Name]
+		if !ok {
+			t.Errorf("field %q missing", want.Name)
+			continue
+		}
+		if got.Type != want.Type || !reflect.DeepEqual(got.Options, want.Options) {
+			t.Errorf("field %q = %s %v, want %s %v", want.Name, got.Type, got.Options, want.Type, want.Options)
+		}
+	}
+
+	if len(plan.Columns) != len(board.Columns) {
+		t.Fatalf("got %d columns, want %d", len(plan.Columns), len(board.Columns))
+	}
+	for i, column := range board.Columns {
+		planned := plan.Columns[i]
+		if planned.Name != column.Name {
+			t.Errorf("column %d = %q, want %q", i, planned.Name, column.Name)
+		}
+
+		want := append([]models.Card(nil), column.Cards...)
+		for _, card := range archived {
+			if card.ColumnID == column.ID {
+				want = append(want, card)
+			}
+		}
+		if len(planned.Cards) != len(want) {
+			t.Fatalf("column %q has %d cards, want %d", column.Name, len(planned.Cards), len(want))
+		}
+		for j, card := range want {
+			assertRoundTripCard(t, board.Fields, card, planned.Cards[j])
+		}
+	}
+
+	for i, column := range board.Columns {
+		if plan.Columns[i].IsDone != column.IsDoneColumn || plan.Columns[i].Resolution != column.Resolution {
+			t.Errorf("column %q done = %v/%q, want %v/%q", column.Name, plan.Columns[i].IsDone, plan.Columns[i].Resolution, column.IsDoneColumn, column.Resolution)
+		}
+	}
+}
+
+func assertRoundTripCard(t *testing.T, fields []models.CustomField, want models.Card, got ImportCard) {
+	t.Helper()
+
+	if got.Title != want.Title || got.Description != want.Description {
+		t.Errorf("card %d = %q/%q, want %q/%q", want.ID, got.Title, got.Description, want.Title, want.Description)
+	}
+	if (got.CompletedAt == nil) != (want.CompletedAt == nil) || got.CompletedAt != nil && !got.CompletedAt.Equal(*want.CompletedAt) || got.Resolution != want.Resolution {
+		t.Errorf("card %q completed at %v/%q, want %v/%q", want.Title, got.CompletedAt, got.Resolution, want.CompletedAt, want.Resolution)
+	}
+	if got.Archived != (want.ArchivedAt != nil) {
+		t.Errorf("card %q archived = %v, want %v", want.Title, got.Archived, want.ArchivedAt != nil)
+	}
+
+	var assignees []string
+	for _, person := range want.Assignees {
+		assignees = append(assignees, person.Name)
+	}
+	if !reflect.DeepEqual(got.Assignees, assignees) {
+		t.Errorf("card %q assignees = %v, want %v", want.Title, got.Assignees, assignees)
+	}
+
+	var checklist []models.ChecklistItem
+	for _, item := range want.Checklist {
+		checklist = append(checklist, models.ChecklistItem{Checklist: item.Checklist, Content: item.Content, IsCompleted: item.IsCompleted})
+	}
+	if !reflect.DeepEqual(got.Checklist, checklist) {
+		t.Errorf("card %q checklist = %+v, want %+v", want.Title, got.Checklist, checklist)
+	}
+
+	var comments []models.Comment
+	for _, comment := range want.Comments {
+		comments = append(comments, models.Comment{Content: comment.Content, CreatedAt: comment.CreatedAt})
+	}
+	if len(got.Comments) != len(comments) {
+		t.Errorf("card %q has %d comments, want %d", want.Title, len(got.Comments), len(comments))
+	} else {
+		for i := range comments {
+			if got.Comments[i].Content != comments[i].Content || !got.Comments[i].CreatedAt.Equal(comments[i].CreatedAt) {
+				t.Errorf("card %q comment %d = %+v, want %+v", want.Title, i, got.Comments[i], comments[i])
+			}
+		}
+	}
+
+	var links []models.CardLink
+	for _, link := range want.Links {
+		links = append(links, models.CardLink{Title: link.Title, URL: link.URL})
+	}
+	if !reflect.DeepEqual(got.Links, links) {
+		t.Errorf("card %q links = %+v, want %+v", want.Title, got.Links, links)
+	}
+
+	for _, field := range fields {
+		wantValue := want.FieldValues[field.ID]
+		gotValue, err := NormalizeFieldValue(&field, got.FieldValues[field.Name])
+		if err != nil {
+			t.Errorf("card %q field %q: %v", want.Title, field.Name, err)
+			continue
+		}
+		if gotValue != wantValue {
+			t.Errorf("card %q field %q = %q, want %q", want.Title, field.Name, gotValue, wantValue)
+		}
+	}
+}
+
+func TestTrelloExportOfImportIsStable(t *testing.T) {
+	board, archived := roundTripBoard()
+	first, err := json.Marshal(buildTrelloExport(board, archived))
+	if err != nil {
+		t.Fatalf("marshal export: %v", err)
+	}
+
+	plan, err := trelloImporter{}.Plan(first)
+	if err != nil {
+		t.Fatalf("plan export: %v", err)
+	}
+
+	// Rebuild the board the plan would create, with the IDs it would get
+	rebuilt := &models.Board{ID: board.ID, Name: plan.BoardName}
+	for i, field := range plan.Fields {
+		field.ID = int64(i + 1)
+		rebuilt.Fields = append(rebuilt.Fields, field)
+	}
+	personIDs := make(map[string]int64)
+	for i, name := range plan.People {
+		personIDs[name] = int64(i + 1)
+	}
+
+	var rebuiltArchived []models.Card
+	cardID, linkID := int64(100), int64(1)
+	for i, planned := range plan.Columns {
+		column := models.Column{ID: int64(10 + i), Name: planned.Name, IsDoneColumn: planned.IsDone, Resolution: planned.Resolution}
+		for _, plannedCard := range planned.Cards {
+			card := models.Card{
+				ID:          cardID,
+				ColumnID:    column.ID,
+				Title:       plannedCard.Title,
+				Description: plannedCard.Description,
+				CompletedAt: plannedCard.CompletedAt,
+				Resolution:  plannedCard.Resolution,
+				Checklist:   plannedCard.Checklist,
+				Comments:    plannedCard.Comments,
+				FieldValues: make(map[int64]string),
+			}
+			cardID++
+			for _, name := range plannedCard.Assignees {
+				card.Assignees = append(card.Assignees, models.Person{ID: personIDs[name], Name: name})
+			}
+			for _, link := range plannedCard.Links {
+				link.ID = linkID
+				linkID++
+				card.Links = append(card.Links, link)
+			}
+			for i := range rebuilt.Fields {
+				field := &rebuilt.Fields[i]
+				if value, err := NormalizeFieldValue(field, plannedCard.FieldValues[field.Name]); err == nil && value != "" {
+					card.FieldValues[field.ID] = value
+				}
+			}
+			if plannedCard.Archived {
+				archivedAt := time.Now()
+				card.ArchivedAt = &archivedAt
+				rebuiltArchived = append(rebuiltArchived, card)
+				continue
+			}
+			column.Cards = append(column.Cards, card)
+		}
+		rebuilt.Columns = append(rebuilt.Columns, column)
+	}
+
+	second, err := json.Marshal(buildTrelloExport(rebuilt, rebuiltArchived))
+	if err != nil {
+		t.Fatalf("marshal second export: %v", err)
+	}
+	if string(first) != string(second) {
+		t.Errorf("export changed after a round trip:\nfirst:  %s\nsecond: %s", first, second)
+	}
+}
@@ -1,26 +1,29 @@
 package services
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
-	"io"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
 	"krizzy/internal/models"
+	"krizzy/internal/validation"
 )
 
-type TrelloImportService struct {
-	bm *BoardManager
-}
+// trelloImporter reads Trello's board JSON export
+type trelloImporter struct{}
 
-func NewTrelloImportService(bm *BoardManager) *TrelloImportService {
-	return &TrelloImportService{bm: bm}
-}
+func (trelloImporter) Format() string { return "trello" }
+
+func (trelloImporter) Label() string { return "Trello JSON" }
 
 type trelloBoardExport struct {
+	ID         string            `json:"id"`
 	Name       string            `json:"name"`
+	Labels     []trelloLabel     `json:"labels"`
 	Lists      []trelloList      `json:"lists"`
 	Cards      []trelloCard      `json:"cards"`
 	Checklists []trelloChecklist `json:"checklists"`
@@ -28,22 +31,59 @@ type trelloBoardExport struct {
 	Actions    []trelloAction    `json:"actions"`
 }
 
+type trelloLabel struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
 type trelloList struct {
-	ID     string  `json:"id"`
-	Name   string  `json:"name"`
-	Pos    float64 `json:"pos"`
-	Closed bool    `json:"closed"`
+	ID     string            `json:"id"`
+	Name   string            `json:"name"`
+	Pos    float64           `json:"pos"`
+	Closed bool              `json:"closed"`
+	Krizzy *trelloKrizzyList `json:"krizzy,omitempty"`
 }
 
 type trelloCard struct {
-	ID           string   `json:"id"`
-	IDList       string   `json:"idList"`
-	Name         string   `json:"name"`
-	Desc         string   `json:"desc"`
-	Pos          float64  `json:"pos"`
-	Closed       bool     `json:"closed"`
-	IDMembers    []string `json:"idMembers"`
-	IDChecklists []string `json:"idChecklists"`
+	ID           string             `json:"id"`
+	IDList       string             `json:"idList"`
+	Name         string             `json:"name"`
+	Desc         string             `json:"desc"`
+	Pos          float64            `json:"pos"`
+	Closed       bool               `json:"closed"`
+	IDMembers    []string           `json:"idMembers"`
+	IDChecklists []string           `json:"idChecklists"`
+	IDLabels     []string           `json:"idLabels"`
+	Due          *string            `json:"due"`
+	DueComplete  bool               `json:"dueComplete"`
+	Attachments  []trelloAttachment `json:"attachments"`
+	Cover        trelloCover        `json:"cover"`
+	Krizzy       *trelloKrizzyCard  `json:"krizzy,omitempty"`
+}
+
+// trelloKrizzyList and trelloKrizzyCard carry what krizzy's own Trello
+// exports add for done columns, which Trello does not have. Lists without
+// it are done when they are called "Done".
+type trelloKrizzyList struct {
+	Done       bool   `json:"done"`
+	Resolution string `json:"resolution,omitempty"`
+}
+
+type trelloKrizzyCard struct {
+	CompletedAt string `json:"completedAt,omitempty"`
+	Resolution  string `json:"resolution,omitempty"`
+}
+
+type trelloAttachment struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type trelloCover struct {
+	Color        string `json:"color"`
+	IDAttachment string `json:"idAttachment"`
 }
 
 type trelloChecklist struct {
@@ -81,49 +121,124 @@ type trelloActionCard struct {
 	ID string `json:"id"`
 }
 
-func (s *TrelloImportService) ImportBoard(r io.Reader, boardName, dbType string, pgConnectionID *int64, pgDatabaseName string) (*models.Board, error) {
+// Custom fields created for Trello card data krizzy has no built-in place for
+const (
+	trelloLabelsField      = "Labels"
+	trelloDueField         = "Due"
+	trelloDueCompleteField = "Due complete"
+	trelloCoverField       = "Cover"
+)
+
+// Detect looks for a JSON object with Trello's board id and lists
+func (trelloImporter) Detect(data []byte) bool {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return false
+	}
+	var probe struct {
+		ID    string            `json:"id"`
+		Lists []json.RawMessage `json:"lists"`
+	}
+	if err := json.Unmarshal(trimmed, &probe); err != nil {
+		return false
+	}
+	return probe.ID != "" && probe.Lists != nil
+}
+
+// Plan imports every list and card. Archived cards come in archived, and
+// cards in archived lists are archived into the first open column. Labels,
+// due dates and cover colours become custom fields and attachments become
+// card links. Comment authors are not kept.
+func (trelloImporter) Plan(data []byte) (*ImportPlan, error) {
 	var export trelloBoardExport
-	if err := json.NewDecoder(r).Decode(&export); err != nil {
+	if err := json.Unmarshal(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")), &export); err != nil {
 		return nil, fmt.Errorf("failed to parse Trello export: %w", err)
 	}
 
-	importName := strings.TrimSpace(boardName)
-	if importName == "" {
-		importName = strings.TrimSpace(export.Name)
-	}
-	if importName == "" {
-		return nil, fmt.Errorf("board name is required")
+	plan := &ImportPlan{BoardName: strings.TrimSpace(export.Name)}
+	warn := func(format string, args ...any) {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf(format, args...))
 	}
 
-	activeLists := make([]trelloList, 0, len(export.Lists))
-	activeListIDs := make(map[string]struct{}, len(export.Lists))
-	for _, list := range export.Lists {
+	lists := append([]trelloList(nil), export.Lists...)
+	sort.SliceStable(lists, func(i, j int) bool {
+		return lists[i].Pos < lists[j].Pos
+	})
+	activeLists := make([]trelloList, 0, len(lists))
+	closedListIDs := make(map[string]struct{})
+	var closedLists []string
+	for _, list := range lists {
 		if list.Closed {
+			name := strings.TrimSpace(list.Name)
+			if name == "" {
+				name = "Untitled"
+			}
+			closedListIDs[list.ID] = struct{}{}
+			closedLists = append(closedLists, name)
 			continue
 		}
 		activeLists = append(activeLists, list)
-		activeListIDs[list.ID] = struct{}{}
 	}
 	if len(activeLists) == 0 {
 		return nil, fmt.Errorf("no open lists found in Trello export")
 	}
-	sort.Slice(activeLists, func(i, j int) bool {
-		return activeLists[i].Pos < activeLists[j].Pos
-	})
+	// Cards whose list is archived or missing land in the first open column
+	fallbackListID := activeLists[0].ID
+	activeListIDs := make(map[string]struct{}, len(activeLists))
+	for _, list := range activeLists {
+		activeListIDs[list.ID] = struct{}{}
+	}
 
-	activeCards := make([]trelloCard, 0, len(export.Cards))
-	usedMemberIDs := make(map[string]struct{})
-	for _, card := range export.Cards {
-		if card.Closed {
-			continue
+	labelNames := make(map[string]string, len(export.Labels))
+	var labelOptions []string
+	for _, label := range export.Labels {
+		name := strings.TrimSpace(label.Name)
+		if name == "" {
+			name = strings.TrimSpace(label.Color)
 		}
-		if _, ok := activeListIDs[card.IDList]; !ok {
+		if name == "" {
 			continue
 		}
-		activeCards = append(activeCards, card)
+		labelNames[label.ID] = name
+		if !slices.Contains(labelOptions, name) {
+			labelOptions = append(labelOptions, name)
+		}
+	}
+
+	usedMemberIDs := make(map[string]struct{})
+	var coverColors []string
+	labelled, due, dueComplete := false, false, false
+	for _, card := range export.Cards {
 		for _, memberID := range card.IDMembers {
 			usedMemberIDs[memberID] = struct{}{}
 		}
+		for _, labelID := range card.IDLabels {
+			if labelNames[labelID] != "" {
+				labelled = true
+			}
+		}
+		if trelloDueDate(card) != "" {
+			due = true
+		}
+		if card.DueComplete {
+			dueComplete = true
+		}
+		if color := strings.TrimSpace(card.Cover.Color); color != "" && !slices.Contains(coverColors, color) {
+			coverColors = append(coverColors, color)
+		}
+	}
+	if labelled {
+		plan.Fields = append(plan.Fields, models.CustomField{Name: trelloLabelsField, Type: models.FieldTypeMultiSelect, Options: labelOptions})
+	}
+	if due {
+		plan.Fields = append(plan.Fields, models.CustomField{Name: trelloDueField, Type: models.FieldTypeDate})
+	}
+	if dueComplete {
+		plan.Fields = append(plan.Fields, models.CustomField{Name: trelloDueCompleteField, Type: models.FieldTypeCheckbox})
+	}
+	if len(coverColors) > 0 {
+		sort.Strings(coverColors)
+		plan.Fields = append(plan.Fields, models.CustomField{Name: trelloCoverField, Type: models.FieldTypeSingleSelect, Options: coverColors})
 	}
 
 	memberNames := make(map[string]string, len(export.Members))
@@ -146,23 +261,6 @@ func (s *TrelloImportService) ImportBoard(r io.Reader, boardName, dbType string,
 		usedNames[name] = struct{}{}
 	}
 
-	board, err := s.bm.CreateBoardWithoutDefaults(importName, dbType, pgConnectionID, pgDatabaseName)
-	if err != nil {
-		return nil, err
-	}
-	cleanup := true
-	defer func() {
-		if cleanup {
-			_ = s.bm.DeleteBoard(board.ID)
-		}
-	}()
-
-	svc, err := s.bm.GetServiceForBoard(board.ID)
-	if err != nil {
-		return nil, err
-	}
-
-	personIDsByTrelloID := make(map[string]int64, len(usedMemberIDs))
 	memberIDList := make([]string, 0, len(usedMemberIDs))
 	for memberID := range usedMemberIDs {
 		memberIDList = append(memberIDList, memberID)
@@ -170,19 +268,17 @@ func (s *TrelloImportService) ImportBoard(r io.Reader, boardName, dbType string,
 	sort.Slice(memberIDList, func(i, j int) bool {
 		return memberNames[memberIDList[i]] < memberNames[memberIDList[j]]
 	})
+	unknownMembers := 0
 	for _, memberID := range memberIDList {
 		name := memberNames[memberID]
 		if name == "" {
+			unknownMembers++
 			continue
 		}
-		person := &models.Person{
-			BoardID: board.ID,
-			Name:    name,
-		}
-		if err := svc.PersonRepo.Create(person); err != nil {
-			return nil, fmt.Errorf("failed to import person %q: %w", name, err)
-		}
-		personIDsByTrelloID[memberID] = person.ID
+		plan.People = append(plan.People, name)
+	}
+	if unknownMembers > 0 {
+		warn("%d card members without a name will not be assigned", unknownMembers)
 	}
 
 	checklistsByCardID := make(map[string][]trelloChecklist)
@@ -232,125 +328,159 @@ func (s *TrelloImportService) ImportBoard(r io.Reader, boardName, dbType string,
 	}
 
 	cardsByListID := make(map[string][]trelloCard)
-	for _, card := range activeCards {
-		cardsByListID[card.IDList] = append(cardsByListID[card.IDList], card)
+	closedListCards, unknownListCards := 0, 0
+	for _, card := range export.Cards {
+		listID := card.IDList
+		if _, ok := activeListIDs[listID]; !ok {
+			listID = fallbackListID
+			if _, ok := closedListIDs[card.IDList]; ok {
+				closedListCards++
+			} else {
+				unknownListCards++
+			}
+		}
+		cardsByListID[listID] = append(cardsByListID[listID], card)
 	}
 	for listID := range cardsByListID {
-		sort.Slice(cardsByListID[listID], func(i, j int) bool {
+		sort.SliceStable(cardsByListID[listID], func(i, j int) bool {
 			return cardsByListID[listID][i].Pos < cardsByListID[listID][j].Pos
 		})
 	}
+	// Archived lists are listed even when empty, so the preview accounts for
+	// every list in the export
+	if len(closedLists) > 0 {
+		warn("%d archived lists (%s) will not become columns", len(closedLists), strings.Join(closedLists, ", "))
+	}
+	first := strings.TrimSpace(activeLists[0].Name)
+	if first == "" {
+		first = "Untitled"
+	}
+	if closedListCards > 0 {
+		warn("%d cards from archived lists will be archived in %q", closedListCards, first)
+	}
+	if unknownListCards > 0 {
+		warn("%d cards from unknown lists will be archived in %q", unknownListCards, first)
+	}
 
-	now := time.Now()
+	comments, badLinks := 0, 0
 	for _, list := range activeLists {
-		column := &models.Column{
-			BoardID:      board.ID,
-			Name:         strings.TrimSpace(list.Name),
-			IsDoneColumn: strings.EqualFold(strings.TrimSpace(list.Name), "done"),
+		column := ImportColumn{
+			Source: list.Name,
+			Name:   strings.TrimSpace(list.Name),
+			IsDone: strings.EqualFold(strings.TrimSpace(list.Name), "done"),
+		}
+		if list.Krizzy != nil {
+			column.IsDone = list.Krizzy.Done
+			column.Resolution = strings.TrimSpace(list.Krizzy.Resolution)
 		}
 		if column.Name == "" {
 			column.Name = "Untitled"
 		}
-		if err := svc.ColumnRepo.Create(column); err != nil {
-			return nil, fmt.Errorf("failed to import list %q: %w", list.Name, err)
-		}
 
 		for _, cardData := range cardsByListID[list.ID] {
-			card := &models.Card{
-				ColumnID:    column.ID,
+			_, inOpenList := activeListIDs[cardData.IDList]
+			card := ImportCard{
 				Title:       strings.TrimSpace(cardData.Name),
 				Description: cardData.Desc,
+				Checklist:   trelloChecklistItems(checklistsByCardID[cardData.ID]),
+				Comments:    commentsByCardID[cardData.ID],
+				FieldValues: make(map[string][]string),
+				Archived:    cardData.Closed || !inOpenList,
 			}
 			if card.Title == "" {
 				card.Title = "Untitled"
 			}
-			if err := svc.CardRepo.Create(card); err != nil {
-				return nil, fmt.Errorf("failed to import card %q: %w", card.Title, err)
-			}
-
-			if column.IsDoneColumn {
-				card.CompletedAt = &now
-				if err := svc.CardRepo.Update(card); err != nil {
-					return nil, fmt.Errorf("failed to mark imported done card %q: %w", card.Title, err)
+			if cardData.Krizzy != nil {
+				if completedAt, err := time.Parse(time.RFC3339, cardData.Krizzy.CompletedAt); err == nil {
+					card.CompletedAt = &completedAt
 				}
+				card.Resolution = strings.TrimSpace(cardData.Krizzy.Resolution)
 			}
-
-			if err := s.importChecklistItems(svc, card.ID, checklistsByCardID[cardData.ID]); err != nil {
-				return nil, fmt.Errorf("failed to import checklist for %q: %w", card.Title, err)
-			}
-
-			assigneeIDs := make([]int64, 0, len(cardData.IDMembers))
 			for _, memberID := range cardData.IDMembers {
-				personID, ok := personIDsByTrelloID[memberID]
-				if ok {
-					assigneeIDs = append(assigneeIDs, personID)
+				if name := memberNames[memberID]; name != "" {
+					card.Assignees = append(card.Assignees, name)
 				}
 			}
-			if len(assigneeIDs) > 0 {
-				if err := svc.PersonRepo.SetCardAssignees(card.ID, assigneeIDs); err != nil {
-					return nil, fmt.Errorf("failed to import assignees for %q: %w", card.Title, err)
+			for _, labelID := range cardData.IDLabels {
+				if name := labelNames[labelID]; name != "" {
+					card.FieldValues[trelloLabelsField] = append(card.FieldValues[trelloLabelsField], name)
 				}
 			}
-
-			if err := s.importComments(svc, card.ID, commentsByCardID[cardData.ID]); err != nil {
-				return nil, fmt.Errorf("failed to import comments for %q: %w", card.Title, err)
+			if dueDate := trelloDueDate(cardData); dueDate != "" {
+				card.FieldValues[trelloDueField] = []string{dueDate}
+			}
+			if cardData.DueComplete {
+				card.FieldValues[trelloDueCompleteField] = []string{"true"}
+			}
+			if color := strings.TrimSpace(cardData.Cover.Color); color != "" {
+				card.FieldValues[trelloCoverField] = []string{color}
+			}
+			for _, attachment := range cardData.Attachments {
+				linkURL, ok := validation.NormalizeLinkURL(attachment.URL)
+				if !ok {
+					badLinks++
+					continue
+				}
+				title := strings.TrimSpace(attachment.Name)
+				if title == "" {
+					title = linkURL
+				}
+				if attachment.ID != "" && attachment.ID == cardData.Cover.IDAttachment {
+					title += trelloCoverSuffix
+				}
+				card.Links = append(card.Links, models.CardLink{Title: title, URL: linkURL})
 			}
+			comments += len(card.Comments)
+			column.Cards = append(column.Cards, card)
 		}
+		plan.Columns = append(plan.Columns, column)
+	}
+	if badLinks > 0 {
+		warn("%d attachments without a web link will be dropped", badLinks)
+	}
+	if comments > 0 {
+		warn("comment authors will not be kept")
 	}
 
-	cleanup = false
-	return board, nil
+	return plan, nil
 }
 
-func (s *TrelloImportService) importChecklistItems(svc *KanbanService, cardID int64, checklists []trelloChecklist) error {
-	if len(checklists) == 0 {
-		return nil
+// trelloDueDate returns a card's due date as YYYY-MM-DD, or "" when it has
+// none
+func trelloDueDate(card trelloCard) string {
+	if card.Due == nil || *card.Due == "" {
+		return ""
 	}
+	parsed, err := time.Parse(time.RFC3339, *card.Due)
+	if err != nil {
+		return ""
+	}
+	return parsed.UTC().Format("2006-01-02")
+}
 
-	multipleChecklists := len(checklists) > 1
+// trelloChecklistItems lists a card's checklist items in order, each keeping
+// the name of the checklist it belongs to
+func trelloChecklistItems(checklists []trelloChecklist) []models.ChecklistItem {
+	var result []models.ChecklistItem
 	for _, checklist := range checklists {
 		items := append([]trelloCheckItem(nil), checklist.CheckItems...)
 		sort.Slice(items, func(i, j int) bool {
 			return items[i].Pos < items[j].Pos
 		})
 
+		name := strings.TrimSpace(checklist.Name)
 		for _, item := range items {
 			content := strings.TrimSpace(item.Name)
 			if content == "" {
 				continue
 			}
-			if multipleChecklists {
-				checklistName := strings.TrimSpace(checklist.Name)
-				if checklistName != "" {
-					content = checklistName + ": " + content
-				}
-			}
-
-			checklistItem := &models.ChecklistItem{
-				CardID:      cardID,
+			result = append(result, models.ChecklistItem{
+				Checklist:   name,
 				Content:     content,
 				IsCompleted: item.State == "complete",
-			}
-			if err := svc.ChecklistRepo.Create(checklistItem); err != nil {
-				return err
-			}
-		}
-	}
-
-	return nil
-}
-
-func (s *TrelloImportService) importComments(svc *KanbanService, cardID int64, comments []models.Comment) error {
-	for _, commentData := range comments {
-		comment := &models.Comment{
-			CardID:    cardID,
-			Content:   commentData.Content,
-			CreatedAt: commentData.CreatedAt,
-		}
-		if err := svc.CommentRepo.Create(comment); err != nil {
-			return err
+			})
 		}
 	}
 
-	return nil
+	return result
 }
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTrelloPlanNotesArchivedLists(t *testing.T) {
+	data := []byte(`{
+		"id": "b1",
+		"name": "Attic",
+		"lists": [
+			{"id": "l1", "name": "To Do", "pos": 1},
+			{"id": "l2", "name": "Old ideas", "pos": 2, "closed": true},
+			{"id": "l3", "name": "Empty", "pos": 3, "closed": true}
+		],
+		"cards": [
+			{"id": "c1", "idList": "l1", "name": "Open"},
+			{"id": "c2", "idList": "l2", "name": "Shelved"},
+			{"id": "c3", "idList": "gone", "name": "Lost"}
+		]
+	}`)
+
+	plan, err := trelloImporter{}.Plan(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		`2 archived lists (Old ideas, Empty) will not become columns`,
+		`1 cards from archived lists will be archived in "To Do"`,
+		`1 cards from unknown lists will be archived in "To Do"`,
+	}
+	if !reflect.DeepEqual(plan.Warnings, want) {
+		t.Errorf("warnings = %q, want %q", plan.Warnings, want)
+	}
+	if len(plan.Columns) != 1 || len(plan.Columns[0].Cards) != 3 {
+		t.Fatalf("got %d columns, want one with all 3 cards", len(plan.Columns))
+	}
+}
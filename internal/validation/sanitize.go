@@ -2,6 +2,7 @@ package validation
 
 import (
 	"krizzy/internal/models"
+	"net/url"
 	"regexp"
 	"strings"
 )
@@ -20,3 +21,17 @@ func NormalizePersonColor(color string) string {
 	}
 	return models.DefaultPersonColor
 }
+
+// NormalizeLinkURL returns url trimmed if it is an absolute http or https
+// URL. Other schemes are rejected so links can't run script when clicked.
+func NormalizeLinkURL(raw string) (string, bool) {
+	raw = strings.TrimSpace(raw)
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" {
+		return "", false
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return "", false
+	}
+	return raw, true
+}
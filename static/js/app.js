@@ -1,7 +1,8 @@
 var krizzyRealtime = {
     clientId: null,
     boardId: null,
-    eventSource: null
+    eventSource: null,
+    version: null
 };
 
 function getClientId() {
@@ -26,6 +27,11 @@ function getBoardId() {
     return container ? container.dataset.boardId : null;
 }
 
+function getBoardLayout() {
+    var container = document.getElementById('columns-container');
+    return container ? container.dataset.layout : null;
+}
+
 function getCurrentModalCardId() {
     var modalCard = document.querySelector('#modal-content [data-card-id]');
     return modalCard ? modalCard.dataset.cardId : null;
@@ -48,6 +54,13 @@ function refreshColumnsContainer(boardId) {
     });
 }
 
+function refreshBoardContent(boardId) {
+    htmx.ajax('GET', '/boards/' + boardId, {
+        target: '#board-content',
+        swap: 'innerHTML'
+    });
+}
+
 function refreshColumn(boardId, columnId) {
     var target = document.getElementById('column-' + columnId);
     if (!target) {
@@ -62,6 +75,13 @@ function refreshColumn(boardId, columnId) {
 }
 
 function refreshCard(boardId, cardId, fallbackColumnId) {
+    // Grouped by assignee a card can appear in several rows, and an assignee
+    // change moves it between them
+    if (getBoardLayout() === 'assignee') {
+        refreshColumnsContainer(boardId);
+        return;
+    }
+
     var target = document.getElementById('card-' + cardId);
     if (!target) {
         if (fallbackColumnId) {
@@ -98,53 +118,218 @@ function refreshPeopleModal(boardId) {
     });
 }
 
+// Events number themselves per board. A page that sees a number skipped
+// missed an event, so it reloads the board rather than patch it.
+function trackBoardVersion(version) {
+    var expected = krizzyRealtime.version === null ? version : krizzyRealtime.version + 1;
+    krizzyRealtime.version = version;
+    return version === expected;
+}
+
+function refreshWholeBoard(boardId) {
+    refreshBoardContent(boardId);
+    if (getCurrentModalCardId()) {
+        refreshOpenCardModal(boardId, getCurrentModalCardId());
+    }
+}
+
+function htmlToElement(html) {
+    var template = document.createElement('template');
+    template.innerHTML = html.trim();
+    return template.content.firstElementChild;
+}
+
+// Wire up an element rendered into an event like htmx does after a swap
+function processFragment(element) {
+    htmx.process(element);
+    initializeSortable();
+    applyCardFilter();
+}
+
+// Place a card rendered into an event at its cell and position. Returns
+// false when the page has no single place for it, and the caller fetches.
+function applyCardFragment(event) {
+    // Grouped by assignee a card can appear in several rows
+    if (!event.html || getBoardLayout() === 'assignee') {
+        return false;
+    }
+
+    var selector = '#columns-container .cards-container[data-column-id="' + event.column_id + '"]';
+    if (getBoardLayout() === 'swimlanes') {
+        selector += '[data-swimlane-id="' + (event.swimlane_id || 0) + '"]';
+    }
+    var cell = document.querySelector(selector);
+    var card = htmlToElement(event.html);
+    if (!cell || !card) {
+        return false;
+    }
+
+    var existing = document.getElementById('card-' + event.card_id);
+    if (existing) {
+        existing.remove();
+    }
+    var cards = cell.querySelectorAll(':scope > .card-item');
+    cell.insertBefore(card, cards[event.position || 0] || null);
+    processFragment(card);
+    return true;
+}
+
+function removeCard(cardId) {
+    if (getBoardLayout() === 'assignee') {
+        return false;
+    }
+
+    var card = document.getElementById('card-' + cardId);
+    if (card) {
+        card.remove();
+    }
+    return true;
+}
+
+// Column fragments only fit the plain column layout; with swimlanes a
+// column is split across rows
+function columnsRow() {
+    if (getBoardLayout() !== 'columns') {
+        return null;
+    }
+    return document.querySelector('#columns-container .columns-row');
+}
+
+function applyColumnFragment(event) {
+    var row = columnsRow();
+    var column = event.html ? htmlToElement(event.html) : null;
+    if (!row || !column) {
+        return false;
+    }
+
+    var existing = document.getElementById('column-' + event.column_id);
+    if (existing) {
+        existing.replaceWith(column);
+    } else {
+        var columns = row.querySelectorAll(':scope > [data-column-id]');
+        var next = columns[event.position || 0];
+        if (!next) {
+            next = columns.length ? columns[columns.length - 1].nextElementSibling : row.firstElementChild;
+        }
+        row.insertBefore(column, next);
+    }
+    processFragment(column);
+    return true;
+}
+
+function removeColumn(columnId) {
+    if (!columnsRow()) {
+        return false;
+    }
+
+    var column = document.getElementById('column-' + columnId);
+    if (column) {
+        column.remove();
+    }
+    return true;
+}
+
+function applyColumnOrder(columnIds) {
+    var row = columnsRow();
+    if (!row || !columnIds || row.querySelectorAll(':scope > [data-column-id]').length !== columnIds.length) {
+        return false;
+    }
+
+    var columns = columnIds.map(function(id) {
+        return document.getElementById('column-' + id);
+    });
+    if (columns.some(function(column) { return !column; })) {
+        return false;
+    }
+
+    // The add-column form stays last
+    var addColumn = row.querySelector(':scope > :not([data-column-id])');
+    columns.forEach(function(column) {
+        row.insertBefore(column, addColumn);
+    });
+    return true;
+}
+
 function handleBoardEvent(event) {
     var boardId = getBoardId();
     if (!boardId || String(event.board_id) !== String(boardId)) {
         return;
     }
 
+    // Own events count towards the version too
+    if (!trackBoardVersion(event.version)) {
+        refreshWholeBoard(boardId);
+        return;
+    }
+
     if (event.client_id && event.client_id === getClientId()) {
         return;
     }
 
     switch (event.type) {
+        case 'board.reset':
+            // A demo instance put the board back to its sample data
+            window.location.reload();
+            break;
         case 'column.created':
+        case 'column.updated':
+            if (!applyColumnFragment(event)) {
+                refreshColumnsContainer(boardId);
+            }
+            break;
         case 'column.deleted':
+            if (!removeColumn(event.column_id)) {
+                refreshColumnsContainer(boardId);
+            }
+            break;
         case 'column.reordered':
+            if (!applyColumnOrder(event.column_ids)) {
+                refreshColumnsContainer(boardId);
+            }
+            break;
+        case 'swimlane.updated':
             refreshColumnsContainer(boardId);
             break;
-        case 'column.updated':
-            if (event.column_id) {
-                refreshColumn(boardId, event.column_id);
+        case 'fields.updated':
+            // The filter bar lists the fields, so refresh it with the columns
+            refreshBoardContent(boardId);
+            if (getCurrentModalCardId()) {
+                refreshOpenCardModal(boardId, getCurrentModalCardId());
             }
             break;
+        case 'templates.updated':
+            // Templates are listed in every column's add-card area
+            refreshColumnsContainer(boardId);
+            break;
         case 'card.created':
+            if (!applyCardFragment(event) && event.column_id) {
+                refreshColumn(boardId, event.column_id);
+            }
+            break;
         case 'card.deleted':
-            if (event.column_id) {
+            if (!removeCard(event.card_id) && event.column_id) {
                 refreshColumn(boardId, event.column_id);
             }
             break;
         case 'card.updated':
+        case 'checklist.updated':
             if (event.card_id) {
-                refreshCard(boardId, event.card_id, event.column_id);
+                if (!applyCardFragment(event)) {
+                    refreshCard(boardId, event.card_id, event.column_id);
+                }
                 refreshOpenCardModal(boardId, event.card_id);
             }
             break;
         case 'card.moved':
-            if (event.from_column_id) {
-                refreshColumn(boardId, event.from_column_id);
-            }
-            if (event.to_column_id && event.to_column_id !== event.from_column_id) {
-                refreshColumn(boardId, event.to_column_id);
-            }
-            if (event.card_id) {
-                refreshOpenCardModal(boardId, event.card_id);
+            if (!applyCardFragment(event)) {
+                if (event.from_column_id) {
+                    refreshColumn(boardId, event.from_column_id);
+                }
+                if (event.to_column_id && event.to_column_id !== event.from_column_id) {
+                    refreshColumn(boardId, event.to_column_id);
+                }
             }
-            break;
-        case 'checklist.updated':
             if (event.card_id) {
-                refreshCard(boardId, event.card_id, event.column_id);
                 refreshOpenCardModal(boardId, event.card_id);
             }
             break;
@@ -163,6 +348,21 @@ function handleBoardEvent(event) {
     }
 }
 
+// A stream opens with the board's current version. After a reconnect a
+// different version means events were missed while the stream was down.
+function handleBoardVersion(message) {
+    var boardId = getBoardId();
+    if (!boardId || String(message.board_id) !== String(boardId)) {
+        return;
+    }
+
+    var missed = krizzyRealtime.version !== null && krizzyRealtime.version !== message.version;
+    krizzyRealtime.version = message.version;
+    if (missed) {
+        refreshWholeBoard(boardId);
+    }
+}
+
 function initializeRealtime() {
     var boardId = getBoardId();
 
@@ -184,7 +384,11 @@ function initializeRealtime() {
     }
 
     krizzyRealtime.boardId = boardId;
+    krizzyRealtime.version = null;
     krizzyRealtime.eventSource = new EventSource('/boards/' + boardId + '/events');
+    krizzyRealtime.eventSource.addEventListener('board-version', function(message) {
+        handleBoardVersion(JSON.parse(message.data));
+    });
     krizzyRealtime.eventSource.addEventListener('board-update', function(message) {
         handleBoardEvent(JSON.parse(message.data));
     });
@@ -200,7 +404,7 @@ function initializeRealtime() {
 // Show HTMX error responses as alerts
 document.addEventListener('htmx:responseError', function(event) {
     var elt = event.detail.elt;
-    if (elt && (elt.id === 'trello-import-form' || elt.closest('#trello-import-form'))) {
+    if (elt && (elt.id === 'board-import-form' || elt.closest('#board-import-form'))) {
         showImportFeedback('error', event.detail.xhr && event.detail.xhr.responseText ? event.detail.xhr.responseText : 'Import failed.');
         setImportFormDisabled(false);
         return;
@@ -220,15 +424,22 @@ document.addEventListener('htmx:configRequest', function(event) {
 document.addEventListener('DOMContentLoaded', function() {
     initializeSortable();
     initializeRealtime();
+    restoreCardFilterInputs();
+    applyCardFilter();
 
     toggleCreatePgFields();
     toggleImportPgFields();
 });
 
 // Re-initialize after HTMX swaps content
-document.addEventListener('htmx:afterSwap', function() {
+document.addEventListener('htmx:afterSwap', function(event) {
+    if (event.detail.target && event.detail.target.id === 'import-job') {
+        watchImportJob(event.detail.target);
+    }
     initializeSortable();
     initializeRealtime();
+    restoreCardFilterInputs();
+    applyCardFilter();
     toggleCreatePgFields();
     toggleImportPgFields();
 });
@@ -249,6 +460,10 @@ function toggleImportPgFields() {
     }
 }
 
+function isImportPreview(event) {
+    return !!(event && event.detail && event.detail.elt && event.detail.elt.dataset.importPreview);
+}
+
 function openImportModal() {
     var backdrop = document.getElementById('import-modal-backdrop');
     if (!backdrop) {
@@ -295,7 +510,13 @@ function showImportFeedback(state, message) {
 
     if (state === 'loading') {
         feedback.classList.add('border-go-blue', 'bg-dark-700', 'text-dark-100');
-        feedback.innerHTML = '<div class="flex items-center gap-3"><span class="inline-block h-4 w-4 animate-spin rounded-full border-2 border-go-blue border-t-transparent"></span><div><div class="font-medium">Importing...</div><div class="text-sm text-dark-300">This can take a moment for larger boards.</div></div></div>';
+        feedback.innerHTML = '<div class="flex items-center gap-3"><span class="inline-block h-4 w-4 animate-spin rounded-full border-2 border-go-blue border-t-transparent"></span><div><div class="font-medium">Uploading...</div><div class="text-sm text-dark-300">The import runs in the background once the file is read.</div></div></div>';
+        return;
+    }
+
+    if (state === 'cancelled') {
+        feedback.classList.add('border-go-blue', 'bg-dark-700', 'text-dark-100');
+        feedback.innerHTML = '<div class="font-medium">Import cancelled</div><div class="text-sm mt-1">' + escapeHtml(message || 'Nothing was imported.') + '</div>';
         return;
     }
 
@@ -309,23 +530,88 @@ function showImportFeedback(state, message) {
     feedback.innerHTML = '<div class="font-medium">Import failed</div><div class="text-sm mt-1">' + escapeHtml(message || 'Something went wrong while importing.') + '</div>';
 }
 
-function startImportFeedback() {
+function startImportFeedback(event) {
+    if (isImportPreview(event)) {
+        return;
+    }
     showImportFeedback('loading');
     setImportFormDisabled(true);
 }
 
 function finishImportFeedback(event) {
-    if (!event.detail.successful) {
+    if (!event.detail.successful || isImportPreview(event)) {
+        return;
+    }
+
+    var feedback = document.getElementById('import-feedback');
+    if (feedback) {
+        feedback.classList.add('hidden');
+    }
+}
+
+// Follow a background import started by the import form. Progress arrives
+// over server-sent events until the job finishes.
+function watchImportJob(container) {
+    var jobElement = container.querySelector('[data-import-job]');
+    if (!jobElement) {
         return;
     }
 
-    var form = document.getElementById('trello-import-form');
+    var source = new EventSource('/imports/' + encodeURIComponent(jobElement.dataset.importJob) + '/events');
+    source.addEventListener('import-progress', function(message) {
+        var job = JSON.parse(message.data);
+        updateImportProgress(jobElement, job);
+        if (job.status === 'running') {
+            return;
+        }
+        source.close();
+        finishImportJob(container, job);
+    });
+    source.onerror = function() {
+        // EventSource reconnects on its own unless the job is gone
+        if (source.readyState === EventSource.CLOSED) {
+            container.innerHTML = '';
+            setImportFormDisabled(false);
+            showImportFeedback('error', 'Lost track of the import. Check the boards list.');
+        }
+    };
+}
+
+function updateImportProgress(jobElement, job) {
+    var bar = jobElement.querySelector('[data-import-bar]');
+    if (bar) {
+        bar.style.width = job.progress + '%';
+    }
+    var status = jobElement.querySelector('[data-import-status]');
+    if (status) {
+        status.textContent = job.progress + '%';
+    }
+}
+
+function finishImportJob(container, job) {
+    container.innerHTML = '';
+    setImportFormDisabled(false);
+
+    if (job.status === 'cancelled') {
+        showImportFeedback('cancelled');
+        return;
+    }
+    if (job.status !== 'done') {
+        showImportFeedback('error', job.error);
+        return;
+    }
+
+    var form = document.getElementById('board-import-form');
     if (form) {
         form.reset();
     }
-    setImportFormDisabled(false);
+    var preview = document.getElementById('import-preview');
+    if (preview) {
+        preview.innerHTML = '';
+    }
     toggleImportPgFields();
-    showImportFeedback('success', 'Your Trello board is ready.');
+    showImportFeedback('success', 'Your board is ready.');
+    htmx.ajax('GET', '/', {target: '#boards-list', swap: 'innerHTML'});
 }
 
 function escapeHtml(value) {
@@ -345,17 +631,17 @@ window.startImportFeedback = startImportFeedback;
 window.finishImportFeedback = finishImportFeedback;
 
 function initializeSortable() {
-    var columnsContainer = document.getElementById('columns-container');
-    if (columnsContainer && !columnsContainer._sortable) {
-        columnsContainer._sortable = new Sortable(columnsContainer, {
+    var columnsRow = document.querySelector('#columns-container .columns-row');
+    if (columnsRow && !columnsRow._sortable) {
+        columnsRow._sortable = new Sortable(columnsRow, {
             animation: 150,
             draggable: '[data-column-id]',
             ghostClass: 'sortable-ghost',
             chosenClass: 'sortable-chosen',
             handle: '.column-header',
             onEnd: function() {
-                var boardId = columnsContainer.dataset.boardId;
-                var columnIds = Array.from(columnsContainer.querySelectorAll('[data-column-id]')).map(function(col) {
+                var boardId = getBoardId();
+                var columnIds = Array.from(columnsRow.querySelectorAll(':scope > [data-column-id]')).map(function(col) {
                     return col.dataset.columnId;
                 });
 
@@ -369,6 +655,11 @@ function initializeSortable() {
                     method: 'POST',
                     headers: withClientHeaders(),
                     body: formData
+                }).then(function() {
+                    // Swimlane rows lay their cells out in column order
+                    if (getBoardLayout() !== 'columns') {
+                        refreshColumnsContainer(boardId);
+                    }
                 });
             }
         });
@@ -388,17 +679,21 @@ function initializeSortable() {
                     var newColumnId = evt.to.dataset.columnId;
                     var newPosition = evt.newIndex;
                     var boardId = getBoardId();
+                    var payload = {
+                        column_id: parseInt(newColumnId, 10),
+                        position: newPosition,
+                        board_id: parseInt(boardId, 10)
+                    };
+                    if (evt.to.dataset.swimlaneId !== undefined) {
+                        payload.swimlane_id = parseInt(evt.to.dataset.swimlaneId, 10);
+                    }
 
                     fetch('/cards/' + cardId + '/move', {
                         method: 'POST',
                         headers: withClientHeaders({
                             'Content-Type': 'application/json'
                         }),
-                        body: JSON.stringify({
-                            column_id: parseInt(newColumnId, 10),
-                            position: newPosition,
-                            board_id: parseInt(boardId, 10)
-                        })
+                        body: JSON.stringify(payload)
                     }).then(function(response) {
                         if (!boardId) {
                             return;
@@ -432,8 +727,11 @@ function initializeSortable() {
                 chosenClass: 'sortable-chosen',
                 filter: 'input, button',
                 onEnd: function() {
+                    // Named checklists are separate containers; positions
+                    // run across all of a card's checklists
                     var cardId = container.dataset.cardId;
-                    var itemIds = Array.from(container.querySelectorAll('.checklist-item')).map(function(item) {
+                    var items = document.querySelectorAll('.checklist-container[data-card-id="' + cardId + '"] .checklist-item');
+                    var itemIds = Array.from(items).map(function(item) {
                         return item.dataset.itemId;
                     });
 
@@ -459,15 +757,130 @@ function initializeSortable() {
 
 // Refresh board when modal closes
 function closeModalAndRefresh(boardId) {
+    // Field changes also touch the filter bar, which sits outside the columns
+    var fieldsChanged = !!document.getElementById('fields-list');
     document.getElementById('modal-backdrop').classList.add('hidden');
     if (!boardId) {
         boardId = getBoardId();
     }
     if (boardId) {
-        refreshColumnsContainer(boardId);
+        if (fieldsChanged) {
+            refreshBoardContent(boardId);
+        } else {
+            refreshColumnsContainer(boardId);
+        }
     }
 }
 
+// Card filter. The filter is kept in the page URL (?q=&field=&value=) so it
+// survives reloads and re-renders of the board.
+function readCardFilter() {
+    var params = new URLSearchParams(window.location.search);
+    return {
+        q: params.get('q') || '',
+        field: params.get('field') || '',
+        value: params.get('value') || ''
+    };
+}
+
+function updateCardFilter() {
+    var params = new URLSearchParams(window.location.search);
+    [['q', 'card-filter-q'], ['field', 'card-filter-field'], ['value', 'card-filter-value']].forEach(function(pair) {
+        var input = document.getElementById(pair[1]);
+        if (input && input.value) {
+            params.set(pair[0], input.value);
+        } else {
+            params.delete(pair[0]);
+        }
+    });
+
+    var query = params.toString();
+    window.history.replaceState(null, '', window.location.pathname + (query ? '?' + query : ''));
+    applyCardFilter();
+}
+
+function restoreCardFilterInputs() {
+    var filter = readCardFilter();
+    [['card-filter-q', filter.q], ['card-filter-field', filter.field], ['card-filter-value', filter.value]].forEach(function(pair) {
+        var input = document.getElementById(pair[0]);
+        if (input && input.value !== pair[1]) {
+            input.value = pair[1];
+        }
+    });
+}
+
+// Matches the server's CardFilter, so an export keeps the cards the board
+// shows: the query searches data-search, which holds the card's title,
+// description and assignee names, and only multi-select values are lists.
+function cardMatchesFilter(card, filter, fieldType) {
+    if (filter.q && (card.dataset.search || '').toLowerCase().indexOf(filter.q.toLowerCase()) === -1) {
+        return false;
+    }
+    if (!filter.field) {
+        return true;
+    }
+
+    var fields = {};
+    try {
+        fields = JSON.parse(card.dataset.fields || '{}');
+    } catch (e) {
+        return false;
+    }
+
+    var raw = fields[filter.field];
+    if (raw === undefined) {
+        return false;
+    }
+    if (!filter.value) {
+        return true;
+    }
+
+    // Multi-select values are stored as JSON arrays
+    var values = [raw];
+    if (fieldType === 'multi_select') {
+        try {
+            var parsed = JSON.parse(raw);
+            values = Array.isArray(parsed) ? parsed : [];
+        } catch (e) {
+            values = [];
+        }
+    }
+
+    var needle = filter.value.toLowerCase();
+    return values.some(function(value) {
+        return String(value).toLowerCase().indexOf(needle) !== -1;
+    });
+}
+
+function applyCardFilter() {
+    var filter = readCardFilter();
+    var fieldType = '';
+    var select = document.getElementById('card-filter-field');
+    if (select && filter.field) {
+        var option = select.querySelector('option[value="' + CSS.escape(filter.field) + '"]');
+        fieldType = option ? option.dataset.type || '' : '';
+    }
+    document.querySelectorAll('#columns-container .card-item').forEach(function(card) {
+        card.classList.toggle('hidden', !cardMatchesFilter(card, filter, fieldType));
+    });
+}
+
+window.updateCardFilter = updateCardFilter;
+
+// Download the board with the active card filter applied
+function exportBoard(boardId, format) {
+    var filter = readCardFilter();
+    var params = new URLSearchParams({format: format});
+    ['q', 'field', 'value'].forEach(function(key) {
+        if (filter[key]) {
+            params.set(key, filter[key]);
+        }
+    });
+    window.location.href = '/boards/' + boardId + '/export?' + params.toString();
+}
+
+window.exportBoard = exportBoard;
+
 // Close connections modal and refresh the boards list (to update connection dropdown)
 function closeConnModal() {
     document.getElementById('conn-modal-backdrop').classList.add('hidden');
@@ -537,6 +950,31 @@ document.addEventListener('keydown', function(event) {
     }
 });
 
+function startRenameSwimlane(swimlaneId) {
+    var form = document.getElementById('rename-swimlane-form-' + swimlaneId);
+    var title = document.getElementById('swimlane-title-' + swimlaneId);
+    var input = document.getElementById('rename-swimlane-input-' + swimlaneId);
+    if (form && input) {
+        form.classList.remove('hidden');
+        if (title) {
+            title.classList.add('hidden');
+        }
+        input.focus();
+        input.select();
+    }
+}
+
+function cancelRenameSwimlane(swimlaneId) {
+    var form = document.getElementById('rename-swimlane-form-' + swimlaneId);
+    var title = document.getElementById('swimlane-title-' + swimlaneId);
+    if (form) {
+        form.classList.add('hidden');
+    }
+    if (title) {
+        title.classList.remove('hidden');
+    }
+}
+
 function startRenamePerson(personId) {
     var form = document.getElementById('rename-person-form-' + personId);
     var row = document.getElementById('person-row-' + personId);